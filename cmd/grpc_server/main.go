@@ -14,8 +14,39 @@ import (
 
 func main() {
 	configPath := flag.String("config", "config/config.yaml", "path to config file")
+	flag.Usage = func() {
+		out := flag.CommandLine.Output()
+		fmt.Fprintf(out, "usage: %s [-config path] [migrate up|down|status|redo|normalize-emails]\n", os.Args[0])
+		fmt.Fprintf(out, "       %s build-bloom <hibp-sha1-dump> <out> [fp_rate]\n", os.Args[0])
+		fmt.Fprintf(out, "       %s openapi [out]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
+	if flag.Arg(0) == "migrate" {
+		if err := app.Migrate(*configPath, flag.Args()[1:]); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "build-bloom" {
+		if err := app.BuildBloom(flag.Args()[1:]); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "openapi" {
+		if err := app.OpenAPI(flag.Args()[1:]); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	a, err := app.InitApp(*configPath)
 	if err != nil {
 		fmt.Println(err)
@@ -43,12 +74,12 @@ func main() {
 			close(done)
 		}()
 
-		// Ждем graceful N секунд, потом жестко останавливаем.
+		// Ждем graceful N секунд (плюс паузу на drain), потом жестко останавливаем.
 		const shutdownTimeout = 5 * time.Second
 		select {
 		case <-done:
 			// graceful ok
-		case <-time.After(shutdownTimeout):
+		case <-time.After(a.DrainDelay() + shutdownTimeout):
 			fmt.Println("graceful shutdown timeout, forcing stop")
 			a.Stop()
 		}
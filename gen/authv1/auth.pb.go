@@ -9,6 +9,7 @@ package authv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -74,9 +75,11 @@ func (x *RegisterRequest) GetPassword() string {
 }
 
 type LoginRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
-	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Email    string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Password string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	// выдать refresh token web-сессии (HTTP edge кладёт его в HttpOnly cookie)
+	WebSession    bool `protobuf:"varint,3,opt,name=web_session,json=webSession,proto3" json:"web_session,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -125,17 +128,79 @@ func (x *LoginRequest) GetPassword() string {
 	return ""
 }
 
-type AuthResponse struct {
+func (x *LoginRequest) GetWebSession() bool {
+	if x != nil {
+		return x.WebSession
+	}
+	return false
+}
+
+type ChangePasswordRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
-	ExpiresInSec  int64                  `protobuf:"varint,2,opt,name=expires_in_sec,json=expiresInSec,proto3" json:"expires_in_sec,omitempty"`
+	OldPassword   string                 `protobuf:"bytes,1,opt,name=old_password,json=oldPassword,proto3" json:"old_password,omitempty"`
+	NewPassword   string                 `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *ChangePasswordRequest) Reset() {
+	*x = ChangePasswordRequest{}
+	mi := &file_auth_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordRequest) ProtoMessage() {}
+
+func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordRequest.ProtoReflect.Descriptor instead.
+func (*ChangePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ChangePasswordRequest) GetOldPassword() string {
+	if x != nil {
+		return x.OldPassword
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+type AuthResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken  string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	ExpiresInSec int64                  `protobuf:"varint,2,opt,name=expires_in_sec,json=expiresInSec,proto3" json:"expires_in_sec,omitempty"`
+	// только для web_session
+	RefreshToken        string `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	RefreshExpiresInSec int64  `protobuf:"varint,4,opt,name=refresh_expires_in_sec,json=refreshExpiresInSec,proto3" json:"refresh_expires_in_sec,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
 func (x *AuthResponse) Reset() {
 	*x = AuthResponse{}
-	mi := &file_auth_proto_msgTypes[2]
+	mi := &file_auth_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -147,7 +212,7 @@ func (x *AuthResponse) String() string {
 func (*AuthResponse) ProtoMessage() {}
 
 func (x *AuthResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_auth_proto_msgTypes[2]
+	mi := &file_auth_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -160,7 +225,7 @@ func (x *AuthResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AuthResponse.ProtoReflect.Descriptor instead.
 func (*AuthResponse) Descriptor() ([]byte, []int) {
-	return file_auth_proto_rawDescGZIP(), []int{2}
+	return file_auth_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *AuthResponse) GetAccessToken() string {
@@ -177,6 +242,273 @@ func (x *AuthResponse) GetExpiresInSec() int64 {
 	return 0
 }
 
+func (x *AuthResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *AuthResponse) GetRefreshExpiresInSec() int64 {
+	if x != nil {
+		return x.RefreshExpiresInSec
+	}
+	return 0
+}
+
+type RefreshSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshSessionRequest) Reset() {
+	*x = RefreshSessionRequest{}
+	mi := &file_auth_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshSessionRequest) ProtoMessage() {}
+
+func (x *RefreshSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshSessionRequest.ProtoReflect.Descriptor instead.
+func (*RefreshSessionRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RefreshSessionRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type EndSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EndSessionRequest) Reset() {
+	*x = EndSessionRequest{}
+	mi := &file_auth_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EndSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndSessionRequest) ProtoMessage() {}
+
+func (x *EndSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndSessionRequest.ProtoReflect.Descriptor instead.
+func (*EndSessionRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *EndSessionRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type EndSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EndSessionResponse) Reset() {
+	*x = EndSessionResponse{}
+	mi := &file_auth_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EndSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndSessionResponse) ProtoMessage() {}
+
+func (x *EndSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndSessionResponse.ProtoReflect.Descriptor instead.
+func (*EndSessionResponse) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *EndSessionResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+// Владение вторым аккаунтом подтверждается одним из полей.
+type MergeAccountsRequest struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	SecondaryAccessToken string                 `protobuf:"bytes,1,opt,name=secondary_access_token,json=secondaryAccessToken,proto3" json:"secondary_access_token,omitempty"` // JWT второго аккаунта
+	SecondaryLinkCode    string                 `protobuf:"bytes,2,opt,name=secondary_link_code,json=secondaryLinkCode,proto3" json:"secondary_link_code,omitempty"`          // или код из CreateTelegramLinkCode, выпущенный вторым аккаунтом
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *MergeAccountsRequest) Reset() {
+	*x = MergeAccountsRequest{}
+	mi := &file_auth_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeAccountsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeAccountsRequest) ProtoMessage() {}
+
+func (x *MergeAccountsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeAccountsRequest.ProtoReflect.Descriptor instead.
+func (*MergeAccountsRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MergeAccountsRequest) GetSecondaryAccessToken() string {
+	if x != nil {
+		return x.SecondaryAccessToken
+	}
+	return ""
+}
+
+func (x *MergeAccountsRequest) GetSecondaryLinkCode() string {
+	if x != nil {
+		return x.SecondaryLinkCode
+	}
+	return ""
+}
+
+type MergeAccountsResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	MergedUserId    int32                  `protobuf:"varint,1,opt,name=merged_user_id,json=mergedUserId,proto3" json:"merged_user_id,omitempty"` // удалённый аккаунт
+	MovedIdentities int64                  `protobuf:"varint,2,opt,name=moved_identities,json=movedIdentities,proto3" json:"moved_identities,omitempty"`
+	MovedSessions   int64                  `protobuf:"varint,3,opt,name=moved_sessions,json=movedSessions,proto3" json:"moved_sessions,omitempty"`
+	MovedLogins     int64                  `protobuf:"varint,4,opt,name=moved_logins,json=movedLogins,proto3" json:"moved_logins,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MergeAccountsResponse) Reset() {
+	*x = MergeAccountsResponse{}
+	mi := &file_auth_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeAccountsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeAccountsResponse) ProtoMessage() {}
+
+func (x *MergeAccountsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeAccountsResponse.ProtoReflect.Descriptor instead.
+func (*MergeAccountsResponse) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *MergeAccountsResponse) GetMergedUserId() int32 {
+	if x != nil {
+		return x.MergedUserId
+	}
+	return 0
+}
+
+func (x *MergeAccountsResponse) GetMovedIdentities() int64 {
+	if x != nil {
+		return x.MovedIdentities
+	}
+	return 0
+}
+
+func (x *MergeAccountsResponse) GetMovedSessions() int64 {
+	if x != nil {
+		return x.MovedSessions
+	}
+	return 0
+}
+
+func (x *MergeAccountsResponse) GetMovedLogins() int64 {
+	if x != nil {
+		return x.MovedLogins
+	}
+	return 0
+}
+
 type CreateTelegramLinkCodeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -185,7 +517,7 @@ type CreateTelegramLinkCodeRequest struct {
 
 func (x *CreateTelegramLinkCodeRequest) Reset() {
 	*x = CreateTelegramLinkCodeRequest{}
-	mi := &file_auth_proto_msgTypes[3]
+	mi := &file_auth_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -197,7 +529,7 @@ func (x *CreateTelegramLinkCodeRequest) String() string {
 func (*CreateTelegramLinkCodeRequest) ProtoMessage() {}
 
 func (x *CreateTelegramLinkCodeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_auth_proto_msgTypes[3]
+	mi := &file_auth_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -210,7 +542,7 @@ func (x *CreateTelegramLinkCodeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateTelegramLinkCodeRequest.ProtoReflect.Descriptor instead.
 func (*CreateTelegramLinkCodeRequest) Descriptor() ([]byte, []int) {
-	return file_auth_proto_rawDescGZIP(), []int{3}
+	return file_auth_proto_rawDescGZIP(), []int{9}
 }
 
 type CreateTelegramLinkCodeResponse struct {
@@ -223,7 +555,7 @@ type CreateTelegramLinkCodeResponse struct {
 
 func (x *CreateTelegramLinkCodeResponse) Reset() {
 	*x = CreateTelegramLinkCodeResponse{}
-	mi := &file_auth_proto_msgTypes[4]
+	mi := &file_auth_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -235,7 +567,7 @@ func (x *CreateTelegramLinkCodeResponse) String() string {
 func (*CreateTelegramLinkCodeResponse) ProtoMessage() {}
 
 func (x *CreateTelegramLinkCodeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_auth_proto_msgTypes[4]
+	mi := &file_auth_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -248,7 +580,7 @@ func (x *CreateTelegramLinkCodeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateTelegramLinkCodeResponse.ProtoReflect.Descriptor instead.
 func (*CreateTelegramLinkCodeResponse) Descriptor() ([]byte, []int) {
-	return file_auth_proto_rawDescGZIP(), []int{4}
+	return file_auth_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *CreateTelegramLinkCodeResponse) GetCode() string {
@@ -273,13 +605,15 @@ type LinkTelegramRequest struct {
 	Username       string                 `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
 	FirstName      string                 `protobuf:"bytes,5,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
 	LastName       string                 `protobuf:"bytes,6,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// language_code пользователя Telegram: на нём отдаются тексты ошибок (если нет x-locale)
+	LanguageCode  string `protobuf:"bytes,7,opt,name=language_code,json=languageCode,proto3" json:"language_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *LinkTelegramRequest) Reset() {
 	*x = LinkTelegramRequest{}
-	mi := &file_auth_proto_msgTypes[5]
+	mi := &file_auth_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -291,7 +625,7 @@ func (x *LinkTelegramRequest) String() string {
 func (*LinkTelegramRequest) ProtoMessage() {}
 
 func (x *LinkTelegramRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_auth_proto_msgTypes[5]
+	mi := &file_auth_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -304,7 +638,7 @@ func (x *LinkTelegramRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LinkTelegramRequest.ProtoReflect.Descriptor instead.
 func (*LinkTelegramRequest) Descriptor() ([]byte, []int) {
-	return file_auth_proto_rawDescGZIP(), []int{5}
+	return file_auth_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *LinkTelegramRequest) GetCode() string {
@@ -349,6 +683,13 @@ func (x *LinkTelegramRequest) GetLastName() string {
 	return ""
 }
 
+func (x *LinkTelegramRequest) GetLanguageCode() string {
+	if x != nil {
+		return x.LanguageCode
+	}
+	return ""
+}
+
 type LinkTelegramResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
@@ -358,7 +699,7 @@ type LinkTelegramResponse struct {
 
 func (x *LinkTelegramResponse) Reset() {
 	*x = LinkTelegramResponse{}
-	mi := &file_auth_proto_msgTypes[6]
+	mi := &file_auth_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -370,7 +711,7 @@ func (x *LinkTelegramResponse) String() string {
 func (*LinkTelegramResponse) ProtoMessage() {}
 
 func (x *LinkTelegramResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_auth_proto_msgTypes[6]
+	mi := &file_auth_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -383,7 +724,7 @@ func (x *LinkTelegramResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LinkTelegramResponse.ProtoReflect.Descriptor instead.
 func (*LinkTelegramResponse) Descriptor() ([]byte, []int) {
-	return file_auth_proto_rawDescGZIP(), []int{6}
+	return file_auth_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *LinkTelegramResponse) GetOk() bool {
@@ -400,13 +741,15 @@ type TelegramLoginRequest struct {
 	Username       string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
 	FirstName      string                 `protobuf:"bytes,4,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
 	LastName       string                 `protobuf:"bytes,5,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// language_code пользователя Telegram: на нём отдаются тексты ошибок (если нет x-locale)
+	LanguageCode  string `protobuf:"bytes,6,opt,name=language_code,json=languageCode,proto3" json:"language_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *TelegramLoginRequest) Reset() {
 	*x = TelegramLoginRequest{}
-	mi := &file_auth_proto_msgTypes[7]
+	mi := &file_auth_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -418,7 +761,7 @@ func (x *TelegramLoginRequest) String() string {
 func (*TelegramLoginRequest) ProtoMessage() {}
 
 func (x *TelegramLoginRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_auth_proto_msgTypes[7]
+	mi := &file_auth_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -431,7 +774,7 @@ func (x *TelegramLoginRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TelegramLoginRequest.ProtoReflect.Descriptor instead.
 func (*TelegramLoginRequest) Descriptor() ([]byte, []int) {
-	return file_auth_proto_rawDescGZIP(), []int{7}
+	return file_auth_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *TelegramLoginRequest) GetTelegramUserId() int64 {
@@ -469,48 +812,456 @@ func (x *TelegramLoginRequest) GetLastName() string {
 	return ""
 }
 
+func (x *TelegramLoginRequest) GetLanguageCode() string {
+	if x != nil {
+		return x.LanguageCode
+	}
+	return ""
+}
+
+type LoginRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Method        string                 `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"` // 'password' | 'telegram'
+	Ip            string                 `protobuf:"bytes,3,opt,name=ip,proto3" json:"ip,omitempty"`
+	UserAgent     string                 `protobuf:"bytes,4,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	Device        string                 `protobuf:"bytes,5,opt,name=device,proto3" json:"device,omitempty"` // 'Chrome, Windows'
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginRecord) Reset() {
+	*x = LoginRecord{}
+	mi := &file_auth_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginRecord) ProtoMessage() {}
+
+func (x *LoginRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginRecord.ProtoReflect.Descriptor instead.
+func (*LoginRecord) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *LoginRecord) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *LoginRecord) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *LoginRecord) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *LoginRecord) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *LoginRecord) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
+func (x *LoginRecord) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListLoginHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"` // по умолчанию 50, максимум 200
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLoginHistoryRequest) Reset() {
+	*x = ListLoginHistoryRequest{}
+	mi := &file_auth_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLoginHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLoginHistoryRequest) ProtoMessage() {}
+
+func (x *ListLoginHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLoginHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ListLoginHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListLoginHistoryRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListLoginHistoryRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListLoginHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Logins        []*LoginRecord         `protobuf:"bytes,1,rep,name=logins,proto3" json:"logins,omitempty"` // от новых к старым
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLoginHistoryResponse) Reset() {
+	*x = ListLoginHistoryResponse{}
+	mi := &file_auth_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLoginHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLoginHistoryResponse) ProtoMessage() {}
+
+func (x *ListLoginHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLoginHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ListLoginHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListLoginHistoryResponse) GetLogins() []*LoginRecord {
+	if x != nil {
+		return x.Logins
+	}
+	return nil
+}
+
+func (x *ListLoginHistoryResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type BotMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ChatId        int64                  `protobuf:"varint,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Text          string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BotMessage) Reset() {
+	*x = BotMessage{}
+	mi := &file_auth_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BotMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BotMessage) ProtoMessage() {}
+
+func (x *BotMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BotMessage.ProtoReflect.Descriptor instead.
+func (*BotMessage) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BotMessage) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *BotMessage) GetChatId() int64 {
+	if x != nil {
+		return x.ChatId
+	}
+	return 0
+}
+
+func (x *BotMessage) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *BotMessage) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type PullBotMessagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"` // по умолчанию 50, максимум 200
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PullBotMessagesRequest) Reset() {
+	*x = PullBotMessagesRequest{}
+	mi := &file_auth_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullBotMessagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullBotMessagesRequest) ProtoMessage() {}
+
+func (x *PullBotMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullBotMessagesRequest.ProtoReflect.Descriptor instead.
+func (*PullBotMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PullBotMessagesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type PullBotMessagesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*BotMessage          `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PullBotMessagesResponse) Reset() {
+	*x = PullBotMessagesResponse{}
+	mi := &file_auth_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullBotMessagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullBotMessagesResponse) ProtoMessage() {}
+
+func (x *PullBotMessagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullBotMessagesResponse.ProtoReflect.Descriptor instead.
+func (*PullBotMessagesResponse) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *PullBotMessagesResponse) GetMessages() []*BotMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
 var File_auth_proto protoreflect.FileDescriptor
 
 const file_auth_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"auth.proto\x12\x10bottrade.auth.v1\"C\n" +
+	"auth.proto\x12\x10bottrade.auth.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\roptions.proto\"I\n" +
 	"\x0fRegisterRequest\x12\x14\n" +
-	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"@\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12 \n" +
+	"\bpassword\x18\x02 \x01(\tB\x04\x90\xb5\x18\x01R\bpassword\"g\n" +
 	"\fLoginRequest\x12\x14\n" +
-	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"W\n" +
-	"\fAuthResponse\x12!\n" +
-	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12$\n" +
-	"\x0eexpires_in_sec\x18\x02 \x01(\x03R\fexpiresInSec\"\x1f\n" +
-	"\x1dCreateTelegramLinkCodeRequest\"Z\n" +
-	"\x1eCreateTelegramLinkCodeResponse\x12\x12\n" +
-	"\x04code\x18\x01 \x01(\tR\x04code\x12$\n" +
-	"\x0eexpires_in_sec\x18\x02 \x01(\x03R\fexpiresInSec\"\xc4\x01\n" +
-	"\x13LinkTelegramRequest\x12\x12\n" +
-	"\x04code\x18\x01 \x01(\tR\x04code\x12(\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12 \n" +
+	"\bpassword\x18\x02 \x01(\tB\x04\x90\xb5\x18\x01R\bpassword\x12\x1f\n" +
+	"\vweb_session\x18\x03 \x01(\bR\n" +
+	"webSession\"i\n" +
+	"\x15ChangePasswordRequest\x12'\n" +
+	"\fold_password\x18\x01 \x01(\tB\x04\x90\xb5\x18\x01R\voldPassword\x12'\n" +
+	"\fnew_password\x18\x02 \x01(\tB\x04\x90\xb5\x18\x01R\vnewPassword\"\xbd\x01\n" +
+	"\fAuthResponse\x12'\n" +
+	"\faccess_token\x18\x01 \x01(\tB\x04\x90\xb5\x18\x01R\vaccessToken\x12$\n" +
+	"\x0eexpires_in_sec\x18\x02 \x01(\x03R\fexpiresInSec\x12)\n" +
+	"\rrefresh_token\x18\x03 \x01(\tB\x04\x90\xb5\x18\x01R\frefreshToken\x123\n" +
+	"\x16refresh_expires_in_sec\x18\x04 \x01(\x03R\x13refreshExpiresInSec\"B\n" +
+	"\x15RefreshSessionRequest\x12)\n" +
+	"\rrefresh_token\x18\x01 \x01(\tB\x04\x90\xb5\x18\x01R\frefreshToken\">\n" +
+	"\x11EndSessionRequest\x12)\n" +
+	"\rrefresh_token\x18\x01 \x01(\tB\x04\x90\xb5\x18\x01R\frefreshToken\"$\n" +
+	"\x12EndSessionResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\"\x88\x01\n" +
+	"\x14MergeAccountsRequest\x12:\n" +
+	"\x16secondary_access_token\x18\x01 \x01(\tB\x04\x90\xb5\x18\x01R\x14secondaryAccessToken\x124\n" +
+	"\x13secondary_link_code\x18\x02 \x01(\tB\x04\x90\xb5\x18\x01R\x11secondaryLinkCode\"\xb2\x01\n" +
+	"\x15MergeAccountsResponse\x12$\n" +
+	"\x0emerged_user_id\x18\x01 \x01(\x05R\fmergedUserId\x12)\n" +
+	"\x10moved_identities\x18\x02 \x01(\x03R\x0fmovedIdentities\x12%\n" +
+	"\x0emoved_sessions\x18\x03 \x01(\x03R\rmovedSessions\x12!\n" +
+	"\fmoved_logins\x18\x04 \x01(\x03R\vmovedLogins\"\x1f\n" +
+	"\x1dCreateTelegramLinkCodeRequest\"`\n" +
+	"\x1eCreateTelegramLinkCodeResponse\x12\x18\n" +
+	"\x04code\x18\x01 \x01(\tB\x04\x90\xb5\x18\x01R\x04code\x12$\n" +
+	"\x0eexpires_in_sec\x18\x02 \x01(\x03R\fexpiresInSec\"\xef\x01\n" +
+	"\x13LinkTelegramRequest\x12\x18\n" +
+	"\x04code\x18\x01 \x01(\tB\x04\x90\xb5\x18\x01R\x04code\x12(\n" +
 	"\x10telegram_user_id\x18\x02 \x01(\x03R\x0etelegramUserId\x12\x17\n" +
 	"\achat_id\x18\x03 \x01(\x03R\x06chatId\x12\x1a\n" +
 	"\busername\x18\x04 \x01(\tR\busername\x12\x1d\n" +
 	"\n" +
 	"first_name\x18\x05 \x01(\tR\tfirstName\x12\x1b\n" +
-	"\tlast_name\x18\x06 \x01(\tR\blastName\"&\n" +
+	"\tlast_name\x18\x06 \x01(\tR\blastName\x12#\n" +
+	"\rlanguage_code\x18\a \x01(\tR\flanguageCode\"&\n" +
 	"\x14LinkTelegramResponse\x12\x0e\n" +
-	"\x02ok\x18\x01 \x01(\bR\x02ok\"\xb1\x01\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\"\xd6\x01\n" +
 	"\x14TelegramLoginRequest\x12(\n" +
 	"\x10telegram_user_id\x18\x01 \x01(\x03R\x0etelegramUserId\x12\x17\n" +
 	"\achat_id\x18\x02 \x01(\x03R\x06chatId\x12\x1a\n" +
 	"\busername\x18\x03 \x01(\tR\busername\x12\x1d\n" +
 	"\n" +
 	"first_name\x18\x04 \x01(\tR\tfirstName\x12\x1b\n" +
-	"\tlast_name\x18\x05 \x01(\tR\blastName2\xd9\x03\n" +
-	"\vAuthService\x12M\n" +
-	"\bRegister\x12!.bottrade.auth.v1.RegisterRequest\x1a\x1e.bottrade.auth.v1.AuthResponse\x12G\n" +
-	"\x05Login\x12\x1e.bottrade.auth.v1.LoginRequest\x1a\x1e.bottrade.auth.v1.AuthResponse\x12{\n" +
-	"\x16CreateTelegramLinkCode\x12/.bottrade.auth.v1.CreateTelegramLinkCodeRequest\x1a0.bottrade.auth.v1.CreateTelegramLinkCodeResponse\x12]\n" +
-	"\fLinkTelegram\x12%.bottrade.auth.v1.LinkTelegramRequest\x1a&.bottrade.auth.v1.LinkTelegramResponse\x12V\n" +
-	"\fTelegramAuth\x12&.bottrade.auth.v1.TelegramLoginRequest\x1a\x1e.bottrade.auth.v1.AuthResponseB?Z=github.com/IvanOplesnin/BotTradeService.git/gen/authv1;authv1b\x06proto3"
+	"\tlast_name\x18\x05 \x01(\tR\blastName\x12#\n" +
+	"\rlanguage_code\x18\x06 \x01(\tR\flanguageCode\"\xb7\x01\n" +
+	"\vLoginRecord\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12\x0e\n" +
+	"\x02ip\x18\x03 \x01(\tR\x02ip\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x04 \x01(\tR\tuserAgent\x12\x16\n" +
+	"\x06device\x18\x05 \x01(\tR\x06device\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"U\n" +
+	"\x17ListLoginHistoryRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\"y\n" +
+	"\x18ListLoginHistoryResponse\x125\n" +
+	"\x06logins\x18\x01 \x03(\v2\x1d.bottrade.auth.v1.LoginRecordR\x06logins\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x84\x01\n" +
+	"\n" +
+	"BotMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\achat_id\x18\x02 \x01(\x03R\x06chatId\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\".\n" +
+	"\x16PullBotMessagesRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\"S\n" +
+	"\x17PullBotMessagesResponse\x128\n" +
+	"\bmessages\x18\x01 \x03(\v2\x1c.bottrade.auth.v1.BotMessageR\bmessages2\xe0\b\n" +
+	"\vAuthService\x12S\n" +
+	"\bRegister\x12!.bottrade.auth.v1.RegisterRequest\x1a\x1e.bottrade.auth.v1.AuthResponse\"\x04\x88\xb5\x18\x01\x12M\n" +
+	"\x05Login\x12\x1e.bottrade.auth.v1.LoginRequest\x1a\x1e.bottrade.auth.v1.AuthResponse\"\x04\x88\xb5\x18\x01\x12_\n" +
+	"\x0eRefreshSession\x12'.bottrade.auth.v1.RefreshSessionRequest\x1a\x1e.bottrade.auth.v1.AuthResponse\"\x04\x88\xb5\x18\x01\x12]\n" +
+	"\n" +
+	"EndSession\x12#.bottrade.auth.v1.EndSessionRequest\x1a$.bottrade.auth.v1.EndSessionResponse\"\x04\x88\xb5\x18\x01\x12_\n" +
+	"\x0eChangePassword\x12'.bottrade.auth.v1.ChangePasswordRequest\x1a\x1e.bottrade.auth.v1.AuthResponse\"\x04\x88\xb5\x18\x03\x12o\n" +
+	"\x10ListLoginHistory\x12).bottrade.auth.v1.ListLoginHistoryRequest\x1a*.bottrade.auth.v1.ListLoginHistoryResponse\"\x04\x88\xb5\x18\x03\x12f\n" +
+	"\rMergeAccounts\x12&.bottrade.auth.v1.MergeAccountsRequest\x1a'.bottrade.auth.v1.MergeAccountsResponse\"\x04\x88\xb5\x18\x03\x12\x81\x01\n" +
+	"\x16CreateTelegramLinkCode\x12/.bottrade.auth.v1.CreateTelegramLinkCodeRequest\x1a0.bottrade.auth.v1.CreateTelegramLinkCodeResponse\"\x04\x88\xb5\x18\x03\x12c\n" +
+	"\fLinkTelegram\x12%.bottrade.auth.v1.LinkTelegramRequest\x1a&.bottrade.auth.v1.LinkTelegramResponse\"\x04\x88\xb5\x18\x02\x12\\\n" +
+	"\fTelegramAuth\x12&.bottrade.auth.v1.TelegramLoginRequest\x1a\x1e.bottrade.auth.v1.AuthResponse\"\x04\x88\xb5\x18\x02\x12l\n" +
+	"\x0fPullBotMessages\x12(.bottrade.auth.v1.PullBotMessagesRequest\x1a).bottrade.auth.v1.PullBotMessagesResponse\"\x04\x88\xb5\x18\x02B?Z=github.com/IvanOplesnin/BotTradeService.git/gen/authv1;authv1b\x06proto3"
 
 var (
 	file_auth_proto_rawDescOnce sync.Once
@@ -524,33 +1275,62 @@ func file_auth_proto_rawDescGZIP() []byte {
 	return file_auth_proto_rawDescData
 }
 
-var file_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_auth_proto_goTypes = []any{
 	(*RegisterRequest)(nil),                // 0: bottrade.auth.v1.RegisterRequest
 	(*LoginRequest)(nil),                   // 1: bottrade.auth.v1.LoginRequest
-	(*AuthResponse)(nil),                   // 2: bottrade.auth.v1.AuthResponse
-	(*CreateTelegramLinkCodeRequest)(nil),  // 3: bottrade.auth.v1.CreateTelegramLinkCodeRequest
-	(*CreateTelegramLinkCodeResponse)(nil), // 4: bottrade.auth.v1.CreateTelegramLinkCodeResponse
-	(*LinkTelegramRequest)(nil),            // 5: bottrade.auth.v1.LinkTelegramRequest
-	(*LinkTelegramResponse)(nil),           // 6: bottrade.auth.v1.LinkTelegramResponse
-	(*TelegramLoginRequest)(nil),           // 7: bottrade.auth.v1.TelegramLoginRequest
+	(*ChangePasswordRequest)(nil),          // 2: bottrade.auth.v1.ChangePasswordRequest
+	(*AuthResponse)(nil),                   // 3: bottrade.auth.v1.AuthResponse
+	(*RefreshSessionRequest)(nil),          // 4: bottrade.auth.v1.RefreshSessionRequest
+	(*EndSessionRequest)(nil),              // 5: bottrade.auth.v1.EndSessionRequest
+	(*EndSessionResponse)(nil),             // 6: bottrade.auth.v1.EndSessionResponse
+	(*MergeAccountsRequest)(nil),           // 7: bottrade.auth.v1.MergeAccountsRequest
+	(*MergeAccountsResponse)(nil),          // 8: bottrade.auth.v1.MergeAccountsResponse
+	(*CreateTelegramLinkCodeRequest)(nil),  // 9: bottrade.auth.v1.CreateTelegramLinkCodeRequest
+	(*CreateTelegramLinkCodeResponse)(nil), // 10: bottrade.auth.v1.CreateTelegramLinkCodeResponse
+	(*LinkTelegramRequest)(nil),            // 11: bottrade.auth.v1.LinkTelegramRequest
+	(*LinkTelegramResponse)(nil),           // 12: bottrade.auth.v1.LinkTelegramResponse
+	(*TelegramLoginRequest)(nil),           // 13: bottrade.auth.v1.TelegramLoginRequest
+	(*LoginRecord)(nil),                    // 14: bottrade.auth.v1.LoginRecord
+	(*ListLoginHistoryRequest)(nil),        // 15: bottrade.auth.v1.ListLoginHistoryRequest
+	(*ListLoginHistoryResponse)(nil),       // 16: bottrade.auth.v1.ListLoginHistoryResponse
+	(*BotMessage)(nil),                     // 17: bottrade.auth.v1.BotMessage
+	(*PullBotMessagesRequest)(nil),         // 18: bottrade.auth.v1.PullBotMessagesRequest
+	(*PullBotMessagesResponse)(nil),        // 19: bottrade.auth.v1.PullBotMessagesResponse
+	(*timestamppb.Timestamp)(nil),          // 20: google.protobuf.Timestamp
 }
 var file_auth_proto_depIdxs = []int32{
-	0, // 0: bottrade.auth.v1.AuthService.Register:input_type -> bottrade.auth.v1.RegisterRequest
-	1, // 1: bottrade.auth.v1.AuthService.Login:input_type -> bottrade.auth.v1.LoginRequest
-	3, // 2: bottrade.auth.v1.AuthService.CreateTelegramLinkCode:input_type -> bottrade.auth.v1.CreateTelegramLinkCodeRequest
-	5, // 3: bottrade.auth.v1.AuthService.LinkTelegram:input_type -> bottrade.auth.v1.LinkTelegramRequest
-	7, // 4: bottrade.auth.v1.AuthService.TelegramAuth:input_type -> bottrade.auth.v1.TelegramLoginRequest
-	2, // 5: bottrade.auth.v1.AuthService.Register:output_type -> bottrade.auth.v1.AuthResponse
-	2, // 6: bottrade.auth.v1.AuthService.Login:output_type -> bottrade.auth.v1.AuthResponse
-	4, // 7: bottrade.auth.v1.AuthService.CreateTelegramLinkCode:output_type -> bottrade.auth.v1.CreateTelegramLinkCodeResponse
-	6, // 8: bottrade.auth.v1.AuthService.LinkTelegram:output_type -> bottrade.auth.v1.LinkTelegramResponse
-	2, // 9: bottrade.auth.v1.AuthService.TelegramAuth:output_type -> bottrade.auth.v1.AuthResponse
-	5, // [5:10] is the sub-list for method output_type
-	0, // [0:5] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	20, // 0: bottrade.auth.v1.LoginRecord.created_at:type_name -> google.protobuf.Timestamp
+	14, // 1: bottrade.auth.v1.ListLoginHistoryResponse.logins:type_name -> bottrade.auth.v1.LoginRecord
+	20, // 2: bottrade.auth.v1.BotMessage.created_at:type_name -> google.protobuf.Timestamp
+	17, // 3: bottrade.auth.v1.PullBotMessagesResponse.messages:type_name -> bottrade.auth.v1.BotMessage
+	0,  // 4: bottrade.auth.v1.AuthService.Register:input_type -> bottrade.auth.v1.RegisterRequest
+	1,  // 5: bottrade.auth.v1.AuthService.Login:input_type -> bottrade.auth.v1.LoginRequest
+	4,  // 6: bottrade.auth.v1.AuthService.RefreshSession:input_type -> bottrade.auth.v1.RefreshSessionRequest
+	5,  // 7: bottrade.auth.v1.AuthService.EndSession:input_type -> bottrade.auth.v1.EndSessionRequest
+	2,  // 8: bottrade.auth.v1.AuthService.ChangePassword:input_type -> bottrade.auth.v1.ChangePasswordRequest
+	15, // 9: bottrade.auth.v1.AuthService.ListLoginHistory:input_type -> bottrade.auth.v1.ListLoginHistoryRequest
+	7,  // 10: bottrade.auth.v1.AuthService.MergeAccounts:input_type -> bottrade.auth.v1.MergeAccountsRequest
+	9,  // 11: bottrade.auth.v1.AuthService.CreateTelegramLinkCode:input_type -> bottrade.auth.v1.CreateTelegramLinkCodeRequest
+	11, // 12: bottrade.auth.v1.AuthService.LinkTelegram:input_type -> bottrade.auth.v1.LinkTelegramRequest
+	13, // 13: bottrade.auth.v1.AuthService.TelegramAuth:input_type -> bottrade.auth.v1.TelegramLoginRequest
+	18, // 14: bottrade.auth.v1.AuthService.PullBotMessages:input_type -> bottrade.auth.v1.PullBotMessagesRequest
+	3,  // 15: bottrade.auth.v1.AuthService.Register:output_type -> bottrade.auth.v1.AuthResponse
+	3,  // 16: bottrade.auth.v1.AuthService.Login:output_type -> bottrade.auth.v1.AuthResponse
+	3,  // 17: bottrade.auth.v1.AuthService.RefreshSession:output_type -> bottrade.auth.v1.AuthResponse
+	6,  // 18: bottrade.auth.v1.AuthService.EndSession:output_type -> bottrade.auth.v1.EndSessionResponse
+	3,  // 19: bottrade.auth.v1.AuthService.ChangePassword:output_type -> bottrade.auth.v1.AuthResponse
+	16, // 20: bottrade.auth.v1.AuthService.ListLoginHistory:output_type -> bottrade.auth.v1.ListLoginHistoryResponse
+	8,  // 21: bottrade.auth.v1.AuthService.MergeAccounts:output_type -> bottrade.auth.v1.MergeAccountsResponse
+	10, // 22: bottrade.auth.v1.AuthService.CreateTelegramLinkCode:output_type -> bottrade.auth.v1.CreateTelegramLinkCodeResponse
+	12, // 23: bottrade.auth.v1.AuthService.LinkTelegram:output_type -> bottrade.auth.v1.LinkTelegramResponse
+	3,  // 24: bottrade.auth.v1.AuthService.TelegramAuth:output_type -> bottrade.auth.v1.AuthResponse
+	19, // 25: bottrade.auth.v1.AuthService.PullBotMessages:output_type -> bottrade.auth.v1.PullBotMessagesResponse
+	15, // [15:26] is the sub-list for method output_type
+	4,  // [4:15] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_auth_proto_init() }
@@ -558,13 +1338,14 @@ func file_auth_proto_init() {
 	if File_auth_proto != nil {
 		return
 	}
+	file_options_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_auth_proto_rawDesc), len(file_auth_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -21,9 +21,15 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	AuthService_Register_FullMethodName               = "/bottrade.auth.v1.AuthService/Register"
 	AuthService_Login_FullMethodName                  = "/bottrade.auth.v1.AuthService/Login"
+	AuthService_RefreshSession_FullMethodName         = "/bottrade.auth.v1.AuthService/RefreshSession"
+	AuthService_EndSession_FullMethodName             = "/bottrade.auth.v1.AuthService/EndSession"
+	AuthService_ChangePassword_FullMethodName         = "/bottrade.auth.v1.AuthService/ChangePassword"
+	AuthService_ListLoginHistory_FullMethodName       = "/bottrade.auth.v1.AuthService/ListLoginHistory"
+	AuthService_MergeAccounts_FullMethodName          = "/bottrade.auth.v1.AuthService/MergeAccounts"
 	AuthService_CreateTelegramLinkCode_FullMethodName = "/bottrade.auth.v1.AuthService/CreateTelegramLinkCode"
 	AuthService_LinkTelegram_FullMethodName           = "/bottrade.auth.v1.AuthService/LinkTelegram"
 	AuthService_TelegramAuth_FullMethodName           = "/bottrade.auth.v1.AuthService/TelegramAuth"
+	AuthService_PullBotMessages_FullMethodName        = "/bottrade.auth.v1.AuthService/PullBotMessages"
 )
 
 // AuthServiceClient is the client API for AuthService service.
@@ -33,12 +39,28 @@ type AuthServiceClient interface {
 	// Web
 	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*AuthResponse, error)
 	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*AuthResponse, error)
+	// Web-сессия: новый access token по refresh token'у (ротируется при каждом вызове).
+	// HTTP edge берёт refresh token из HttpOnly cookie, а не из тела запроса.
+	RefreshSession(ctx context.Context, in *RefreshSessionRequest, opts ...grpc.CallOption) (*AuthResponse, error)
+	// Web-сессия: выход, сессия refresh token'а отзывается
+	EndSession(ctx context.Context, in *EndSessionRequest, opts ...grpc.CallOption) (*EndSessionResponse, error)
+	// Web: смена пароля (требует JWT). Все прежние сессии отзываются, выдаётся новый токен.
+	ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*AuthResponse, error)
+	// Web: история входов текущего пользователя (требует JWT)
+	ListLoginHistory(ctx context.Context, in *ListLoginHistoryRequest, opts ...grpc.CallOption) (*ListLoginHistoryResponse, error)
+	// Web: слить второй аккаунт пользователя (повторная регистрация на другой email) в текущий
+	// (требует JWT основного). Второй аккаунт удаляется, его identities (в т.ч. Telegram),
+	// сессии и история входов переезжают.
+	MergeAccounts(ctx context.Context, in *MergeAccountsRequest, opts ...grpc.CallOption) (*MergeAccountsResponse, error)
 	// Web: выдаём код для привязки Telegram (требует JWT)
 	CreateTelegramLinkCode(ctx context.Context, in *CreateTelegramLinkCodeRequest, opts ...grpc.CallOption) (*CreateTelegramLinkCodeResponse, error)
 	// Telegram bot: привязка Telegram по коду (требует bot-signature)
 	LinkTelegram(ctx context.Context, in *LinkTelegramRequest, opts ...grpc.CallOption) (*LinkTelegramResponse, error)
 	// Telegram bot: логин по telegram_user_id (требует bot-signature)
 	TelegramAuth(ctx context.Context, in *TelegramLoginRequest, opts ...grpc.CallOption) (*AuthResponse, error)
+	// Telegram bot: забрать сообщения для пользователей (уведомления о входе и т.п.).
+	// Выданные сообщения считаются доставленными (требует bot-signature)
+	PullBotMessages(ctx context.Context, in *PullBotMessagesRequest, opts ...grpc.CallOption) (*PullBotMessagesResponse, error)
 }
 
 type authServiceClient struct {
@@ -69,6 +91,56 @@ func (c *authServiceClient) Login(ctx context.Context, in *LoginRequest, opts ..
 	return out, nil
 }
 
+func (c *authServiceClient) RefreshSession(ctx context.Context, in *RefreshSessionRequest, opts ...grpc.CallOption) (*AuthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthResponse)
+	err := c.cc.Invoke(ctx, AuthService_RefreshSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) EndSession(ctx context.Context, in *EndSessionRequest, opts ...grpc.CallOption) (*EndSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EndSessionResponse)
+	err := c.cc.Invoke(ctx, AuthService_EndSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*AuthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthResponse)
+	err := c.cc.Invoke(ctx, AuthService_ChangePassword_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) ListLoginHistory(ctx context.Context, in *ListLoginHistoryRequest, opts ...grpc.CallOption) (*ListLoginHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLoginHistoryResponse)
+	err := c.cc.Invoke(ctx, AuthService_ListLoginHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) MergeAccounts(ctx context.Context, in *MergeAccountsRequest, opts ...grpc.CallOption) (*MergeAccountsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MergeAccountsResponse)
+	err := c.cc.Invoke(ctx, AuthService_MergeAccounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *authServiceClient) CreateTelegramLinkCode(ctx context.Context, in *CreateTelegramLinkCodeRequest, opts ...grpc.CallOption) (*CreateTelegramLinkCodeResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CreateTelegramLinkCodeResponse)
@@ -99,6 +171,16 @@ func (c *authServiceClient) TelegramAuth(ctx context.Context, in *TelegramLoginR
 	return out, nil
 }
 
+func (c *authServiceClient) PullBotMessages(ctx context.Context, in *PullBotMessagesRequest, opts ...grpc.CallOption) (*PullBotMessagesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PullBotMessagesResponse)
+	err := c.cc.Invoke(ctx, AuthService_PullBotMessages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AuthServiceServer is the server API for AuthService service.
 // All implementations must embed UnimplementedAuthServiceServer
 // for forward compatibility.
@@ -106,12 +188,28 @@ type AuthServiceServer interface {
 	// Web
 	Register(context.Context, *RegisterRequest) (*AuthResponse, error)
 	Login(context.Context, *LoginRequest) (*AuthResponse, error)
+	// Web-сессия: новый access token по refresh token'у (ротируется при каждом вызове).
+	// HTTP edge берёт refresh token из HttpOnly cookie, а не из тела запроса.
+	RefreshSession(context.Context, *RefreshSessionRequest) (*AuthResponse, error)
+	// Web-сессия: выход, сессия refresh token'а отзывается
+	EndSession(context.Context, *EndSessionRequest) (*EndSessionResponse, error)
+	// Web: смена пароля (требует JWT). Все прежние сессии отзываются, выдаётся новый токен.
+	ChangePassword(context.Context, *ChangePasswordRequest) (*AuthResponse, error)
+	// Web: история входов текущего пользователя (требует JWT)
+	ListLoginHistory(context.Context, *ListLoginHistoryRequest) (*ListLoginHistoryResponse, error)
+	// Web: слить второй аккаунт пользователя (повторная регистрация на другой email) в текущий
+	// (требует JWT основного). Второй аккаунт удаляется, его identities (в т.ч. Telegram),
+	// сессии и история входов переезжают.
+	MergeAccounts(context.Context, *MergeAccountsRequest) (*MergeAccountsResponse, error)
 	// Web: выдаём код для привязки Telegram (требует JWT)
 	CreateTelegramLinkCode(context.Context, *CreateTelegramLinkCodeRequest) (*CreateTelegramLinkCodeResponse, error)
 	// Telegram bot: привязка Telegram по коду (требует bot-signature)
 	LinkTelegram(context.Context, *LinkTelegramRequest) (*LinkTelegramResponse, error)
 	// Telegram bot: логин по telegram_user_id (требует bot-signature)
 	TelegramAuth(context.Context, *TelegramLoginRequest) (*AuthResponse, error)
+	// Telegram bot: забрать сообщения для пользователей (уведомления о входе и т.п.).
+	// Выданные сообщения считаются доставленными (требует bot-signature)
+	PullBotMessages(context.Context, *PullBotMessagesRequest) (*PullBotMessagesResponse, error)
 	mustEmbedUnimplementedAuthServiceServer()
 }
 
@@ -128,6 +226,21 @@ func (UnimplementedAuthServiceServer) Register(context.Context, *RegisterRequest
 func (UnimplementedAuthServiceServer) Login(context.Context, *LoginRequest) (*AuthResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Login not implemented")
 }
+func (UnimplementedAuthServiceServer) RefreshSession(context.Context, *RefreshSessionRequest) (*AuthResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RefreshSession not implemented")
+}
+func (UnimplementedAuthServiceServer) EndSession(context.Context, *EndSessionRequest) (*EndSessionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EndSession not implemented")
+}
+func (UnimplementedAuthServiceServer) ChangePassword(context.Context, *ChangePasswordRequest) (*AuthResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ChangePassword not implemented")
+}
+func (UnimplementedAuthServiceServer) ListLoginHistory(context.Context, *ListLoginHistoryRequest) (*ListLoginHistoryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListLoginHistory not implemented")
+}
+func (UnimplementedAuthServiceServer) MergeAccounts(context.Context, *MergeAccountsRequest) (*MergeAccountsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method MergeAccounts not implemented")
+}
 func (UnimplementedAuthServiceServer) CreateTelegramLinkCode(context.Context, *CreateTelegramLinkCodeRequest) (*CreateTelegramLinkCodeResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method CreateTelegramLinkCode not implemented")
 }
@@ -137,6 +250,9 @@ func (UnimplementedAuthServiceServer) LinkTelegram(context.Context, *LinkTelegra
 func (UnimplementedAuthServiceServer) TelegramAuth(context.Context, *TelegramLoginRequest) (*AuthResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method TelegramAuth not implemented")
 }
+func (UnimplementedAuthServiceServer) PullBotMessages(context.Context, *PullBotMessagesRequest) (*PullBotMessagesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PullBotMessages not implemented")
+}
 func (UnimplementedAuthServiceServer) mustEmbedUnimplementedAuthServiceServer() {}
 func (UnimplementedAuthServiceServer) testEmbeddedByValue()                     {}
 
@@ -194,6 +310,96 @@ func _AuthService_Login_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthService_RefreshSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).RefreshSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_RefreshSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).RefreshSession(ctx, req.(*RefreshSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_EndSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EndSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).EndSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_EndSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).EndSession(ctx, req.(*EndSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_ChangePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangePasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).ChangePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_ChangePassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).ChangePassword(ctx, req.(*ChangePasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_ListLoginHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLoginHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).ListLoginHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_ListLoginHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).ListLoginHistory(ctx, req.(*ListLoginHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_MergeAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).MergeAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_MergeAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).MergeAccounts(ctx, req.(*MergeAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AuthService_CreateTelegramLinkCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateTelegramLinkCodeRequest)
 	if err := dec(in); err != nil {
@@ -248,6 +454,24 @@ func _AuthService_TelegramAuth_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthService_PullBotMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PullBotMessagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).PullBotMessages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_PullBotMessages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).PullBotMessages(ctx, req.(*PullBotMessagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AuthService_ServiceDesc is the grpc.ServiceDesc for AuthService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -263,6 +487,26 @@ var AuthService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Login",
 			Handler:    _AuthService_Login_Handler,
 		},
+		{
+			MethodName: "RefreshSession",
+			Handler:    _AuthService_RefreshSession_Handler,
+		},
+		{
+			MethodName: "EndSession",
+			Handler:    _AuthService_EndSession_Handler,
+		},
+		{
+			MethodName: "ChangePassword",
+			Handler:    _AuthService_ChangePassword_Handler,
+		},
+		{
+			MethodName: "ListLoginHistory",
+			Handler:    _AuthService_ListLoginHistory_Handler,
+		},
+		{
+			MethodName: "MergeAccounts",
+			Handler:    _AuthService_MergeAccounts_Handler,
+		},
 		{
 			MethodName: "CreateTelegramLinkCode",
 			Handler:    _AuthService_CreateTelegramLinkCode_Handler,
@@ -275,6 +519,10 @@ var AuthService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "TelegramAuth",
 			Handler:    _AuthService_TelegramAuth_Handler,
 		},
+		{
+			MethodName: "PullBotMessages",
+			Handler:    _AuthService_PullBotMessages_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "auth.proto",
@@ -0,0 +1,185 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: options.proto
+
+package authv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AuthLevel — какой уровень доступа нужен для вызова метода.
+// Метод без аннотации считается закрытым (fail closed).
+type AuthLevel int32
+
+const (
+	AuthLevel_AUTH_LEVEL_UNSPECIFIED AuthLevel = 0
+	AuthLevel_PUBLIC                 AuthLevel = 1 // без авторизации
+	AuthLevel_BOT                    AuthLevel = 2 // bot-signature (x-bot-id, x-ts, x-nonce, x-signature)
+	AuthLevel_USER                   AuthLevel = 3 // JWT
+	AuthLevel_ADMIN                  AuthLevel = 4 // JWT + роль admin
+)
+
+// Enum value maps for AuthLevel.
+var (
+	AuthLevel_name = map[int32]string{
+		0: "AUTH_LEVEL_UNSPECIFIED",
+		1: "PUBLIC",
+		2: "BOT",
+		3: "USER",
+		4: "ADMIN",
+	}
+	AuthLevel_value = map[string]int32{
+		"AUTH_LEVEL_UNSPECIFIED": 0,
+		"PUBLIC":                 1,
+		"BOT":                    2,
+		"USER":                   3,
+		"ADMIN":                  4,
+	}
+)
+
+func (x AuthLevel) Enum() *AuthLevel {
+	p := new(AuthLevel)
+	*p = x
+	return p
+}
+
+func (x AuthLevel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AuthLevel) Descriptor() protoreflect.EnumDescriptor {
+	return file_options_proto_enumTypes[0].Descriptor()
+}
+
+func (AuthLevel) Type() protoreflect.EnumType {
+	return &file_options_proto_enumTypes[0]
+}
+
+func (x AuthLevel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AuthLevel.Descriptor instead.
+func (AuthLevel) EnumDescriptor() ([]byte, []int) {
+	return file_options_proto_rawDescGZIP(), []int{0}
+}
+
+var file_options_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*AuthLevel)(nil),
+		Field:         50001,
+		Name:          "bottrade.auth.v1.auth",
+		Tag:           "varint,50001,opt,name=auth,enum=bottrade.auth.v1.AuthLevel",
+		Filename:      "options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         50002,
+		Name:          "bottrade.auth.v1.sensitive",
+		Tag:           "varint,50002,opt,name=sensitive",
+		Filename:      "options.proto",
+	},
+}
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// rpc Foo(...) returns (...) { option (bottrade.auth.v1.auth) = USER; }
+	//
+	// optional bottrade.auth.v1.AuthLevel auth = 50001;
+	E_Auth = &file_options_proto_extTypes[0]
+)
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// string password = 2 [(bottrade.auth.v1.sensitive) = true];
+	// Значение поля не попадает в логи: redact подменяет его при выводе сообщения.
+	//
+	// optional bool sensitive = 50002;
+	E_Sensitive = &file_options_proto_extTypes[1]
+)
+
+var File_options_proto protoreflect.FileDescriptor
+
+const file_options_proto_rawDesc = "" +
+	"\n" +
+	"\roptions.proto\x12\x10bottrade.auth.v1\x1a google/protobuf/descriptor.proto*Q\n" +
+	"\tAuthLevel\x12\x1a\n" +
+	"\x16AUTH_LEVEL_UNSPECIFIED\x10\x00\x12\n" +
+	"\n" +
+	"\x06PUBLIC\x10\x01\x12\a\n" +
+	"\x03BOT\x10\x02\x12\b\n" +
+	"\x04USER\x10\x03\x12\t\n" +
+	"\x05ADMIN\x10\x04:Q\n" +
+	"\x04auth\x12\x1e.google.protobuf.MethodOptions\x18ц\x03 \x01(\x0e2\x1b.bottrade.auth.v1.AuthLevelR\x04auth:=\n" +
+	"\tsensitive\x12\x1d.google.protobuf.FieldOptions\x18҆\x03 \x01(\bR\tsensitiveB?Z=github.com/IvanOplesnin/BotTradeService.git/gen/authv1;authv1b\x06proto3"
+
+var (
+	file_options_proto_rawDescOnce sync.Once
+	file_options_proto_rawDescData []byte
+)
+
+func file_options_proto_rawDescGZIP() []byte {
+	file_options_proto_rawDescOnce.Do(func() {
+		file_options_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_options_proto_rawDesc), len(file_options_proto_rawDesc)))
+	})
+	return file_options_proto_rawDescData
+}
+
+var file_options_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_options_proto_goTypes = []any{
+	(AuthLevel)(0),                     // 0: bottrade.auth.v1.AuthLevel
+	(*descriptorpb.MethodOptions)(nil), // 1: google.protobuf.MethodOptions
+	(*descriptorpb.FieldOptions)(nil),  // 2: google.protobuf.FieldOptions
+}
+var file_options_proto_depIdxs = []int32{
+	1, // 0: bottrade.auth.v1.auth:extendee -> google.protobuf.MethodOptions
+	2, // 1: bottrade.auth.v1.sensitive:extendee -> google.protobuf.FieldOptions
+	0, // 2: bottrade.auth.v1.auth:type_name -> bottrade.auth.v1.AuthLevel
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	2, // [2:3] is the sub-list for extension type_name
+	0, // [0:2] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_options_proto_init() }
+func file_options_proto_init() {
+	if File_options_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_options_proto_rawDesc), len(file_options_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 2,
+			NumServices:   0,
+		},
+		GoTypes:           file_options_proto_goTypes,
+		DependencyIndexes: file_options_proto_depIdxs,
+		EnumInfos:         file_options_proto_enumTypes,
+		ExtensionInfos:    file_options_proto_extTypes,
+	}.Build()
+	File_options_proto = out.File
+	file_options_proto_goTypes = nil
+	file_options_proto_depIdxs = nil
+}
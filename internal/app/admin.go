@@ -0,0 +1,67 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/interceptor/recoveryinterceptor"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/logger"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// adminServer — служебный HTTP listener для операторов и мониторинга: /metrics (Prometheus)
+// и /debug/crashes — последние перехваченные panic со стеком (если включён журнал).
+// Наружу его не публикуют, поэтому авторизации на нём нет.
+type adminServer struct {
+	address string
+	http    *http.Server
+}
+
+func newAdminServer(address string, crashes *recoveryinterceptor.CrashLog) *adminServer {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.Handler())
+	if crashes != nil {
+		mux.HandleFunc("GET /debug/crashes", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(crashes.Recent())
+		})
+	}
+
+	return &adminServer{
+		address: address,
+		http: &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}
+}
+
+func (s *adminServer) start() error {
+	lis, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := s.http.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Log.Errorf("admin: http serve: %s", err)
+		}
+	}()
+	logger.Log.Infof("admin: listening on %s", s.address)
+	return nil
+}
+
+func (s *adminServer) shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), gatewayShutdownTimeout)
+	defer cancel()
+	if err := s.http.Shutdown(ctx); err != nil {
+		s.http.Close()
+	}
+}
+
+func (s *adminServer) close() {
+	s.http.Close()
+}
@@ -1,22 +1,52 @@
 package app
 
 import (
+	"context"
+	"fmt"
 	"net"
+	"os"
+	"strconv"
+	"time"
 
+	"github.com/IvanOplesnin/BotTradeService.git/gen/authv1"
 	"github.com/IvanOplesnin/BotTradeService.git/internal/config"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/gateway"
 	grpchandlers "github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/handlers"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/healthcheck"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/interceptor/recoveryinterceptor"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/servertls"
 	"github.com/IvanOplesnin/BotTradeService.git/internal/logger"
 	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/psql"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/audit"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/emailnorm"
 	"github.com/IvanOplesnin/BotTradeService.git/internal/service/hasher/argon2hash"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/janitor"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/notify"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/notify/smtpmail"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/outbox"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/outbox/natspub"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/passpolicy"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/scheduler"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/svcadmin"
 	"github.com/IvanOplesnin/BotTradeService.git/internal/service/svcauth"
 	"github.com/IvanOplesnin/BotTradeService.git/internal/service/token"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/webhook"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/tracing"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
 )
 
 type App struct {
 	cfg *config.Config
 
 	grpcServer *grpc.Server
+	health     *healthcheck.Checker
+	gateway    *gatewayServer // nil, если app.gateway_address не задан
+	admin      *adminServer   // nil, если app.admin_address не задан
+	relay      *outbox.Relay
+	webhooks   *webhook.Worker
+	scheduler  *scheduler.Scheduler
 	close      func()
 }
 
@@ -30,6 +60,12 @@ func InitApp(configPath string) (*App, error) {
 		return nil, err
 	}
 
+	shutdownTracing, err := tracing.SetupTracing(&cfg.Tracing)
+	if err != nil {
+		logger.Log.Errorf("no init tracing: %s", err.Error())
+		return nil, err
+	}
+
 	hasherPass, err := argon2hash.New(cfg.Security.PasswordHash)
 	if err != nil {
 		logger.Log.Errorf("no init hasher: %s", err.Error())
@@ -40,39 +76,311 @@ func InitApp(configPath string) (*App, error) {
 		logger.Log.Errorf("no init tokener: %s", err.Error())
 		return nil, err
 	}
-	pool, err := psql.Connect(cfg.App.Dsn)
+	passwordPolicy, err := passpolicy.New(cfg.Security.PasswordPolicy)
+	if err != nil {
+		logger.Log.Errorf("no init password policy: %s", err.Error())
+		return nil, err
+	}
+	store, err := newStorage(cfg.App)
 	if err != nil {
 		logger.Log.Errorf("no init repo: %s", err.Error())
 		return nil, err
 	}
+	repo := store.repo
+	auditRecorder := audit.NewRecorder(repo)
 
-	repo := psql.NewPsqlRepo(pool)
+	mailer, err := newMailer(cfg.Notify.SMTP)
+	if err != nil {
+		logger.Log.Errorf("no init mailer: %s", err.Error())
+		store.close()
+		return nil, err
+	}
+	publisher, closePublisher, err := newPublisher(cfg.Outbox)
+	if err != nil {
+		logger.Log.Errorf("no init outbox publisher: %s", err.Error())
+		store.close()
+		return nil, err
+	}
+	relay := outbox.NewRelay(
+		outbox.RelayDeps{
+			Store:        repo,
+			Publisher:    outbox.MultiPublisher{publisher, webhook.NewFanout(repo)},
+			PollInterval: cfg.Outbox.PollInterval.Duration(),
+			BatchSize:    cfg.Outbox.BatchSize,
+			MaxAttempts:  cfg.Outbox.MaxAttempts,
+		},
+	)
+
+	webhookWorker := webhook.NewWorker(
+		webhook.WorkerDeps{
+			Store:        repo,
+			PollInterval: cfg.Webhooks.PollInterval.Duration(),
+			MaxAttempts:  cfg.Webhooks.MaxAttempts,
+			Timeout:      cfg.Webhooks.Timeout.Duration(),
+		},
+	)
+
+	sched := scheduler.New(
+		scheduler.SchedulerDeps{
+			Locker: repo,
+			Jobs:   janitor.Jobs(janitorDeps(repo, cfg.Janitor)),
+		},
+	)
+
+	notifier := notify.New(
+		notify.NotifierDeps{
+			Mailer: mailer,
+			Queue:  repo,
+		},
+	)
 
 	authService := svcauth.New(
 		svcauth.AuthUsecaseDeps{
-			Hasher:  hasherPass,
-			Tokener: tokener,
-			Repo:    repo,
+			Hasher:   hasherPass,
+			Tokener:  tokener,
+			Emails:   emailnorm.New(cfg.Security.Email),
+			Policy:   passwordPolicy,
+			Repo:     repo,
+			WithTx:   store.authTx,
+			Audit:    auditRecorder,
+			Notifier: notifier,
+
+			RefreshTTL: cfg.Security.WebSession.RefreshTTL.Duration(),
+			BotSecrets: cfg.Security.Bots.Secrets,
+			BotMaxSkew: cfg.Security.Bots.MaxSkew.Duration(),
 		},
 	)
 
-	server := grpchandlers.InitHandlers(
+	adminService := svcadmin.New(
+		svcadmin.AdminUsecaseDeps{
+			Repo:   repo,
+			WithTx: store.adminTx,
+			Audit:  auditRecorder,
+		},
+	)
+
+	webhookService := webhook.New(
+		webhook.ServiceDeps{
+			Store: repo,
+			Audit: auditRecorder,
+		},
+	)
+
+	creds, err := newServerCreds(cfg.App.TLS)
+	if err != nil {
+		logger.Log.Errorf("no init tls: %s", err.Error())
+		closePublisher()
+		store.close()
+		return nil, err
+	}
+
+	var crashes *recoveryinterceptor.CrashLog
+	if cfg.App.CrashLogSize > 0 {
+		crashes = recoveryinterceptor.NewCrashLog(cfg.App.CrashLogSize)
+	}
+
+	healthServer := health.NewServer()
+	healthChecker := healthcheck.NewChecker(
+		healthcheck.CheckerDeps{
+			Server:   healthServer,
+			Probe:    store.ping,
+			Services: []string{authv1.AuthService_ServiceDesc.ServiceName, authv1.AdminService_ServiceDesc.ServiceName},
+			Interval: cfg.App.Health.ProbeInterval.Duration(),
+			Timeout:  cfg.App.Health.ProbeTimeout.Duration(),
+		},
+	)
+
+	server, err := grpchandlers.InitHandlers(
 		grpchandlers.InitHandlerDeps{
-			TokenVerifier: authService,
-			AuthUseCase:   authService,
-			BotVerifier:   authService,
+			TokenVerifier:  authService,
+			AuthUseCase:    authService,
+			AdminUseCase:   adminService,
+			WebhookUseCase: webhookService,
+			BotVerifier:    authService,
+			AdminVerifier:  authService,
+
+			CodeTgTtlMinute:   cfg.Security.TgLinkCodeTtlMinute,
+			TrustProxyHeaders: cfg.App.TrustProxyHeaders,
+			LogPayloads:       cfg.Logger.LogPayloads,
+			Creds:             creds,
+			ServiceIdentities: cfg.App.TLS.ServiceIdentities,
+			Health:            healthServer,
+			Crashes:           crashes,
 		},
 	)
+	if err != nil {
+		logger.Log.Errorf("no init handlers: %s", err.Error())
+		closePublisher()
+		store.close()
+		return nil, err
+	}
+
+	var gw *gatewayServer
+	if cfg.App.GatewayAddress != "" {
+		if gw, err = newGatewayServer(cfg.App, cfg.Security.WebSession, server); err != nil {
+			logger.Log.Errorf("no init gateway: %s", err.Error())
+			closePublisher()
+			store.close()
+			return nil, err
+		}
+	}
+
+	var admin *adminServer
+	if cfg.App.AdminAddress != "" {
+		admin = newAdminServer(cfg.App.AdminAddress, crashes)
+	}
 
 	return &App{
 		cfg:        cfg,
 		grpcServer: server,
+		health:     healthChecker,
+		gateway:    gw,
+		admin:      admin,
+		relay:      relay,
+		webhooks:   webhookWorker,
+		scheduler:  sched,
 		close: func() {
-			pool.Close()
+			if gw != nil {
+				gw.close()
+			}
+			if admin != nil {
+				admin.close()
+			}
+			healthChecker.Stop()
+			sched.Stop()
+			relay.Stop()
+			webhookWorker.Stop()
+			closePublisher()
+			notifier.Close()
+			store.close()
+			// последние span-ы остановки тоже уходят в коллектор
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				logger.Log.Warnf("tracing shutdown: %s", err)
+			}
 		},
 	}, nil
 }
 
+// Migrate — подкоманда migrate: up, down, status или redo над встроенными миграциями
+// и normalize-emails — пересчёт ключей email по текущему security.email.
+func Migrate(configPath string, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: migrate up|down|status|redo|normalize-emails")
+	}
+
+	cfg, err := config.NewConfig(configPath)
+	if err != nil {
+		return err
+	}
+	if err := logger.SetupLogger(&cfg.Logger); err != nil {
+		return err
+	}
+	if cfg.App.Storage != config.StoragePostgres {
+		return fmt.Errorf("migrate: app.storage is %s, nothing to migrate", cfg.App.Storage)
+	}
+
+	if args[0] == psql.MigrateNormalizeEmails {
+		return psql.NormalizeEmails(context.Background(), cfg.App.Dsn, emailnorm.New(cfg.Security.Email).Normalize, os.Stdout)
+	}
+	return psql.Migrate(context.Background(), cfg.App.Dsn, args[0], os.Stdout)
+}
+
+// defaultBloomFPRate — доля ложных срабатываний: ~14 бит на пароль, для полного дампа HIBP около 1.5 GiB.
+const defaultBloomFPRate = 0.001
+
+// BuildBloom — подкоманда build-bloom: фильтр утёкших паролей для security.password_policy.breached_bloom_file.
+func BuildBloom(args []string) error {
+	if len(args) != 2 && len(args) != 3 {
+		return fmt.Errorf("usage: build-bloom <hibp-sha1-dump> <out> [fp_rate]")
+	}
+	fpRate := defaultBloomFPRate
+	if len(args) == 3 {
+		var err error
+		if fpRate, err = strconv.ParseFloat(args[2], 64); err != nil {
+			return fmt.Errorf("fp_rate: %w", err)
+		}
+	}
+	return passpolicy.BuildBloomFile(args[0], args[1], fpRate, os.Stdout)
+}
+
+// OpenAPI — подкоманда openapi: OpenAPI-документ REST-шлюза в файл или stdout.
+func OpenAPI(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: openapi [out]")
+	}
+	doc, err := gateway.OpenAPI()
+	if err != nil {
+		return err
+	}
+	doc = append(doc, '\n')
+	if len(args) == 0 {
+		_, err = os.Stdout.Write(doc)
+		return err
+	}
+	return os.WriteFile(args[0], doc, 0o644)
+}
+
+// newServerCreds: без app.tls.cert_file gRPC listener работает без TLS (nil).
+func newServerCreds(cfg config.TLS) (credentials.TransportCredentials, error) {
+	if cfg.CertFile == "" {
+		return nil, nil
+	}
+	reloader, err := servertls.NewReloader(servertls.ReloaderDeps{
+		CertFile:          cfg.CertFile,
+		KeyFile:           cfg.KeyFile,
+		ClientCAFile:      cfg.ClientCAFile,
+		RequireClientCert: cfg.RequireClientCert,
+		ReloadInterval:    cfg.ReloadInterval.Duration(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return servertls.NewCredentials(reloader.TLSConfig()), nil
+}
+
+// newMailer: без smtp.host письма только пишутся в лог.
+func newMailer(cfg config.SMTP) (notify.Mailer, error) {
+	if cfg.Host == "" {
+		return notify.LogMailer{}, nil
+	}
+	return smtpmail.New(cfg)
+}
+
+// newPublisher выбирает шину для outbox; вторым значением — закрытие соединения.
+func newPublisher(cfg config.Outbox) (outbox.Publisher, func(), error) {
+	if cfg.Publisher != "nats" {
+		return outbox.LogPublisher{}, func() {}, nil
+	}
+	p, err := natspub.New(cfg.NATS)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, p.Close, nil
+}
+
+// janitorDeps переводит секцию janitor конфига в расписания job-ов.
+func janitorDeps(store janitor.Store, cfg config.Janitor) janitor.JobsDeps {
+	schedule := func(j config.JanitorJob) janitor.Schedule {
+		return janitor.Schedule{
+			Disabled:  j.Disabled,
+			Interval:  j.Interval.Duration(),
+			Retention: j.Retention.Duration(),
+		}
+	}
+	return janitor.JobsDeps{
+		Store:             store,
+		BatchSize:         cfg.BatchSize,
+		LinkCodes:         schedule(cfg.LinkCodes),
+		Sessions:          schedule(cfg.Sessions),
+		Outbox:            schedule(cfg.Outbox),
+		BotMessages:       schedule(cfg.BotMessages),
+		WebhookDeliveries: schedule(cfg.WebhookDeliveries),
+		BotNonces:         schedule(cfg.BotNonces),
+	}
+}
+
 func (a *App) Run() error {
 	lis, err := net.Listen("tcp", a.cfg.App.Address)
 	if err != nil {
@@ -80,6 +388,22 @@ func (a *App) Run() error {
 		return err
 	}
 	defer a.close()
+	if a.admin != nil {
+		if err := a.admin.start(); err != nil {
+			logger.Log.Errorf("app.Run error: %s", err)
+			return err
+		}
+	}
+	if a.gateway != nil {
+		if err := a.gateway.start(a.grpcServer); err != nil {
+			logger.Log.Errorf("app.Run error: %s", err)
+			return err
+		}
+	}
+	a.relay.Start()
+	a.webhooks.Start()
+	a.scheduler.Start()
+	a.health.Start()
 	if err := a.grpcServer.Serve(lis); err != nil {
 		logger.Log.Errorf("app.Run error: %s", err)
 		return err
@@ -93,8 +417,26 @@ func (a *App) Close() {
 	}
 }
 
+// DrainDelay — пауза в начале GracefulStop, пока балансировщики уводят трафик.
+func (a *App) DrainDelay() time.Duration {
+	return a.cfg.App.Health.DrainDelay.Duration()
+}
+
 func (a *App) GracefulStop() {
+	// NOT_SERVING первым делом: новые запросы уходят на другие реплики
+	a.health.Shutdown()
+	if d := a.DrainDelay(); d > 0 {
+		logger.Log.Infof("health: NOT_SERVING, draining for %s", d)
+		time.Sleep(d)
+	}
+	if a.gateway != nil {
+		a.gateway.shutdown()
+	}
 	a.grpcServer.GracefulStop()
+	// метрики доступны до конца остановки
+	if a.admin != nil {
+		a.admin.shutdown()
+	}
 }
 
 func (a *App) Stop() {
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/config"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/gateway"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// gatewayBufSize — буфер in-process соединения шлюза с gRPC-сервером.
+const gatewayBufSize = 1 << 20
+
+// gatewayShutdownTimeout — сколько ждать незавершённые HTTP-запросы при остановке.
+const gatewayShutdownTimeout = 5 * time.Second
+
+// gatewayServer — HTTP listener для браузера: REST-шлюз и gRPC-Web. REST ходит до gRPC-сервера
+// через bufconn, gRPC-Web — через grpc.Server.ServeHTTP; в обоих случаях запросы проходят
+// те же интерцепторы, что и нативные gRPC-вызовы.
+type gatewayServer struct {
+	address string
+	bufLis  *bufconn.Listener
+	conn    *grpc.ClientConn
+	http    *http.Server
+}
+
+func newGatewayServer(cfg config.App, web config.WebSession, server *grpc.Server) (*gatewayServer, error) {
+	bufLis := bufconn.Listen(gatewayBufSize)
+	conn, err := grpc.NewClient("passthrough:///gateway",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return bufLis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rest, err := gateway.New(gateway.GatewayDeps{
+		Conn:              conn,
+		TrustProxyHeaders: cfg.TrustProxyHeaders,
+		WebSession: gateway.WebSessionOptions{
+			CookieDomain:   web.CookieDomain,
+			SameSite:       sameSite(web.SameSite),
+			InsecureCookie: web.InsecureCookie,
+		},
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	deps := gateway.HTTPDeps{
+		REST: rest,
+		CORS: gateway.CORSOptions{
+			AllowedOrigins:   cfg.CORS.AllowedOrigins,
+			AllowedMethods:   cfg.CORS.AllowedMethods,
+			AllowedHeaders:   cfg.CORS.AllowedHeaders,
+			ExposedHeaders:   cfg.CORS.ExposedHeaders,
+			AllowCredentials: cfg.CORS.AllowCredentials,
+			MaxAge:           cfg.CORS.MaxAge.Duration(),
+		},
+	}
+	if cfg.GrpcWeb {
+		deps.GRPCWeb = server
+	}
+
+	return &gatewayServer{
+		address: cfg.GatewayAddress,
+		bufLis:  bufLis,
+		conn:    conn,
+		http: &http.Server{
+			Handler:           gateway.NewHTTPHandler(deps),
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}, nil
+}
+
+// start поднимает HTTP listener и отдаёт gRPC-серверу in-process listener шлюза.
+func (g *gatewayServer) start(server *grpc.Server) error {
+	lis, err := net.Listen("tcp", g.address)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := server.Serve(g.bufLis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			logger.Log.Errorf("gateway: grpc serve: %s", err)
+		}
+	}()
+	go func() {
+		if err := g.http.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Log.Errorf("gateway: http serve: %s", err)
+		}
+	}()
+	logger.Log.Infof("gateway: listening on %s", g.address)
+	return nil
+}
+
+// shutdown дожидается текущих HTTP-запросов; вызывается до остановки gRPC-сервера.
+func (g *gatewayServer) shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), gatewayShutdownTimeout)
+	defer cancel()
+	if err := g.http.Shutdown(ctx); err != nil {
+		g.http.Close()
+	}
+}
+
+func (g *gatewayServer) close() {
+	g.http.Close()
+	g.conn.Close()
+}
+
+// sameSite — значение security.web_session.same_site (проверено в config) для cookie.
+func sameSite(v string) http.SameSite {
+	switch v {
+	case "lax":
+		return http.SameSiteLaxMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteStrictMode
+	}
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/config"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/logger"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/inmemory"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/psql"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/audit"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/janitor"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/notify"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/outbox"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/scheduler"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/svcadmin"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/svcauth"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/webhook"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// repository — всё, что сервисы ждут от хранилища; реализуют psql.Repo и inmemory.Repo.
+type repository interface {
+	svcauth.AuthRepo
+	svcadmin.AdminRepo
+	audit.Store
+	notify.BotQueue
+	outbox.Store
+	webhook.Store
+	webhook.FanoutStore
+	webhook.WorkerStore
+	janitor.Store
+	scheduler.Locker
+}
+
+type storage struct {
+	repo    repository
+	authTx  svcauth.TxRunner
+	adminTx svcadmin.TxRunner
+	ping    func(ctx context.Context) error // проверка готовности для health
+	close   func()
+}
+
+// newStorage: app.storage = postgres (по умолчанию) или memory — без БД, данные до рестарта.
+func newStorage(cfg config.App) (*storage, error) {
+	if cfg.Storage == config.StorageMemory {
+		logger.Log.Warn("storage: memory — data is lost on restart, do not use in production")
+		repo := inmemory.New()
+		return &storage{
+			repo:    repo,
+			authTx:  authTx(repo.WithTx),
+			adminTx: adminTx(repo.WithTx),
+			ping:    func(context.Context) error { return nil },
+			close:   func() {},
+		}, nil
+	}
+
+	isolation, err := psql.ParseIsolation(cfg.TxIsolation)
+	if err != nil {
+		return nil, err
+	}
+	if err := prepareSchema(cfg); err != nil {
+		return nil, err
+	}
+	pool, err := psql.Connect(cfg.Dsn)
+	if err != nil {
+		return nil, err
+	}
+	prometheus.MustRegister(psql.NewPoolCollector(pool))
+	repo := psql.NewPsqlRepo(pool, psql.TxOptions{
+		Isolation:  isolation,
+		MaxRetries: cfg.TxMaxRetries,
+	})
+	return &storage{
+		repo:    repo,
+		authTx:  authTx(repo.WithTx),
+		adminTx: adminTx(repo.WithTx),
+		ping:    pool.Ping,
+		close:   pool.Close,
+	}, nil
+}
+
+// migrateTimeout — сколько ждём миграции при старте (включая ожидание lock-а другой реплики).
+const migrateTimeout = 10 * time.Minute
+
+func prepareSchema(cfg config.App) error {
+	ctx, cancel := context.WithTimeout(context.Background(), migrateTimeout)
+	defer cancel()
+
+	if cfg.AutoMigrate {
+		if err := psql.Migrate(ctx, cfg.Dsn, psql.MigrateUp, io.Discard); err != nil {
+			return fmt.Errorf("auto migrate: %w", err)
+		}
+	}
+	return psql.CheckSchemaVersion(ctx, cfg.Dsn)
+}
+
+// authTx и adminTx отдают сервисам транзакции хранилища под их собственными интерфейсами репозитория.
+func authTx[R svcauth.AuthRepo](withTx func(context.Context, func(R) error) error) svcauth.TxRunner {
+	return func(ctx context.Context, fn func(svcauth.AuthRepo) error) error {
+		return withTx(ctx, func(tx R) error { return fn(tx) })
+	}
+}
+
+func adminTx[R svcadmin.AdminRepo](withTx func(context.Context, func(R) error) error) svcadmin.TxRunner {
+	return func(ctx context.Context, fn func(svcadmin.AdminRepo) error) error {
+		return withTx(ctx, func(tx R) error { return fn(tx) })
+	}
+}
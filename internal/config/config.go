@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"go.yaml.in/yaml/v3"
@@ -12,21 +14,130 @@ type Config struct {
 	Logger   Logger
 	App      App
 	Security Security
+	Notify   Notify
+	Outbox   Outbox
+	Webhooks Webhooks
+	Janitor  Janitor
+	Tracing  Tracing
 }
 
 type Logger struct {
 	Level  string `yaml:"level"`
 	Format string `yaml:"format"`
+	// LogPayloads — на уровне debug писать тела запросов и ответов gRPC
+	// (sensitive поля proto скрываются)
+	LogPayloads bool `yaml:"log_payloads"`
+}
+
+// Tracing — OpenTelemetry. По умолчанию exporter none: span-ы не пишутся, но trace context
+// из входящих запросов всё равно передаётся дальше.
+type Tracing struct {
+	Exporter    string  `yaml:"exporter"`     // none | otlp, по умолчанию none
+	Endpoint    string  `yaml:"endpoint"`     // OTLP/gRPC коллектор host:port, по умолчанию localhost:4317
+	Insecure    bool    `yaml:"insecure"`     // без TLS до коллектора
+	SampleRatio float64 `yaml:"sample_ratio"` // доля корневых trace-ов, по умолчанию 1
+	ServiceName string  `yaml:"service_name"` // по умолчанию bottrade-auth
 }
 
 type App struct {
 	Address string `yaml:"adress"`
-	Dsn     string `yaml:"dsn"`
+	TLS     TLS    `yaml:"tls"`     // TLS gRPC listener-а; без cert_file — plaintext
+	Dsn     string `yaml:"dsn"`     // не нужен при storage: memory
+	Storage string `yaml:"storage"` // postgres | memory, по умолчанию postgres
+
+	// применять встроенные миграции при старте; без него сервер не стартует на отставшей схеме
+	AutoMigrate bool `yaml:"auto_migrate"`
+
+	// HTTP listener для веб-фронтенда (host:port): REST/JSON шлюз и gRPC-Web; пусто — выключен
+	GatewayAddress string `yaml:"gateway_address"`
+	GrpcWeb        bool   `yaml:"grpc_web"` // отдавать authv1 по gRPC-Web на том же listener-е
+	CORS           CORS   `yaml:"cors"`
+
+	Health Health `yaml:"health"`
+	// служебный HTTP listener (host:port): /metrics, /debug/crashes; пусто — выключен
+	AdminAddress string `yaml:"admin_address"`
+	// сколько последних panic хранить для GET /debug/crashes на admin listener; 0 — не хранить
+	CrashLogSize int `yaml:"crash_log_size"`
+
+	// брать IP клиента из x-forwarded-for/x-real-ip (только за своим балансировщиком)
+	TrustProxyHeaders bool `yaml:"trust_proxy_headers"`
+
+	// транзакции репозитория: read_committed | repeatable_read | serializable
+	TxIsolation  string `yaml:"tx_isolation"`   // по умолчанию read_committed
+	TxMaxRetries int    `yaml:"tx_max_retries"` // повторы при 40001/40P01, по умолчанию 3
 }
 
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
+
 type Security struct {
 	PasswordHash PasswordHash `yaml:"password_hash"`
 	Tokener      Tokener      `yaml:"tokener"`
+	Email        Email        `yaml:"email"`
+
+	PasswordPolicy PasswordPolicy `yaml:"password_policy"`
+	WebSession     WebSession     `yaml:"web_session"`
+	Bots           Bots           `yaml:"bots"`
+
+	TgLinkCodeTtlMinute int64 `yaml:"tg_link_code_ttl_min"` // по умолчанию 10
+}
+
+// Bots — боты, которые подписывают запросы к BOT-методам HMAC (x-bot-id, x-ts, x-nonce,
+// x-signature). Секрет каждого бота — только из env BOT_SECRET_<ID> (см. BotSecretEnv).
+// Без ботов BOT-методы доступны только сервисам с mTLS-идентичностью.
+type Bots struct {
+	IDs     []string        `yaml:"ids"`
+	MaxSkew SecondsDuration `yaml:"max_skew_sec"` // допустимое расхождение x-ts, по умолчанию 300
+
+	Secrets map[string][]byte `yaml:"-"`
+}
+
+// BotSecretEnv — имя переменной окружения с секретом бота: BOT_SECRET_ и id
+// в верхнем регистре, символы кроме букв и цифр заменены на "_".
+func BotSecretEnv(botID string) string {
+	name := []byte(strings.ToUpper(botID))
+	for i, c := range name {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			name[i] = '_'
+		}
+	}
+	return "BOT_SECRET_" + string(name)
+}
+
+// TLS — транспорт gRPC listener-а. Сертификат и CA перечитываются при изменении файлов.
+// С client_ca_file включается mTLS: SAN/CN проверенного клиентского сертификата —
+// идентичность внутреннего сервиса, которой service_identities разрешают BOT-методы без
+// HMAC-подписи.
+type TLS struct {
+	CertFile       string          `yaml:"cert_file"`
+	KeyFile        string          `yaml:"key_file"`
+	ReloadInterval SecondsDuration `yaml:"reload_interval_sec"` // как часто проверять файлы, по умолчанию 30
+
+	ClientCAFile      string `yaml:"client_ca_file"`
+	RequireClientCert bool   `yaml:"require_client_cert"` // иначе сертификат клиента необязателен
+	// идентичность (URI SAN, DNS SAN или CN) -> full method names, например
+	// /bottrade.auth.v1.AuthService/LinkTelegram
+	ServiceIdentities map[string][]string `yaml:"service_identities"`
+}
+
+// Health — grpc.health.v1: статус SERVING, пока хранилище отвечает на проверку.
+type Health struct {
+	ProbeInterval SecondsDuration `yaml:"probe_interval_sec"` // по умолчанию 5
+	ProbeTimeout  SecondsDuration `yaml:"probe_timeout_sec"`  // по умолчанию 2
+	// сколько ждать после перехода в NOT_SERVING, прежде чем перестать принимать запросы:
+	// балансировщик должен успеть заметить статус; по умолчанию 0
+	DrainDelay SecondsDuration `yaml:"drain_delay_sec"`
+}
+
+// WebSession — режим HttpOnly cookie для веб-приложения на HTTP listener-е: refresh token
+// в cookie, короткий access token в теле ответа, CSRF по схеме double-submit.
+type WebSession struct {
+	RefreshTTL     SecondsDuration `yaml:"refresh_ttl_sec"` // срок web-сессии, по умолчанию 30 дней
+	CookieDomain   string          `yaml:"cookie_domain"`   // пусто — только хост listener-а
+	SameSite       string          `yaml:"same_site"`       // strict | lax | none, по умолчанию strict
+	InsecureCookie bool            `yaml:"insecure_cookie"` // без Secure — только для разработки по http
 }
 
 type PasswordHash struct {
@@ -43,6 +154,92 @@ type PasswordHash struct {
 	MaxParallelism uint8  `yaml:"max_parallelism"`
 }
 
+// PasswordPolicy — требования к новым паролям (регистрация и смена; на логин не влияют).
+type PasswordPolicy struct {
+	MinLength int `yaml:"min_length"` // в символах, не байтах; по умолчанию 8
+	MaxLength int `yaml:"max_length"` // по умолчанию 128
+
+	RequireLower  bool `yaml:"require_lower"`
+	RequireUpper  bool `yaml:"require_upper"`
+	RequireDigit  bool `yaml:"require_digit"`
+	RequireSymbol bool `yaml:"require_symbol"`
+	MinClasses    int  `yaml:"min_classes"` // сколько из 4 классов (строчные, заглавные, цифры, символы), 0 — не важно
+
+	// частые пароли по строке, дополнительно к встроенному списку
+	DenylistFile string `yaml:"denylist_file"`
+	// bloom-фильтр утёкших паролей (`build-bloom` из SHA-1 дампа HIBP); пусто — без проверки
+	BreachedBloomFile string `yaml:"breached_bloom_file"`
+}
+
+// Email — как сравнивать адреса. Регистр и IDNA учитываются всегда.
+type Email struct {
+	// правила провайдеров (Gmail: точки и +tag не различают ящики). Включение на живой базе —
+	// только вместе с `migrate normalize-emails`, иначе старые аккаунты не найдутся по новому ключу.
+	ProviderRules bool `yaml:"provider_rules"`
+}
+
+type Notify struct {
+	SMTP SMTP `yaml:"smtp"`
+}
+
+// SMTP — почта для уведомлений. Пустой host — письма только пишутся в лог.
+type SMTP struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"` // по умолчанию 587
+	Username string `yaml:"username"`
+	Password string `yaml:"-"` // пароль только из env SMTP_PASSWORD
+	From     string `yaml:"from"`
+}
+
+// CORS — доступ браузера к HTTP listener-у с чужих origin. Без allowed_origins CORS выключен.
+type CORS struct {
+	AllowedOrigins   []string        `yaml:"allowed_origins"`   // точные origin; "*" — любой
+	AllowedMethods   []string        `yaml:"allowed_methods"`   // по умолчанию GET, POST
+	AllowedHeaders   []string        `yaml:"allowed_headers"`   // по умолчанию заголовки REST и gRPC-Web
+	ExposedHeaders   []string        `yaml:"exposed_headers"`   // по умолчанию grpc-status, grpc-message, grpc-status-details-bin
+	AllowCredentials bool            `yaml:"allow_credentials"` // куки и authorization с чужого origin
+	MaxAge           SecondsDuration `yaml:"max_age_sec"`       // кэш preflight, по умолчанию 600
+}
+
+// Outbox — публикация доменных событий (user.registered, telegram.linked, ...).
+type Outbox struct {
+	Publisher    string          `yaml:"publisher"`         // log | nats, по умолчанию log
+	PollInterval SecondsDuration `yaml:"poll_interval_sec"` // по умолчанию 2
+	BatchSize    int32           `yaml:"batch_size"`        // по умолчанию 100
+	MaxAttempts  int32           `yaml:"max_attempts"`      // после — dead letter, по умолчанию 10
+	NATS         NATS            `yaml:"nats"`
+}
+
+// Webhooks — доставка событий по подпискам из AdminService.
+type Webhooks struct {
+	PollInterval SecondsDuration `yaml:"poll_interval_sec"` // по умолчанию 2
+	MaxAttempts  int32           `yaml:"max_attempts"`      // после — статус failed, по умолчанию 8
+	Timeout      SecondsDuration `yaml:"timeout_sec"`       // на один запрос, по умолчанию 10
+}
+
+// Janitor — фоновая очистка устаревших строк. Каждый job на кластер выполняет одна реплика
+// (advisory lock в Postgres). Нули — значения по умолчанию из janitor.
+type Janitor struct {
+	BatchSize         int32      `yaml:"batch_size"` // строк за один DELETE, по умолчанию 1000
+	LinkCodes         JanitorJob `yaml:"link_codes"`
+	Sessions          JanitorJob `yaml:"sessions"`
+	Outbox            JanitorJob `yaml:"outbox"`
+	BotMessages       JanitorJob `yaml:"bot_messages"`
+	WebhookDeliveries JanitorJob `yaml:"webhook_deliveries"`
+	BotNonces         JanitorJob `yaml:"bot_nonces"`
+}
+
+type JanitorJob struct {
+	Disabled  bool            `yaml:"disabled"`
+	Interval  SecondsDuration `yaml:"interval_sec"`
+	Retention SecondsDuration `yaml:"retention_sec"` // сколько хранить после истечения/доставки
+}
+
+type NATS struct {
+	URL           string `yaml:"url"`
+	SubjectPrefix string `yaml:"subject_prefix"` // по умолчанию bottrade.auth
+}
+
 type SecondsDuration time.Duration
 
 func (d *SecondsDuration) UnmarshalYAML(value *yaml.Node) error {
@@ -88,15 +285,62 @@ func NewConfig(fileCfg string) (*Config, error) {
 		return nil, fmt.Errorf("SECRET_KEY env var is required")
 	}
 	cfg.Security.Tokener.Secret = []byte(secret)
+	cfg.Notify.SMTP.Password = os.Getenv("SMTP_PASSWORD")
+	cfg.Security.Bots.Secrets = make(map[string][]byte, len(cfg.Security.Bots.IDs))
+	for _, id := range cfg.Security.Bots.IDs {
+		if id == "" {
+			return nil, fmt.Errorf("security.bots.ids: empty bot id")
+		}
+		if _, dup := cfg.Security.Bots.Secrets[id]; dup {
+			return nil, fmt.Errorf("security.bots.ids: duplicate bot id %q", id)
+		}
+		env := BotSecretEnv(id)
+		botSecret := os.Getenv(env)
+		if len(botSecret) < 32 {
+			return nil, fmt.Errorf("%s env var must hold at least 32 bytes for bot %q", env, id)
+		}
+		cfg.Security.Bots.Secrets[id] = []byte(botSecret)
+	}
 
 	// --- Минимальная валидация ---
 	if cfg.App.Address == "" {
 		return nil, fmt.Errorf("app.adress is required")
 	}
-	if cfg.App.Dsn == "" {
+	switch cfg.App.Storage {
+	case "":
+		cfg.App.Storage = StoragePostgres
+	case StoragePostgres, StorageMemory:
+	default:
+		return nil, fmt.Errorf("app.storage must be postgres or memory")
+	}
+	if cfg.App.Storage == StoragePostgres && cfg.App.Dsn == "" {
 		return nil, fmt.Errorf("app.dsn is required")
 	}
 
+	switch cfg.App.TxIsolation {
+	case "", "read_committed", "repeatable_read", "serializable":
+	default:
+		return nil, fmt.Errorf("app.tx_isolation must be read_committed, repeatable_read or serializable")
+	}
+	if err := validateTLS(cfg.App.TLS); err != nil {
+		return nil, err
+	}
+	if (cfg.App.GrpcWeb || len(cfg.App.CORS.AllowedOrigins) > 0) && cfg.App.GatewayAddress == "" {
+		return nil, fmt.Errorf("app.grpc_web and app.cors require app.gateway_address")
+	}
+	if cfg.App.CORS.AllowCredentials && slices.Contains(cfg.App.CORS.AllowedOrigins, "*") {
+		return nil, fmt.Errorf("app.cors.allow_credentials cannot be used with allowed_origins \"*\"")
+	}
+	if cfg.App.TxMaxRetries < 0 {
+		return nil, fmt.Errorf("app.tx_max_retries must be >= 0")
+	}
+	if cfg.App.CrashLogSize < 0 {
+		return nil, fmt.Errorf("app.crash_log_size must be >= 0")
+	}
+	if cfg.App.CrashLogSize > 0 && cfg.App.AdminAddress == "" {
+		return nil, fmt.Errorf("app.crash_log_size requires app.admin_address")
+	}
+
 	ttl := cfg.Security.Tokener.TTL.Duration()
 	if ttl <= 0 {
 		return nil, fmt.Errorf("security.tokener.ttl_sec must be > 0")
@@ -111,6 +355,59 @@ func NewConfig(fileCfg string) (*Config, error) {
 		return nil, fmt.Errorf("security.tokener.clock_skew_sec must be >= 0")
 	}
 
+	switch cfg.Security.WebSession.SameSite {
+	case "", "strict", "lax":
+	case "none":
+		if cfg.Security.WebSession.InsecureCookie {
+			return nil, fmt.Errorf("security.web_session.same_site none requires Secure cookies")
+		}
+	default:
+		return nil, fmt.Errorf("security.web_session.same_site must be strict, lax or none")
+	}
+
+	if cfg.Security.TgLinkCodeTtlMinute < 0 {
+		return nil, fmt.Errorf("security.tg_link_code_ttl_min must be >= 0")
+	}
+	if cfg.Security.TgLinkCodeTtlMinute == 0 {
+		cfg.Security.TgLinkCodeTtlMinute = 10
+	}
+
+	switch cfg.Tracing.Exporter {
+	case "":
+		cfg.Tracing.Exporter = "none"
+	case "none", "otlp":
+	default:
+		return nil, fmt.Errorf("tracing.exporter must be none or otlp")
+	}
+	if cfg.Tracing.SampleRatio < 0 || cfg.Tracing.SampleRatio > 1 {
+		return nil, fmt.Errorf("tracing.sample_ratio must be in [0, 1]")
+	}
+
+	if cfg.Notify.SMTP.Host != "" && cfg.Notify.SMTP.From == "" {
+		return nil, fmt.Errorf("notify.smtp.from is required when notify.smtp.host is set")
+	}
+
+	switch cfg.Outbox.Publisher {
+	case "":
+		cfg.Outbox.Publisher = "log"
+	case "log":
+	case "nats":
+		if cfg.Outbox.NATS.URL == "" {
+			return nil, fmt.Errorf("outbox.nats.url is required when outbox.publisher is nats")
+		}
+	default:
+		return nil, fmt.Errorf("outbox.publisher must be log or nats")
+	}
+	if cfg.Outbox.BatchSize < 0 || cfg.Outbox.MaxAttempts < 0 {
+		return nil, fmt.Errorf("outbox.batch_size/max_attempts must be >= 0")
+	}
+	if cfg.Webhooks.MaxAttempts < 0 {
+		return nil, fmt.Errorf("webhooks.max_attempts must be >= 0")
+	}
+	if cfg.Janitor.BatchSize < 0 {
+		return nil, fmt.Errorf("janitor.batch_size must be >= 0")
+	}
+
 	ph := cfg.Security.PasswordHash
 	if ph.Algorithm == "" {
 		return nil, fmt.Errorf("security.password_hash.algorithm is required")
@@ -126,3 +423,21 @@ func NewConfig(fileCfg string) (*Config, error) {
 
 	return &cfg, nil
 }
+
+func validateTLS(t TLS) error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("app.tls.cert_file and app.tls.key_file must be set together")
+	}
+	if t.ClientCAFile != "" && t.CertFile == "" {
+		return fmt.Errorf("app.tls.client_ca_file requires app.tls.cert_file")
+	}
+	if (t.RequireClientCert || len(t.ServiceIdentities) > 0) && t.ClientCAFile == "" {
+		return fmt.Errorf("app.tls.require_client_cert and service_identities require app.tls.client_ca_file")
+	}
+	for id, methods := range t.ServiceIdentities {
+		if id == "" || len(methods) == 0 {
+			return fmt.Errorf("app.tls.service_identities: identity and its methods must not be empty")
+		}
+	}
+	return nil
+}
@@ -1,22 +1,45 @@
 package modelerrors
 
-
 var (
-	ErrInvalidCredentials = errorString("invalid credentials")
-	ErrEmailTaken         = errorString("email already taken")
-	ErrLinkCodeInvalid    = errorString("link code invalid")
-	ErrLinkCodeExpired    = errorString("link code expired")
-	ErrLinkCodeUsed       = errorString("link code already used")
+	ErrInvalidCredentials    = errorString("invalid credentials")
+	ErrEmailTaken            = errorString("email already taken")
+	ErrEmailInvalid          = errorString("email invalid")
+	ErrLinkCodeInvalid       = errorString("link code invalid")
+	ErrLinkCodeExpired       = errorString("link code expired")
+	ErrLinkCodeUsed          = errorString("link code already used")
 	ErrTelegramAlreadyLinked = errorString("telegram already linked")
-	ErrUnauthorized       = errorString("unauthorized")
-	ErrForbidden          = errorString("forbidden")
-	ErrBadBotSignature    = errorString("bad bot signature")
-	ErrReplay             = errorString("replay detected")
+	ErrUnauthorized          = errorString("unauthorized")
+	ErrForbidden             = errorString("forbidden")
+	ErrBadBotSignature       = errorString("bad bot signature")
+	ErrReplay                = errorString("replay detected")
+	ErrUserBlocked           = errorString("user blocked")
+	ErrUserNotFound          = errorString("user not found")
+	ErrTelegramNotLinked     = errorString("telegram not linked")
+	ErrWebhookNotFound       = errorString("webhook not found")
+	ErrMergeNotAllowed       = errorString("accounts cannot be merged")
+	ErrTxConflict            = errorString("transaction conflict") // повторы исчерпаны, запрос можно повторить
 )
 
 type errorString string
+
 func (e errorString) Error() string { return string(e) }
 
 var (
 	ErrNoRows = errorString("no rows")
-)
\ No newline at end of file
+)
+
+// PasswordViolation — нарушенное правило парольной политики.
+type PasswordViolation struct {
+	Rule        string // код правила, например min_length
+	Description string // для пользователя: "must be at least 8 characters"
+	Limit       int    // порог правила (min_length, max_length, min_classes), иначе 0
+}
+
+// PasswordPolicyError — пароль не прошёл политику; перечислены все нарушения, а не первое.
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "password does not meet policy"
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Типы событий аудита.
+const (
+	AuditUserRegistered      = "user.registered"
+	AuditLoginSucceeded      = "user.login_succeeded"
+	AuditLoginFailed         = "user.login_failed"
+	AuditPasswordChanged     = "user.password_changed"
+	AuditSessionsRevoked     = "user.sessions_revoked"
+	AuditLinkCodeCreated     = "telegram.link_code_created"
+	AuditTelegramLinked      = "telegram.linked"
+	AuditAccountsMerged      = "user.accounts_merged"
+	AuditAdminUserBlocked    = "admin.user_blocked"
+	AuditAdminUserUnblocked  = "admin.user_unblocked"
+	AuditAdminForceLogout    = "admin.force_logout"
+	AuditAdminUserDeleted    = "admin.user_deleted"
+	AuditAdminMfaReset       = "admin.mfa_reset"
+	AuditAdminWebhookCreated = "admin.webhook_created"
+	AuditAdminWebhookDeleted = "admin.webhook_deleted"
+)
+
+type AuditEvent struct {
+	ID   int64
+	Type string
+
+	ActorUserID  int32  // кто сделал, 0 — аноним или бот
+	ActorBotID   string // бот, если действие пришло с bot-signature
+	TargetUserID int32  // над кем, 0 — неизвестно (например, логин с несуществующим email)
+
+	IP        string
+	UserAgent string
+	RequestID string
+	Details   map[string]string
+
+	OccurredAt time.Time
+}
+
+type AuditFilter struct {
+	UserID   int32     // actor или target, 0 — все
+	From     time.Time // включительно, zero — без ограничения
+	To       time.Time // не включительно, zero — без ограничения
+	BeforeID int64     // курсор: id последнего события предыдущей страницы
+	Limit    int32
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+const (
+	LoginMethodPassword = "password"
+	LoginMethodTelegram = "telegram"
+)
+
+// LoginRecord — запись истории успешных входов.
+type LoginRecord struct {
+	ID                int64
+	UserID            int32
+	Method            string
+	IP                string
+	UserAgent         string
+	DeviceName        string // "Chrome, Windows"
+	DeviceFingerprint string
+	CreatedAt         time.Time
+}
+
+// LoginSeen — встречались ли раньше устройство и IP этого входа.
+type LoginSeen struct {
+	HasHistory bool
+	DeviceSeen bool
+	IPSeen     bool
+}
+
+// LoginNotice — уведомление о входе с нового устройства или IP.
+type LoginNotice struct {
+	UserID     int32
+	Email      string
+	ChatIDs    []int64 // привязанные Telegram-чаты
+	Method     string
+	IP         string
+	DeviceName string
+	NewDevice  bool
+	NewIP      bool
+	At         time.Time
+}
+
+type BotMessage struct {
+	ID        int64
+	ChatID    int64
+	Text      string
+	CreatedAt time.Time
+}
@@ -1,8 +1,21 @@
 package models
 
+import "time"
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+const ProviderTelegram = "telegram"
+
 type AuthTokens struct {
 	AccessToken  string
 	ExpiresInSec int64
+
+	// только у web-сессии: refresh token и сколько он ещё действует
+	RefreshToken        string
+	RefreshExpiresInSec int64
 }
 
 type TelegramProfile struct {
@@ -22,6 +35,100 @@ type BotMeta struct {
 
 type User struct {
 	ID           int32
-	Email        string
+	Email        string // как ввёл пользователь
 	HashPassword string
+	Role         string
+	BlockedAt    time.Time // zero — не заблокирован
+	CreatedAt    time.Time
+
+	EmailNormalized string // ключ уникальности, см. emailnorm
+}
+
+func (u User) Blocked() bool {
+	return !u.BlockedAt.IsZero()
+}
+
+// MergeProof — доказательство владения вторым аккаунтом: его access token или link-код,
+// выпущенный из него (CreateTelegramLinkCode).
+type MergeProof struct {
+	AccessToken string
+	LinkCode    string
+}
+
+// MergeResult — что перенесено со второго аккаунта на основной.
+type MergeResult struct {
+	MergedUserID int32
+	Identities   int64
+	Sessions     int64
+	Logins       int64
+}
+
+type Identity struct {
+	ID             int64
+	UserID         int32
+	Provider       string
+	ProviderUserID string
+	Username       string
+	FirstName      string
+	LastName       string
+	ChatID         int64
+	CreatedAt      time.Time
+}
+
+// WithTelegramProfile — identity с актуальными данными из Telegram; changed=false,
+// если обновлять нечего.
+func (i Identity) WithTelegramProfile(tg TelegramProfile) (updated Identity, changed bool) {
+	updated = i
+	updated.Username = tg.Username
+	updated.FirstName = tg.FirstName
+	updated.LastName = tg.LastName
+	updated.ChatID = tg.ChatID
+	return updated, updated != i
+}
+
+type Session struct {
+	ID        string // jti access token'а
+	UserID    int32
+	ExpiresAt time.Time
+	RevokedAt time.Time // zero — активна
+	CreatedAt time.Time
+
+	RefreshTokenHash []byte // sha256 refresh token'а, только у web-сессии
+}
+
+// RefreshSessionState — web-сессия, найденная по refresh token'у.
+type RefreshSessionState struct {
+	SessionID   string
+	UserID      int32
+	ExpiresAt   time.Time
+	Revoked     bool
+	UserBlocked bool
+	Current     bool // false — предъявлен уже ротированный (повторно использованный) токен
+}
+
+// SessionState — всё, что нужно для проверки access token'а.
+type SessionState struct {
+	UserID      int32
+	ExpiresAt   time.Time
+	Revoked     bool
+	UserBlocked bool
+}
+
+type UserFilter struct {
+	AfterID    int32  // курсор: id последнего пользователя предыдущей страницы
+	EmailQuery string // поиск по подстроке email, пусто — без фильтра
+	Limit      int32
+}
+
+type UserDetails struct {
+	User       User
+	Identities []Identity
+	Sessions   []Session
+}
+
+type LinkCode struct {
+	Code      string
+	UserID    int32
+	ExpiresAt time.Time
+	UsedAt    time.Time // zero — не использован
 }
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Доменные события для остальной платформы BotTrade (публикуются через outbox).
+const (
+	EventUserRegistered  = "user.registered"
+	EventTelegramLinked  = "telegram.linked"
+	EventUserBlocked     = "user.blocked"
+	EventPasswordChanged = "password.changed"
+	EventUserMerged      = "user.merged"
+)
+
+// EventTypes — все доменные события; по ним проверяются фильтры подписок.
+var EventTypes = []string{
+	EventUserRegistered,
+	EventTelegramLinked,
+	EventUserBlocked,
+	EventPasswordChanged,
+	EventUserMerged,
+}
+
+// UserEvent — payload доменного события, сериализуется в JSON.
+type UserEvent struct {
+	UserID         int32     `json:"user_id"`
+	Email          string    `json:"email,omitempty"`
+	TelegramUserID string    `json:"telegram_user_id,omitempty"`
+	MergedUserID   int32     `json:"merged_user_id,omitempty"` // user.merged: удалённый аккаунт, его ссылки — на UserID
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// OutboxEvent — строка outbox, взятая relay-воркером на публикацию.
+type OutboxEvent struct {
+	ID          int64
+	Type        string
+	AggregateID string
+	Payload     []byte // JSON
+	Attempts    int32  // с учётом текущей
+	CreatedAt   time.Time
+}
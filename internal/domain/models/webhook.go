@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed" // исчерпаны попытки
+)
+
+// Webhook — подписка интеграции на доменные события.
+type Webhook struct {
+	ID         int64
+	URL        string
+	EventTypes []string // пусто — все события; "user.*" — по префиксу
+	Secret     string
+	CreatedAt  time.Time
+}
+
+// WebhookDelivery — запись журнала доставок.
+type WebhookDelivery struct {
+	ID        int64
+	WebhookID int64
+	EventID   int64 // id события outbox
+	EventType string
+	Payload   []byte
+
+	Status         string
+	Attempts       int32
+	NextAttemptAt  time.Time
+	LastStatusCode int32 // 0 — ответа не было
+	LastError      string
+	DeliveredAt    time.Time
+	CreatedAt      time.Time
+}
+
+// WebhookJob — доставка, взятая воркером, вместе с адресом и ключом подписки.
+type WebhookJob struct {
+	Delivery WebhookDelivery
+	URL      string
+	Secret   string
+}
+
+type WebhookDeliveryFilter struct {
+	WebhookID int64 // 0 — все подписки
+	BeforeID  int64
+	Limit     int32
+}
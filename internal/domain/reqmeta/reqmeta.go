@@ -0,0 +1,45 @@
+package reqmeta
+
+import "context"
+
+// Meta — сведения о вызывающей стороне, нужные сервисному слою (аудит, логи).
+// Заполняется интерцепторами транспорта, сервис только читает.
+type Meta struct {
+	RequestID string
+	Method    string // полное имя gRPC-метода
+	IP        string
+	UserAgent string
+	Locale    string // язык текстов ошибок: ru | en
+
+	UserID int32  // пользователь из JWT, 0 — не аутентифицирован
+	BotID  string // бот из bot-signature или сервис из клиентского сертификата (mTLS)
+}
+
+type ctxKey struct{}
+
+func With(ctx context.Context, m Meta) context.Context {
+	return context.WithValue(ctx, ctxKey{}, m)
+}
+
+func From(ctx context.Context) Meta {
+	m, _ := ctx.Value(ctxKey{}).(Meta)
+	return m
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	m := From(ctx)
+	m.RequestID = requestID
+	return With(ctx, m)
+}
+
+func WithUserID(ctx context.Context, userID int32) context.Context {
+	m := From(ctx)
+	m.UserID = userID
+	return With(ctx, m)
+}
+
+func WithBotID(ctx context.Context, botID string) context.Context {
+	m := From(ctx)
+	m.BotID = botID
+	return With(ctx, m)
+}
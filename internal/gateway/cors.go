@@ -0,0 +1,142 @@
+package gateway
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSOptions — доступ браузера с чужих origin. Пустой AllowedOrigins — CORS выключен.
+type CORSOptions struct {
+	AllowedOrigins   []string // точные origin; "*" — любой
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+var (
+	defaultCORSMethods = []string{http.MethodGet, http.MethodPost}
+	// заголовки запросов REST и gRPC-Web (x-grpc-web, x-user-agent, grpc-timeout)
+	defaultCORSHeaders = []string{
+		"authorization", "content-type", "accept-language", "x-locale", "x-request-id", "x-csrf-token",
+		"traceparent", "tracestate",
+		"x-grpc-web", "x-user-agent", "grpc-timeout",
+	}
+	// без них браузер не увидит статус gRPC-Web, details ошибки и request id
+	defaultCORSExposed = []string{"grpc-status", "grpc-message", "grpc-status-details-bin", "x-request-id"}
+)
+
+const defaultCORSMaxAge = 10 * time.Minute
+
+type cors struct {
+	anyOrigin   bool
+	origins     []string
+	methods     string
+	headers     string
+	exposed     string
+	credentials bool
+	maxAge      string
+}
+
+func newCORS(opts CORSOptions) *cors {
+	c := &cors{
+		anyOrigin:   slices.Contains(opts.AllowedOrigins, "*"),
+		origins:     opts.AllowedOrigins,
+		methods:     strings.Join(orDefault(opts.AllowedMethods, defaultCORSMethods), ", "),
+		headers:     strings.Join(orDefault(opts.AllowedHeaders, defaultCORSHeaders), ", "),
+		exposed:     strings.Join(orDefault(opts.ExposedHeaders, defaultCORSExposed), ", "),
+		credentials: opts.AllowCredentials,
+		maxAge:      strconv.Itoa(int(defaultCORSMaxAge / time.Second)),
+	}
+	if opts.MaxAge > 0 {
+		c.maxAge = strconv.Itoa(int(opts.MaxAge / time.Second))
+	}
+	return c
+}
+
+func orDefault(v, def []string) []string {
+	if len(v) == 0 {
+		return def
+	}
+	return v
+}
+
+func (c *cors) wrap(next http.Handler) http.Handler {
+	if len(c.origins) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Add("Vary", "Origin")
+
+		origin := r.Header.Get("Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if origin == "" || !c.allowed(origin) {
+			if preflight {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h.Set("Access-Control-Allow-Origin", origin)
+		if c.credentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+		if preflight {
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+			h.Set("Access-Control-Allow-Methods", c.methods)
+			h.Set("Access-Control-Allow-Headers", c.headers)
+			h.Set("Access-Control-Max-Age", c.maxAge)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(&exposeWriter{ResponseWriter: w, exposed: c.exposed}, r)
+	})
+}
+
+func (c *cors) allowed(origin string) bool {
+	return c.anyOrigin || slices.Contains(c.origins, origin)
+}
+
+// exposeWriter выставляет Access-Control-Expose-Headers в момент отправки заголовков:
+// обёртка gRPC-Web перезаписывает его своим списком уже после вызова обработчика.
+type exposeWriter struct {
+	http.ResponseWriter
+	exposed     string
+	wroteHeader bool
+}
+
+func (w *exposeWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.Header().Set("Access-Control-Expose-Headers", w.exposed)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *exposeWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *exposeWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if !w.wroteHeader {
+			w.WriteHeader(http.StatusOK)
+		}
+		f.Flush()
+	}
+}
+
+func (w *exposeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,246 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/IvanOplesnin/BotTradeService.git/gen/authv1"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/grpcerr"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/grpcutil"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/i18n"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// maxBodyBytes — предел тела запроса; запросы AuthService маленькие.
+const maxBodyBytes = 1 << 20
+
+// forwardHeaders — заголовки HTTP, которые уходят в metadata gRPC как есть:
+// авторизация (JWT, bot-signature), язык ответов, request id и W3C trace context.
+var forwardHeaders = []string{
+	"authorization",
+	"x-bot-id", "x-ts", "x-nonce", "x-signature",
+	"x-locale", "accept-language",
+	"x-request-id",
+	"traceparent", "tracestate",
+}
+
+var (
+	unmarshalOpts = protojson.UnmarshalOptions{}
+	marshalOpts   = protojson.MarshalOptions{EmitUnpopulated: true}
+)
+
+// Gateway — REST/JSON поверх AuthService. Запросы идут через обычный gRPC-клиент,
+// поэтому проходят всю цепочку интерцепторов сервера: авторизацию, метаданные, ошибки.
+type Gateway struct {
+	conn              grpc.ClientConnInterface
+	trustProxyHeaders bool
+	webSession        WebSessionOptions
+	mux               *http.ServeMux
+	openAPI           []byte
+}
+
+type GatewayDeps struct {
+	// Conn — клиент к gRPC-серверу этого же процесса
+	Conn grpc.ClientConnInterface
+	// TrustProxyHeaders — передавать x-forwarded-for/x-real-ip клиента дальше
+	TrustProxyHeaders bool
+	// WebSession — cookie эндпоинтов /v1/web/* (вход браузера без токенов в localStorage)
+	WebSession WebSessionOptions
+}
+
+func New(deps GatewayDeps) (*Gateway, error) {
+	g := &Gateway{
+		conn:              deps.Conn,
+		trustProxyHeaders: deps.TrustProxyHeaders,
+		webSession:        deps.WebSession,
+		mux:               http.NewServeMux(),
+	}
+
+	svc := authv1.File_auth_proto.Services().ByName("AuthService")
+	if svc == nil {
+		return nil, fmt.Errorf("gateway: AuthService descriptor not found")
+	}
+	for _, r := range authRoutes {
+		md := svc.Methods().ByName(protoreflect.Name(r.rpc))
+		if md == nil {
+			return nil, fmt.Errorf("gateway: %s has no method %s", svc.FullName(), r.rpc)
+		}
+		h, err := g.handler(r, md)
+		if err != nil {
+			return nil, err
+		}
+		g.mux.Handle(r.httpMethod+" "+r.path, h)
+	}
+	g.registerWebSession()
+
+	openAPI, err := OpenAPI()
+	if err != nil {
+		return nil, err
+	}
+	g.openAPI = openAPI
+	g.mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(g.openAPI)
+	})
+	return g, nil
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+func (g *Gateway) handler(rt route, md protoreflect.MethodDescriptor) (http.Handler, error) {
+	in, err := protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName())
+	if err != nil {
+		return nil, fmt.Errorf("gateway: %s: %w", md.FullName(), err)
+	}
+	out, err := protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+	if err != nil {
+		return nil, fmt.Errorf("gateway: %s: %w", md.FullName(), err)
+	}
+	fullMethod := "/" + string(md.Parent().FullName()) + "/" + string(md.Name())
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := in.New().Interface()
+		if err := decodeRequest(r, rt.httpMethod, req); err != nil {
+			writeError(w, grpcerr.Localize(err, requestLocale(r)))
+			return
+		}
+		if err := rejectWebSession(req); err != nil {
+			writeError(w, grpcerr.Localize(err, requestLocale(r)))
+			return
+		}
+
+		resp := out.New().Interface()
+		if err := g.invoke(w, r, fullMethod, req, resp); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeMessage(w, r, resp)
+	}), nil
+}
+
+// invoke вызывает метод через gRPC и переносит request id сервера в заголовок X-Request-Id
+// ответа, в том числе при ошибке.
+func (g *Gateway) invoke(w http.ResponseWriter, r *http.Request, method string, req, resp proto.Message) error {
+	var header metadata.MD
+	err := g.conn.Invoke(g.outgoingContext(r), method, req, resp, grpc.Header(&header))
+	if id := grpcutil.GetMDString(header, "x-request-id"); id != "" {
+		w.Header().Set("X-Request-Id", id)
+	}
+	return err
+}
+
+// outgoingContext переносит заголовки клиента в metadata. IP и user agent клиента
+// передаются отдельно: для gRPC-сервера собеседник — сам шлюз.
+func (g *Gateway) outgoingContext(r *http.Request) context.Context {
+	md := metadata.MD{}
+	for _, h := range forwardHeaders {
+		if v := r.Header.Values(h); len(v) > 0 {
+			md.Set(h, v...)
+		}
+	}
+	if ua := r.UserAgent(); ua != "" {
+		md.Set(grpcutil.GatewayUserAgentKey, ua)
+	}
+
+	remoteIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remoteIP = r.RemoteAddr
+	}
+	xff := remoteIP
+	if g.trustProxyHeaders {
+		if prev := r.Header.Get("X-Forwarded-For"); prev != "" {
+			xff = prev + ", " + remoteIP
+		} else if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+			xff = realIP
+		}
+	}
+	md.Set("x-forwarded-for", xff)
+
+	return metadata.NewOutgoingContext(r.Context(), md)
+}
+
+// decodeRequest: у POST — JSON-тело (пустое тело — пустой запрос), у GET — query-параметры.
+func decodeRequest(r *http.Request, httpMethod string, req proto.Message) error {
+	if httpMethod == http.MethodGet {
+		return decodeQuery(r, req.ProtoReflect())
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes+1))
+	if err != nil {
+		return grpcerr.New(codes.InvalidArgument, grpcerr.ReasonFieldInvalid, "request body is unreadable")
+	}
+	if len(body) > maxBodyBytes {
+		return grpcerr.New(codes.InvalidArgument, grpcerr.ReasonFieldTooLong, "request body is too large")
+	}
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return nil
+	}
+	if err := unmarshalOpts.Unmarshal(body, req); err != nil {
+		return grpcerr.New(codes.InvalidArgument, grpcerr.ReasonFieldInvalid, "request body is not valid JSON: "+err.Error())
+	}
+	return nil
+}
+
+// decodeQuery заполняет скалярные поля запроса; имя — json (pageSize) или proto (page_size).
+func decodeQuery(r *http.Request, msg protoreflect.Message) error {
+	fields := msg.Descriptor().Fields()
+	for key, values := range r.URL.Query() {
+		fd := fields.ByJSONName(key)
+		if fd == nil {
+			fd = fields.ByName(protoreflect.Name(key))
+		}
+		if fd == nil || fd.IsList() || fd.IsMap() || len(values) == 0 {
+			return grpcerr.InvalidField(key, grpcerr.ReasonFieldInvalid, "unknown query parameter "+key)
+		}
+
+		v, err := parseScalar(fd, values[len(values)-1])
+		if err != nil {
+			return grpcerr.InvalidField(string(fd.Name()), grpcerr.ReasonFieldInvalid, string(fd.Name())+" is invalid")
+		}
+		msg.Set(fd, v)
+	}
+	return nil
+}
+
+func parseScalar(fd protoreflect.FieldDescriptor, s string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(s)
+		return protoreflect.ValueOfBool(b), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(s, 10, 32)
+		return protoreflect.ValueOfInt32(int32(n)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(s, 10, 64)
+		return protoreflect.ValueOfInt64(n), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(s, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(n)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(s, 10, 64)
+		return protoreflect.ValueOfUint64(n), err
+	default:
+		return protoreflect.Value{}, fmt.Errorf("unsupported query field kind %s", fd.Kind())
+	}
+}
+
+// requestLocale — язык для ошибок самого шлюза (до gRPC-вызова).
+func requestLocale(r *http.Request) string {
+	return i18n.Resolve(r.Header.Get("X-Locale"), "", strings.Join(r.Header.Values("Accept-Language"), ","))
+}
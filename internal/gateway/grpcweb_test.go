@@ -0,0 +1,295 @@
+package gateway
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/IvanOplesnin/BotTradeService.git/gen/authv1"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/grpcerr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+const testOrigin = "https://app.example"
+
+// stubAuth: Register возвращает authorization из metadata и email, Login — ошибку с ErrorInfo.
+type stubAuth struct {
+	authv1.UnimplementedAuthServiceServer
+}
+
+func (stubAuth) Register(ctx context.Context, req *authv1.RegisterRequest) (*authv1.AuthResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return &authv1.AuthResponse{
+		AccessToken:  strings.Join(md.Get("authorization"), ",") + "|" + req.GetEmail(),
+		ExpiresInSec: 900,
+	}, nil
+}
+
+func (stubAuth) Login(context.Context, *authv1.LoginRequest) (*authv1.AuthResponse, error) {
+	return nil, grpcerr.New(codes.Unauthenticated, grpcerr.ReasonInvalidCredentials, "invalid credentials")
+}
+
+func newTestHTTPServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := grpc.NewServer()
+	authv1.RegisterAuthServiceServer(server, stubAuth{})
+
+	rest := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Handler", "rest")
+		w.WriteHeader(http.StatusNotFound)
+	})
+	srv := httptest.NewServer(NewHTTPHandler(HTTPDeps{
+		REST:    rest,
+		GRPCWeb: server,
+		CORS:    CORSOptions{AllowedOrigins: []string{testOrigin}},
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+type webResponse struct {
+	http     *http.Response
+	messages [][]byte
+	trailers textproto.MIMEHeader
+}
+
+// grpcWebCall — минимальный клиент gRPC-Web: один кадр запроса, разбор кадров данных и трейлеров.
+func grpcWebCall(t *testing.T, url, method string, req proto.Message, text bool, header http.Header) webResponse {
+	t.Helper()
+	payload, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	body := frame(0x00, payload)
+	contentType := "application/grpc-web+proto"
+	if text {
+		body = []byte(base64.StdEncoding.EncodeToString(body))
+		contentType = "application/grpc-web-text"
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, url+method, bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	for k, v := range header {
+		httpReq.Header[k] = v
+	}
+	httpReq.Header.Set("Content-Type", contentType)
+	httpReq.Header.Set("X-Grpc-Web", "1")
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	if text {
+		if raw, err = decodeWebText(raw); err != nil {
+			t.Fatalf("decode grpc-web-text: %v", err)
+		}
+	}
+
+	out := webResponse{http: resp, trailers: textproto.MIMEHeader{}}
+	for len(raw) > 0 {
+		if len(raw) < 5 {
+			t.Fatalf("truncated frame header: %d bytes", len(raw))
+		}
+		flag, n := raw[0], binary.BigEndian.Uint32(raw[1:5])
+		if uint32(len(raw)-5) < n {
+			t.Fatalf("truncated frame: want %d bytes, have %d", n, len(raw)-5)
+		}
+		data := raw[5 : 5+n]
+		raw = raw[5+n:]
+
+		if flag&0x80 == 0 {
+			out.messages = append(out.messages, data)
+			continue
+		}
+		tr, err := textproto.NewReader(bufio.NewReader(bytes.NewReader(append(data, "\r\n"...)))).ReadMIMEHeader()
+		if err != nil && err != io.EOF {
+			t.Fatalf("parse trailers %q: %v", data, err)
+		}
+		for k, v := range tr {
+			out.trailers[k] = v
+		}
+	}
+	return out
+}
+
+func frame(flag byte, payload []byte) []byte {
+	b := make([]byte, 5, 5+len(payload))
+	b[0] = flag
+	binary.BigEndian.PutUint32(b[1:], uint32(len(payload)))
+	return append(b, payload...)
+}
+
+// decodeWebText: ответ grpc-web-text может быть несколькими base64-блоками подряд, каждый со своим padding.
+func decodeWebText(raw []byte) ([]byte, error) {
+	var out []byte
+	for len(raw) > 0 {
+		end := len(raw)
+		if i := bytes.IndexByte(raw, '='); i >= 0 {
+			end = i
+			for end < len(raw) && raw[end] == '=' {
+				end++
+			}
+		}
+		chunk, err := base64.StdEncoding.DecodeString(string(raw[:end]))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, chunk...)
+		raw = raw[end:]
+	}
+	return out, nil
+}
+
+// status ищет grpc-status сначала в трейлерах, затем в заголовках (trailers-only ответ).
+func (r webResponse) status(key string) string {
+	if v := r.trailers.Get(key); v != "" {
+		return v
+	}
+	return r.http.Header.Get(key)
+}
+
+func TestGRPCWebUnary(t *testing.T) {
+	srv := newTestHTTPServer(t)
+
+	for _, text := range []bool{false, true} {
+		resp := grpcWebCall(t, srv.URL, authv1.AuthService_Register_FullMethodName,
+			&authv1.RegisterRequest{Email: "a@b.co", Password: "secret"}, text,
+			http.Header{"Authorization": {"Bearer tok"}, "Origin": {testOrigin}})
+
+		if resp.http.StatusCode != http.StatusOK {
+			t.Fatalf("text=%v: HTTP %d", text, resp.http.StatusCode)
+		}
+		if got := resp.status("Grpc-Status"); got != "0" {
+			t.Fatalf("text=%v: grpc-status = %q, want 0", text, got)
+		}
+		if len(resp.messages) != 1 {
+			t.Fatalf("text=%v: got %d messages, want 1", text, len(resp.messages))
+		}
+		var out authv1.AuthResponse
+		if err := proto.Unmarshal(resp.messages[0], &out); err != nil {
+			t.Fatalf("text=%v: unmarshal response: %v", text, err)
+		}
+		if out.GetAccessToken() != "Bearer tok|a@b.co" || out.GetExpiresInSec() != 900 {
+			t.Errorf("text=%v: unexpected response %v", text, &out)
+		}
+
+		if got := resp.http.Header.Get("Access-Control-Allow-Origin"); got != testOrigin {
+			t.Errorf("text=%v: Access-Control-Allow-Origin = %q", text, got)
+		}
+		if got := resp.http.Header.Get("Access-Control-Expose-Headers"); got != "grpc-status, grpc-message, grpc-status-details-bin, x-request-id" {
+			t.Errorf("text=%v: Access-Control-Expose-Headers = %q", text, got)
+		}
+	}
+}
+
+func TestGRPCWebErrorDetails(t *testing.T) {
+	srv := newTestHTTPServer(t)
+
+	resp := grpcWebCall(t, srv.URL, authv1.AuthService_Login_FullMethodName,
+		&authv1.LoginRequest{Email: "a@b.co", Password: "wrong"}, false, nil)
+
+	if got := resp.status("Grpc-Status"); got != "16" {
+		t.Fatalf("grpc-status = %q, want 16 (Unauthenticated)", got)
+	}
+	if got := resp.status("Grpc-Message"); got != "invalid credentials" {
+		t.Errorf("grpc-message = %q", got)
+	}
+
+	bin := resp.status("Grpc-Status-Details-Bin")
+	raw, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(bin, "="))
+	if err != nil {
+		t.Fatalf("decode grpc-status-details-bin %q: %v", bin, err)
+	}
+	var st spb.Status
+	if err := proto.Unmarshal(raw, &st); err != nil {
+		t.Fatalf("unmarshal status: %v", err)
+	}
+	var info errdetails.ErrorInfo
+	if len(st.GetDetails()) == 0 || st.GetDetails()[0].UnmarshalTo(&info) != nil {
+		t.Fatalf("no ErrorInfo in details: %v", st.GetDetails())
+	}
+	if info.GetReason() != grpcerr.ReasonInvalidCredentials || info.GetDomain() != grpcerr.Domain {
+		t.Errorf("unexpected ErrorInfo: %v", &info)
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	srv := newTestHTTPServer(t)
+
+	preflight := func(origin string) *http.Response {
+		req, _ := http.NewRequest(http.MethodOptions, srv.URL+authv1.AuthService_Register_FullMethodName, nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "x-grpc-web, content-type, authorization")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("preflight: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	resp := preflight(testOrigin)
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("allowed origin: HTTP %d, want 204", resp.StatusCode)
+	}
+	h := resp.Header
+	if h.Get("Access-Control-Allow-Origin") != testOrigin {
+		t.Errorf("Access-Control-Allow-Origin = %q", h.Get("Access-Control-Allow-Origin"))
+	}
+	if h.Get("Access-Control-Allow-Methods") != "GET, POST" {
+		t.Errorf("Access-Control-Allow-Methods = %q", h.Get("Access-Control-Allow-Methods"))
+	}
+	for _, want := range []string{"x-grpc-web", "content-type", "authorization", "grpc-timeout"} {
+		if !strings.Contains(h.Get("Access-Control-Allow-Headers"), want) {
+			t.Errorf("Access-Control-Allow-Headers %q has no %s", h.Get("Access-Control-Allow-Headers"), want)
+		}
+	}
+	if h.Get("Access-Control-Max-Age") != "600" {
+		t.Errorf("Access-Control-Max-Age = %q", h.Get("Access-Control-Max-Age"))
+	}
+
+	resp = preflight("https://evil.example")
+	if resp.StatusCode != http.StatusForbidden || resp.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("foreign origin: HTTP %d, Access-Control-Allow-Origin %q", resp.StatusCode, resp.Header.Get("Access-Control-Allow-Origin"))
+	}
+}
+
+func TestNonGRPCWebRequestsGoToREST(t *testing.T) {
+	srv := newTestHTTPServer(t)
+
+	resp, err := http.Post(srv.URL+"/v1/auth/register", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	resp.Body.Close()
+	if resp.Header.Get("X-Handler") != "rest" {
+		t.Errorf("REST request was not routed to REST handler")
+	}
+
+	// gRPC-Web только для authv1: чужие сервисы того же grpc.Server не отдаются
+	web := grpcWebCall(t, srv.URL, "/grpc.health.v1.Health/Check", &authv1.RegisterRequest{}, false, nil)
+	if web.http.Header.Get("X-Handler") != "rest" {
+		t.Errorf("non-authv1 gRPC-Web request was not routed to REST handler")
+	}
+}
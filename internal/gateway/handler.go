@@ -0,0 +1,43 @@
+package gateway
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/IvanOplesnin/BotTradeService.git/gen/authv1"
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"google.golang.org/grpc"
+)
+
+// HTTPDeps — всё, что обслуживает HTTP listener шлюза.
+type HTTPDeps struct {
+	REST http.Handler
+	// GRPCWeb — сервер, чьи authv1-сервисы отдаются по gRPC-Web; nil — gRPC-Web выключен
+	GRPCWeb *grpc.Server
+	CORS    CORSOptions
+}
+
+// NewHTTPHandler: gRPC-Web для authv1, остальное — REST; поверх всего общий CORS.
+func NewHTTPHandler(deps HTTPDeps) http.Handler {
+	h := deps.REST
+	if deps.GRPCWeb != nil {
+		h = grpcWebHandler(deps.GRPCWeb, deps.REST)
+	}
+	return newCORS(deps.CORS).wrap(h)
+}
+
+// grpcWebHandler отдаёт gRPC-Web запросы в grpc.Server.ServeHTTP — с той же цепочкой
+// интерцепторов, что и у нативного gRPC. Собственный CORS обёртки выключен (origin
+// не разрешён никому): preflight и заголовки CORS выставляет общий cors.
+func grpcWebHandler(server *grpc.Server, rest http.Handler) http.Handler {
+	web := grpcweb.WrapServer(server, grpcweb.WithOriginFunc(func(string) bool { return false }))
+	prefix := "/" + string(authv1.File_auth_proto.Package()) + "."
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if web.IsGrpcWebRequest(r) && strings.HasPrefix(r.URL.Path, prefix) {
+			web.ServeHTTP(w, r)
+			return
+		}
+		rest.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,250 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/IvanOplesnin/BotTradeService.git/gen/authv1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// OpenAPI строит OpenAPI 3 документ шлюза из таблицы маршрутов и дескрипторов proto,
+// поэтому он не расходится с тем, что шлюз принимает на самом деле.
+func OpenAPI() ([]byte, error) {
+	svc := authv1.File_auth_proto.Services().ByName("AuthService")
+	if svc == nil {
+		return nil, fmt.Errorf("gateway: AuthService descriptor not found")
+	}
+
+	b := &openAPIBuilder{schemas: map[string]any{"Status": statusSchema}}
+	paths := map[string]map[string]any{}
+	for _, r := range authRoutes {
+		md := svc.Methods().ByName(protoreflect.Name(r.rpc))
+		if md == nil {
+			return nil, fmt.Errorf("gateway: %s has no method %s", svc.FullName(), r.rpc)
+		}
+		if paths[r.path] == nil {
+			paths[r.path] = map[string]any{}
+		}
+		paths[r.path][strings.ToLower(r.httpMethod)] = b.operation(r, md)
+	}
+	for _, r := range webRoutes {
+		md := svc.Methods().ByName(protoreflect.Name(r.rpc))
+		if md == nil {
+			return nil, fmt.Errorf("gateway: %s has no method %s", svc.FullName(), r.rpc)
+		}
+		paths[r.path] = map[string]any{"post": b.webOperation(r.rpc, md, r.csrf)}
+	}
+
+	doc := map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "BotTrade Auth REST gateway",
+			"version": "v1",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas":         b.schemas,
+			"securitySchemes": securitySchemes,
+		},
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+type openAPIBuilder struct {
+	schemas map[string]any
+}
+
+func (b *openAPIBuilder) operation(r route, md protoreflect.MethodDescriptor) map[string]any {
+	op := map[string]any{
+		"operationId": r.rpc,
+		"tags":        []string{string(md.Parent().Name())},
+		"responses": map[string]any{
+			"200": jsonContent("OK", b.ref(md.Output())),
+			"default": jsonContent("Ошибка: google.rpc.Status с details (ErrorInfo, BadRequest, LocalizedMessage)",
+				map[string]any{"$ref": "#/components/schemas/Status"}),
+		},
+		"security": security(md),
+	}
+
+	if r.httpMethod == http.MethodGet {
+		var params []map[string]any
+		fields := md.Input().Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			params = append(params, map[string]any{
+				"name":   fd.JSONName(),
+				"in":     "query",
+				"schema": b.field(fd),
+			})
+		}
+		op["parameters"] = params
+	} else {
+		op["requestBody"] = map[string]any{
+			"required": true,
+			"content": map[string]any{
+				"application/json": map[string]any{"schema": b.ref(md.Input())},
+			},
+		}
+	}
+	return op
+}
+
+// webOperation — эндпоинт web-сессии: refresh token в cookie, а не в теле.
+func (b *openAPIBuilder) webOperation(rpc string, md protoreflect.MethodDescriptor, csrf bool) map[string]any {
+	ok := jsonContent("OK; Set-Cookie: "+RefreshCookie+" (HttpOnly) и "+CSRFCookie, b.ref(md.Output()))
+	op := map[string]any{
+		"operationId": "Web" + rpc,
+		"tags":        []string{"WebSession"},
+		"responses": map[string]any{
+			"200": ok,
+			"default": jsonContent("Ошибка: google.rpc.Status; CSRF_FAILED — 403",
+				map[string]any{"$ref": "#/components/schemas/Status"}),
+		},
+		"security": []map[string][]string{},
+	}
+	if !csrf {
+		op["requestBody"] = map[string]any{
+			"required": true,
+			"content": map[string]any{
+				"application/json": map[string]any{"schema": b.ref(md.Input())},
+			},
+		}
+		return op
+	}
+
+	op["security"] = []map[string][]string{{"refreshCookie": {}}}
+	op["parameters"] = []map[string]any{{
+		"name":        CSRFHeader,
+		"in":          "header",
+		"required":    true,
+		"description": "значение cookie " + CSRFCookie,
+		"schema":      map[string]any{"type": "string"},
+	}}
+	return op
+}
+
+// ref регистрирует схему сообщения (и вложенных) и возвращает ссылку на неё.
+func (b *openAPIBuilder) ref(msg protoreflect.MessageDescriptor) map[string]any {
+	name := string(msg.Name())
+	ref := map[string]any{"$ref": "#/components/schemas/" + name}
+	if _, ok := b.schemas[name]; ok {
+		return ref
+	}
+
+	props := map[string]any{}
+	b.schemas[name] = map[string]any{"type": "object", "properties": props}
+	fields := msg.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		props[fd.JSONName()] = b.field(fd)
+	}
+	return ref
+}
+
+func (b *openAPIBuilder) field(fd protoreflect.FieldDescriptor) map[string]any {
+	switch {
+	case fd.IsMap():
+		return map[string]any{"type": "object", "additionalProperties": b.single(fd.MapValue())}
+	case fd.IsList():
+		return map[string]any{"type": "array", "items": b.single(fd)}
+	default:
+		return b.single(fd)
+	}
+}
+
+// single — схема одного значения в кодировке protojson (64-битные целые — строки).
+func (b *openAPIBuilder) single(fd protoreflect.FieldDescriptor) map[string]any {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return map[string]any{"type": "string"}
+	case protoreflect.BytesKind:
+		return map[string]any{"type": "string", "format": "byte"}
+	case protoreflect.BoolKind:
+		return map[string]any{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]any{"type": "integer", "format": "int64", "minimum": 0}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return map[string]any{"type": "string", "format": "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]any{"type": "string", "format": "uint64"}
+	case protoreflect.FloatKind:
+		return map[string]any{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return map[string]any{"type": "number", "format": "double"}
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		names := make([]string, 0, values.Len())
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, string(values.Get(i).Name()))
+		}
+		return map[string]any{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		switch fd.Message().FullName() {
+		case "google.protobuf.Timestamp":
+			return map[string]any{"type": "string", "format": "date-time"}
+		case "google.protobuf.Duration":
+			return map[string]any{"type": "string", "example": "1.5s"}
+		}
+		return b.ref(fd.Message())
+	default:
+		return map[string]any{}
+	}
+}
+
+func jsonContent(description string, schema map[string]any) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": schema},
+		},
+	}
+}
+
+// security — требования по аннотации (bottrade.auth.v1.auth) метода.
+func security(md protoreflect.MethodDescriptor) []map[string][]string {
+	level := proto.GetExtension(md.Options(), authv1.E_Auth).(authv1.AuthLevel)
+	switch level {
+	case authv1.AuthLevel_USER, authv1.AuthLevel_ADMIN:
+		return []map[string][]string{{"bearerAuth": {}}}
+	case authv1.AuthLevel_BOT:
+		return []map[string][]string{{"botId": {}, "botTimestamp": {}, "botNonce": {}, "botSignature": {}}}
+	default:
+		return []map[string][]string{}
+	}
+}
+
+var securitySchemes = map[string]any{
+	"bearerAuth":   map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+	"botId":        map[string]any{"type": "apiKey", "in": "header", "name": "x-bot-id"},
+	"botTimestamp": map[string]any{"type": "apiKey", "in": "header", "name": "x-ts"},
+	"botNonce":     map[string]any{"type": "apiKey", "in": "header", "name": "x-nonce"},
+	"botSignature": map[string]any{
+		"type": "apiKey", "in": "header", "name": "x-signature",
+		"description": `"v1=" + hex(HMAC-SHA256(bot secret, "<x-bot-id>.<x-ts>.<x-nonce>.<gRPC full method>." + deterministic protobuf encoding of the request)). ` +
+			"x-ts is unix seconds within the allowed clock skew (5 minutes by default); each x-nonce is accepted once.",
+	},
+	"refreshCookie": map[string]any{"type": "apiKey", "in": "cookie", "name": RefreshCookie},
+}
+
+// statusSchema — google.rpc.Status в protojson; details — Any с полем @type.
+var statusSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"code":    map[string]any{"type": "integer", "format": "int32"},
+		"message": map[string]any{"type": "string"},
+		"details": map[string]any{
+			"type": "array",
+			"items": map[string]any{
+				"type":                 "object",
+				"properties":           map[string]any{"@type": map[string]any{"type": "string"}},
+				"additionalProperties": true,
+			},
+		},
+	},
+}
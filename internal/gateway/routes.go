@@ -0,0 +1,27 @@
+package gateway
+
+import "net/http"
+
+// route — REST-эндпоинт поверх RPC AuthService. Тело POST — JSON запроса (protojson),
+// у GET поля запроса передаются query-параметрами.
+type route struct {
+	httpMethod string
+	path       string
+	rpc        string // имя метода в AuthService
+}
+
+var authRoutes = []route{
+	{http.MethodPost, "/v1/auth/register", "Register"},
+	{http.MethodPost, "/v1/auth/login", "Login"},
+	{http.MethodPost, "/v1/auth/refresh", "RefreshSession"},
+	{http.MethodPost, "/v1/auth/logout", "EndSession"},
+	{http.MethodPost, "/v1/auth/password", "ChangePassword"},
+	{http.MethodGet, "/v1/auth/logins", "ListLoginHistory"},
+	{http.MethodPost, "/v1/auth/merge", "MergeAccounts"},
+
+	{http.MethodPost, "/v1/telegram/link-codes", "CreateTelegramLinkCode"},
+	{http.MethodPost, "/v1/telegram/link", "LinkTelegram"},
+	{http.MethodPost, "/v1/telegram/auth", "TelegramAuth"},
+	// pull помечает сообщения доставленными, поэтому не GET
+	{http.MethodPost, "/v1/bot/messages/pull", "PullBotMessages"},
+}
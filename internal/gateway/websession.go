@@ -0,0 +1,198 @@
+package gateway
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/IvanOplesnin/BotTradeService.git/gen/authv1"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/grpcerr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Web-сессия для браузера: refresh token лежит в HttpOnly cookie и недоступен JS,
+// access token отдаётся в теле и живёт только в памяти страницы. Запросы, которые
+// предъявляют cookie, защищены CSRF по схеме double-submit: значение cookie bt_csrf
+// (её JS прочитать может) должно совпасть с заголовком X-CSRF-Token.
+const (
+	RefreshCookie = "bt_refresh"
+	CSRFCookie    = "bt_csrf"
+	CSRFHeader    = "X-CSRF-Token"
+
+	// refresh cookie уходит только на эндпоинты web-сессии
+	webSessionPath = "/v1/web"
+)
+
+// WebSessionOptions — атрибуты cookie web-сессии.
+type WebSessionOptions struct {
+	CookieDomain string
+	SameSite     http.SameSite // по умолчанию Strict
+	// InsecureCookie — cookie без Secure, только для разработки по http
+	InsecureCookie bool
+}
+
+// webRoutes — эндпоинты web-сессии и RPC, которые за ними стоят.
+var webRoutes = []struct {
+	path string
+	rpc  string
+	csrf bool
+}{
+	// вход CSRF-токен не требует: его cookie появляется как раз здесь
+	{webSessionPath + "/login", "Login", false},
+	{webSessionPath + "/refresh", "RefreshSession", true},
+	{webSessionPath + "/logout", "EndSession", true},
+}
+
+func (g *Gateway) registerWebSession() {
+	g.mux.HandleFunc("POST "+webSessionPath+"/login", g.webLogin)
+	g.mux.HandleFunc("POST "+webSessionPath+"/refresh", g.webRefresh)
+	g.mux.HandleFunc("POST "+webSessionPath+"/logout", g.webLogout)
+}
+
+// webLogin — Login с web_session: refresh token уходит в cookie и вырезается из тела.
+func (g *Gateway) webLogin(w http.ResponseWriter, r *http.Request) {
+	req := &authv1.LoginRequest{}
+	if err := decodeRequest(r, http.MethodPost, req); err != nil {
+		writeError(w, grpcerr.Localize(err, requestLocale(r)))
+		return
+	}
+	req.WebSession = true
+
+	resp := &authv1.AuthResponse{}
+	if err := g.invoke(w, r, authv1.AuthService_Login_FullMethodName, req, resp); err != nil {
+		writeError(w, err)
+		return
+	}
+	g.writeSession(w, r, resp)
+}
+
+// rejectWebSession: web_session допустим только на /v1/web/login — через общий
+// REST-маршрут refresh token web-сессии ушёл бы в тело ответа мимо HttpOnly cookie.
+func rejectWebSession(req proto.Message) error {
+	if login, ok := req.(*authv1.LoginRequest); ok && login.GetWebSession() {
+		return grpcerr.InvalidField("web_session", grpcerr.ReasonFieldInvalid,
+			"web_session is only allowed on "+webSessionPath+"/login")
+	}
+	return nil
+}
+
+// webRefresh ротирует refresh token из cookie; отвергнутая сессия стирает cookie.
+func (g *Gateway) webRefresh(w http.ResponseWriter, r *http.Request) {
+	if err := checkCSRF(r); err != nil {
+		writeError(w, err)
+		return
+	}
+	c, err := r.Cookie(RefreshCookie)
+	if err != nil || c.Value == "" {
+		g.clearSession(w)
+		writeError(w, grpcerr.Localize(
+			grpcerr.New(codes.Unauthenticated, grpcerr.ReasonUnauthenticated, "session cookie is missing"),
+			requestLocale(r)))
+		return
+	}
+
+	req := &authv1.RefreshSessionRequest{RefreshToken: c.Value}
+	resp := &authv1.AuthResponse{}
+	if err := g.invoke(w, r, authv1.AuthService_RefreshSession_FullMethodName, req, resp); err != nil {
+		if status.Code(err) == codes.Unauthenticated {
+			g.clearSession(w)
+		}
+		writeError(w, err)
+		return
+	}
+	g.writeSession(w, r, resp)
+}
+
+// webLogout отзывает сессию и стирает cookie; без refresh cookie — просто стирает.
+func (g *Gateway) webLogout(w http.ResponseWriter, r *http.Request) {
+	if err := checkCSRF(r); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := &authv1.EndSessionResponse{Ok: true}
+	if c, err := r.Cookie(RefreshCookie); err == nil && c.Value != "" {
+		req := &authv1.EndSessionRequest{RefreshToken: c.Value}
+		if err := g.invoke(w, r, authv1.AuthService_EndSession_FullMethodName, req, resp); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	g.clearSession(w)
+	writeMessage(w, r, resp)
+}
+
+// writeSession ставит refresh cookie и новый CSRF-токен, в теле — только access token.
+func (g *Gateway) writeSession(w http.ResponseWriter, r *http.Request, resp *authv1.AuthResponse) {
+	csrf, err := newCSRFToken()
+	if err != nil {
+		writeError(w, grpcerr.Localize(grpcerr.FromError(r.Context(), err), requestLocale(r)))
+		return
+	}
+	maxAge := int(resp.GetRefreshExpiresInSec())
+
+	http.SetCookie(w, g.cookie(RefreshCookie, resp.GetRefreshToken(), webSessionPath, maxAge, true))
+	http.SetCookie(w, g.cookie(CSRFCookie, csrf, "/", maxAge, false))
+	w.Header().Set("Cache-Control", "no-store")
+
+	resp.RefreshToken = ""
+	writeMessage(w, r, resp)
+}
+
+func (g *Gateway) clearSession(w http.ResponseWriter) {
+	http.SetCookie(w, g.cookie(RefreshCookie, "", webSessionPath, -1, true))
+	http.SetCookie(w, g.cookie(CSRFCookie, "", "/", -1, false))
+}
+
+func (g *Gateway) cookie(name, value, path string, maxAge int, httpOnly bool) *http.Cookie {
+	sameSite := g.webSession.SameSite
+	if sameSite == 0 {
+		sameSite = http.SameSiteStrictMode
+	}
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   g.webSession.CookieDomain,
+		MaxAge:   maxAge,
+		Secure:   !g.webSession.InsecureCookie,
+		HttpOnly: httpOnly,
+		SameSite: sameSite,
+	}
+}
+
+// checkCSRF — double-submit: заголовок должен совпасть с cookie, которую чужой сайт
+// прочитать не может.
+func checkCSRF(r *http.Request) error {
+	header := r.Header.Get(CSRFHeader)
+	c, err := r.Cookie(CSRFCookie)
+	if err != nil || c.Value == "" || header == "" ||
+		subtle.ConstantTimeCompare([]byte(header), []byte(c.Value)) != 1 {
+		return grpcerr.Localize(
+			grpcerr.New(codes.PermissionDenied, grpcerr.ReasonCSRFFailed, "csrf token mismatch"),
+			requestLocale(r))
+	}
+	return nil
+}
+
+func newCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("read csrf token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func writeMessage(w http.ResponseWriter, r *http.Request, msg proto.Message) {
+	body, err := marshalOpts.Marshal(msg)
+	if err != nil {
+		writeError(w, grpcerr.FromError(r.Context(), err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,336 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/IvanOplesnin/BotTradeService.git/gen/authv1"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/config"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/grpcerr"
+	grpchandlers "github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/handlers"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/interceptor/authinterceptor"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/interceptor/errinterceptor"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/inmemory"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/emailnorm"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/hasher/argon2hash"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/passpolicy"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/svcauth"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/token"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	testEmail    = "web@example.com"
+	testPassword = "violet-harbor-42"
+)
+
+type nopAudit struct{}
+
+func (nopAudit) Record(context.Context, models.AuditEvent) {}
+
+type nopNotifier struct{}
+
+func (nopNotifier) NotifyNewLogin(context.Context, models.LoginNotice) {}
+
+// newWebSessionServer — шлюз поверх настоящих AuthHandler и svcauth с inmemory
+// репозиторием, как в app: gRPC-клиент ходит в сервер через bufconn.
+func newWebSessionServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	hasher, err := argon2hash.New(config.PasswordHash{
+		Algorithm: "argon2id", MemoryKiB: 8 * 1024, Iterations: 2, Parallelism: 1, SaltLen: 16, KeyLen: 32,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokener, err := token.NewTokener(config.Tokener{Secret: []byte("0123456789abcdef0123456789abcdef")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy, err := passpolicy.New(config.PasswordPolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := inmemory.New()
+	svc := svcauth.New(svcauth.AuthUsecaseDeps{
+		Hasher:  hasher,
+		Tokener: tokener,
+		Emails:  emailnorm.New(config.Email{}),
+		Policy:  policy,
+		Repo:    repo,
+		WithTx: func(ctx context.Context, fn func(svcauth.AuthRepo) error) error {
+			return repo.WithTx(ctx, func(tx *inmemory.Repo) error { return fn(tx) })
+		},
+		Audit:    nopAudit{},
+		Notifier: nopNotifier{},
+	})
+
+	auth := authinterceptor.NewAuthInterceptor(authinterceptor.AuthInterceptorDeps{TokenVerifier: svc, AdminVerifier: svc})
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(errinterceptor.NewErrInterceptor().Unary(), auth.Unary()))
+	authv1.RegisterAuthServiceServer(server, grpchandlers.NewAuthHandler(svc, 10))
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(lis) }()
+	conn, err := grpc.NewClient("passthrough:///gateway",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gw, err := New(GatewayDeps{Conn: conn})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(gw)
+	t.Cleanup(func() {
+		srv.Close()
+		conn.Close()
+		server.Stop()
+	})
+	return srv
+}
+
+// session — cookie браузера; шлём их вручную, чтобы можно было предъявить старые.
+type session struct {
+	refresh string
+	csrf    string
+	access  string
+}
+
+type httpResult struct {
+	status  int
+	cookies map[string]*http.Cookie
+	body    map[string]any
+}
+
+func post(t *testing.T, srv *httptest.Server, path, body string, header http.Header, cookies ...*http.Cookie) httpResult {
+	t.Helper()
+	return do(t, srv, http.MethodPost, path, body, header, cookies...)
+}
+
+func do(t *testing.T, srv *httptest.Server, method, path, body string, header http.Header, cookies ...*http.Cookie) httpResult {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := httpResult{status: resp.StatusCode, cookies: map[string]*http.Cookie{}}
+	for _, c := range resp.Cookies() {
+		res.cookies[c.Name] = c
+	}
+	if err := json.Unmarshal(raw, &res.body); err != nil {
+		t.Fatalf("%s %s: body %q is not JSON: %v", method, path, raw, err)
+	}
+	return res
+}
+
+// reason — ErrorInfo.reason из тела google.rpc.Status.
+func (r httpResult) reason() string {
+	details, _ := r.body["details"].([]any)
+	for _, d := range details {
+		if m, ok := d.(map[string]any); ok && strings.HasSuffix(m["@type"].(string), "ErrorInfo") {
+			reason, _ := m["reason"].(string)
+			return reason
+		}
+	}
+	return ""
+}
+
+func (r httpResult) sessionCleared(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{RefreshCookie, CSRFCookie} {
+		if c := r.cookies[name]; c == nil || c.MaxAge >= 0 || c.Value != "" {
+			t.Fatalf("cookie %s not cleared: %+v", name, c)
+		}
+	}
+}
+
+func (s session) refreshCookies() []*http.Cookie {
+	return []*http.Cookie{{Name: RefreshCookie, Value: s.refresh}, {Name: CSRFCookie, Value: s.csrf}}
+}
+
+func (s session) csrfHeader() http.Header {
+	return http.Header{CSRFHeader: {s.csrf}}
+}
+
+func webLogin(t *testing.T, srv *httptest.Server) session {
+	t.Helper()
+	reg := post(t, srv, "/v1/auth/register", `{"email":"`+testEmail+`","password":"`+testPassword+`"}`, nil)
+	if reg.status != http.StatusOK && reg.reason() != grpcerr.ReasonEmailTaken {
+		t.Fatalf("register: %d %v", reg.status, reg.body)
+	}
+	res := post(t, srv, "/v1/web/login", `{"email":"`+testEmail+`","password":"`+testPassword+`"}`, nil)
+	if res.status != http.StatusOK {
+		t.Fatalf("web login: %d %v", res.status, res.body)
+	}
+	return sessionFrom(t, res)
+}
+
+// sessionFrom проверяет атрибуты cookie и то, что refresh token не попал в тело.
+func sessionFrom(t *testing.T, res httpResult) session {
+	t.Helper()
+	refresh, csrf := res.cookies[RefreshCookie], res.cookies[CSRFCookie]
+	if refresh == nil || refresh.Value == "" || !refresh.HttpOnly || !refresh.Secure ||
+		refresh.Path != webSessionPath || refresh.SameSite != http.SameSiteStrictMode || refresh.MaxAge <= 0 {
+		t.Fatalf("refresh cookie = %+v", refresh)
+	}
+	if csrf == nil || csrf.Value == "" || csrf.HttpOnly || csrf.Path != "/" {
+		t.Fatalf("csrf cookie = %+v", csrf)
+	}
+	if rt, _ := res.body["refreshToken"].(string); rt != "" {
+		t.Fatal("refresh token leaked into response body")
+	}
+	access, _ := res.body["accessToken"].(string)
+	if access == "" {
+		t.Fatalf("no access token in %v", res.body)
+	}
+	return session{refresh: refresh.Value, csrf: csrf.Value, access: access}
+}
+
+func TestWebSessionCookieRotation(t *testing.T) {
+	srv := newWebSessionServer(t)
+	first := webLogin(t, srv)
+
+	res := post(t, srv, "/v1/web/refresh", "", first.csrfHeader(), first.refreshCookies()...)
+	if res.status != http.StatusOK {
+		t.Fatalf("refresh: %d %v", res.status, res.body)
+	}
+	second := sessionFrom(t, res)
+	if second.refresh == first.refresh || second.csrf == first.csrf {
+		t.Fatal("refresh did not rotate cookies")
+	}
+
+	// ротированный токен работает дальше, logout стирает cookie
+	res = post(t, srv, "/v1/web/refresh", "", second.csrfHeader(), second.refreshCookies()...)
+	if res.status != http.StatusOK {
+		t.Fatalf("second refresh: %d %v", res.status, res.body)
+	}
+	third := sessionFrom(t, res)
+	res = post(t, srv, "/v1/web/logout", "", third.csrfHeader(), third.refreshCookies()...)
+	if res.status != http.StatusOK {
+		t.Fatalf("logout: %d %v", res.status, res.body)
+	}
+	res.sessionCleared(t)
+	if res = post(t, srv, "/v1/web/refresh", "", third.csrfHeader(), third.refreshCookies()...); res.status != http.StatusUnauthorized {
+		t.Fatalf("refresh after logout: %d %v", res.status, res.body)
+	}
+}
+
+func TestWebSessionRefreshReuseRevokesSession(t *testing.T) {
+	srv := newWebSessionServer(t)
+	stolen := webLogin(t, srv)
+
+	res := post(t, srv, "/v1/web/refresh", "", stolen.csrfHeader(), stolen.refreshCookies()...)
+	if res.status != http.StatusOK {
+		t.Fatalf("refresh: %d %v", res.status, res.body)
+	}
+	legit := sessionFrom(t, res)
+
+	// повторное предъявление ротированного токена
+	res = post(t, srv, "/v1/web/refresh", "", stolen.csrfHeader(), stolen.refreshCookies()...)
+	if res.status != http.StatusUnauthorized {
+		t.Fatalf("reused refresh: %d %v", res.status, res.body)
+	}
+	res.sessionCleared(t)
+
+	// сессия отозвана целиком: и актуальный refresh token, и access token
+	res = post(t, srv, "/v1/web/refresh", "", legit.csrfHeader(), legit.refreshCookies()...)
+	if res.status != http.StatusUnauthorized {
+		t.Fatalf("refresh of revoked session: %d %v", res.status, res.body)
+	}
+	res.sessionCleared(t)
+	res = do(t, srv, http.MethodGet, "/v1/auth/logins", "", http.Header{"Authorization": {"Bearer " + legit.access}})
+	if res.status != http.StatusUnauthorized {
+		t.Fatalf("access token of revoked session: %d %v", res.status, res.body)
+	}
+}
+
+func TestWebSessionCSRF(t *testing.T) {
+	srv := newWebSessionServer(t)
+	s := webLogin(t, srv)
+	refreshOnly := &http.Cookie{Name: RefreshCookie, Value: s.refresh}
+	csrfCookie := &http.Cookie{Name: CSRFCookie, Value: s.csrf}
+
+	tests := []struct {
+		name    string
+		header  http.Header
+		cookies []*http.Cookie
+	}{
+		{name: "no header", cookies: s.refreshCookies()},
+		{name: "header mismatch", header: http.Header{CSRFHeader: {s.csrf + "x"}}, cookies: s.refreshCookies()},
+		{name: "no csrf cookie", header: s.csrfHeader(), cookies: []*http.Cookie{refreshOnly}},
+		{name: "empty header and cookie", header: http.Header{CSRFHeader: {""}}, cookies: []*http.Cookie{refreshOnly, {Name: CSRFCookie, Value: ""}}},
+		// заголовок подделать можно, cookie чужого сайта — нет
+		{name: "header from cookie of another session", header: http.Header{CSRFHeader: {"forged"}}, cookies: []*http.Cookie{refreshOnly, {Name: CSRFCookie, Value: "other"}}},
+	}
+	for _, tt := range tests {
+		for _, path := range []string{"/v1/web/refresh", "/v1/web/logout"} {
+			t.Run(tt.name+" "+path, func(t *testing.T) {
+				res := post(t, srv, path, "", tt.header, tt.cookies...)
+				if res.status != http.StatusForbidden || res.reason() != grpcerr.ReasonCSRFFailed {
+					t.Fatalf("status %d reason %q, want 403 %s", res.status, res.reason(), grpcerr.ReasonCSRFFailed)
+				}
+				if len(res.cookies) != 0 {
+					t.Fatalf("rejected request changed cookies: %v", res.cookies)
+				}
+			})
+		}
+	}
+
+	// отвергнутые запросы сессию не тронули
+	if res := post(t, srv, "/v1/web/refresh", "", s.csrfHeader(), refreshOnly, csrfCookie); res.status != http.StatusOK {
+		t.Fatalf("legit refresh after csrf failures: %d %v", res.status, res.body)
+	}
+}
+
+func TestRESTLoginRejectsWebSession(t *testing.T) {
+	srv := newWebSessionServer(t)
+	webLogin(t, srv) // регистрирует пользователя
+
+	res := post(t, srv, "/v1/auth/login",
+		`{"email":"`+testEmail+`","password":"`+testPassword+`","web_session":true}`, nil)
+	if res.status != http.StatusBadRequest || res.reason() != grpcerr.ReasonFieldInvalid {
+		t.Fatalf("status %d reason %q, want 400 %s", res.status, res.reason(), grpcerr.ReasonFieldInvalid)
+	}
+	if _, ok := res.body["refreshToken"]; ok {
+		t.Fatalf("refresh token in response body: %v", res.body)
+	}
+	if len(res.cookies) != 0 {
+		t.Fatalf("rejected login set cookies: %v", res.cookies)
+	}
+
+	// обычный вход по REST работает, refresh token не выдаётся
+	res = post(t, srv, "/v1/auth/login", `{"email":"`+testEmail+`","password":"`+testPassword+`"}`, nil)
+	if res.status != http.StatusOK {
+		t.Fatalf("login: %d %v", res.status, res.body)
+	}
+	if rt, _ := res.body["refreshToken"].(string); rt != "" {
+		t.Fatal("refresh token in plain login response")
+	}
+}
@@ -0,0 +1,238 @@
+package grpcerr
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/i18n"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/logger"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Domain — ErrorInfo.domain всех ошибок сервиса.
+const Domain = "auth.bottrade"
+
+// Стабильные коды ошибок (ErrorInfo.reason): на них опирается фронтенд, менять нельзя —
+// только добавлять. Текст сообщения может меняться.
+const (
+	ReasonFieldRequired         = "FIELD_REQUIRED"
+	ReasonFieldInvalid          = "FIELD_INVALID"
+	ReasonFieldTooLong          = "FIELD_TOO_LONG"
+	ReasonInvalidCredentials    = "INVALID_CREDENTIALS"
+	ReasonEmailTaken            = "EMAIL_TAKEN"
+	ReasonEmailInvalid          = "EMAIL_INVALID"
+	ReasonPasswordPolicy        = "PASSWORD_POLICY"
+	ReasonLinkCodeInvalid       = "LINK_CODE_INVALID"
+	ReasonLinkCodeExpired       = "LINK_CODE_EXPIRED"
+	ReasonLinkCodeUsed          = "LINK_CODE_USED"
+	ReasonTelegramAlreadyLinked = "TELEGRAM_ALREADY_LINKED"
+	ReasonTelegramNotLinked     = "TELEGRAM_NOT_LINKED"
+	ReasonUserNotFound          = "USER_NOT_FOUND"
+	ReasonUserBlocked           = "USER_BLOCKED"
+	ReasonWebhookNotFound       = "WEBHOOK_NOT_FOUND"
+	ReasonMergeNotAllowed       = "MERGE_NOT_ALLOWED"
+	ReasonUnauthenticated       = "UNAUTHENTICATED"
+	ReasonBadBotSignature       = "BAD_BOT_SIGNATURE"
+	ReasonReplayDetected        = "REPLAY_DETECTED"
+	ReasonForbidden             = "FORBIDDEN"
+	ReasonCSRFFailed            = "CSRF_FAILED"
+	ReasonConcurrentUpdate      = "CONCURRENT_UPDATE"
+	ReasonDeadlineExceeded      = "DEADLINE_EXCEEDED"
+	ReasonCanceled              = "CANCELED"
+	ReasonInternal              = "INTERNAL"
+)
+
+// conflictRetryDelay — через сколько повторять запрос после конфликта транзакций.
+const conflictRetryDelay = time.Second
+
+type mapping struct {
+	target error
+	code   codes.Code
+	reason string
+	msg    string
+}
+
+// mappings проверяются по порядку через errors.Is, поэтому обёрнутые ошибки тоже узнаются.
+var mappings = []mapping{
+	{modelerrors.ErrInvalidCredentials, codes.Unauthenticated, ReasonInvalidCredentials, "invalid credentials"},
+	{modelerrors.ErrEmailTaken, codes.AlreadyExists, ReasonEmailTaken, "email already taken"},
+
+	{modelerrors.ErrLinkCodeInvalid, codes.NotFound, ReasonLinkCodeInvalid, "link code not found"},
+	{modelerrors.ErrLinkCodeExpired, codes.FailedPrecondition, ReasonLinkCodeExpired, "link code expired"},
+	{modelerrors.ErrLinkCodeUsed, codes.FailedPrecondition, ReasonLinkCodeUsed, "link code already used"},
+	{modelerrors.ErrTelegramAlreadyLinked, codes.AlreadyExists, ReasonTelegramAlreadyLinked, "telegram already linked"},
+	{modelerrors.ErrTelegramNotLinked, codes.NotFound, ReasonTelegramNotLinked, "telegram not linked"},
+
+	{modelerrors.ErrUserNotFound, codes.NotFound, ReasonUserNotFound, "user not found"},
+	{modelerrors.ErrUserBlocked, codes.PermissionDenied, ReasonUserBlocked, "user blocked"},
+	{modelerrors.ErrWebhookNotFound, codes.NotFound, ReasonWebhookNotFound, "webhook not found"},
+	{modelerrors.ErrMergeNotAllowed, codes.FailedPrecondition, ReasonMergeNotAllowed, "accounts cannot be merged"},
+
+	{modelerrors.ErrUnauthorized, codes.Unauthenticated, ReasonUnauthenticated, "unauthorized"},
+	{modelerrors.ErrBadBotSignature, codes.Unauthenticated, ReasonBadBotSignature, "bad bot signature"},
+	{modelerrors.ErrReplay, codes.Unauthenticated, ReasonReplayDetected, "replay detected"},
+	{modelerrors.ErrForbidden, codes.PermissionDenied, ReasonForbidden, "forbidden"},
+
+	{context.DeadlineExceeded, codes.DeadlineExceeded, ReasonDeadlineExceeded, "deadline exceeded"},
+	{context.Canceled, codes.Canceled, ReasonCanceled, "request canceled"},
+}
+
+// FromError — единый перевод ошибок сервисов в gRPC status с ErrorInfo.
+// Неизвестные ошибки логируются с полями запроса и отдаются как Internal без подробностей.
+func FromError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err // уже status (например, из валидации)
+	}
+
+	var policyErr *modelerrors.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		return PasswordPolicy(policyErr, "password")
+	}
+	if errors.Is(err, modelerrors.ErrEmailInvalid) {
+		return InvalidField("email", ReasonEmailInvalid, "email is invalid")
+	}
+	if errors.Is(err, modelerrors.ErrTxConflict) {
+		return build(codes.Aborted, ReasonConcurrentUpdate, "concurrent update, retry the request", nil,
+			&errdetails.RetryInfo{RetryDelay: durationpb.New(conflictRetryDelay)})
+	}
+
+	for _, m := range mappings {
+		if errors.Is(err, m.target) {
+			return New(m.code, m.reason, m.msg)
+		}
+	}
+
+	logger.FromContext(ctx).Errorf("grpc: unmapped error: %s", err)
+	return New(codes.Internal, ReasonInternal, "internal error")
+}
+
+// Reason — ErrorInfo.reason status-ошибки; "" — ошибка без ErrorInfo.
+func Reason(err error) string {
+	st, ok := status.FromError(err)
+	if !ok {
+		return ""
+	}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info.GetReason()
+		}
+	}
+	return ""
+}
+
+// New — status с ErrorInfo{reason, domain}.
+func New(code codes.Code, reason, msg string) error {
+	return build(code, reason, msg, nil)
+}
+
+// InvalidField — InvalidArgument с BadRequest.FieldViolation по одному полю запроса.
+// reason попадает и в ErrorInfo, и в FieldViolation.
+func InvalidField(field, reason, description string) error {
+	return build(codes.InvalidArgument, reason, description, map[string]string{"field": field},
+		&errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+				Field:       field,
+				Description: description,
+				Reason:      reason,
+			}},
+		})
+}
+
+// PasswordPolicy — все нарушенные правила парольной политики отдельными FieldViolation.
+func PasswordPolicy(err *modelerrors.PasswordPolicyError, field string) error {
+	br := &errdetails.BadRequest{}
+	metadata := map[string]string{"field": field}
+	for _, v := range err.Violations {
+		if v.Limit > 0 {
+			metadata[v.Rule] = strconv.Itoa(v.Limit) // для текста "не короче {min_length}"
+		}
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: v.Description,
+			Reason:      ReasonPasswordPolicy + "_" + strings.ToUpper(v.Rule),
+		})
+	}
+	return build(codes.InvalidArgument, ReasonPasswordPolicy, err.Error(), metadata, br)
+}
+
+// Localize добавляет к status-ошибке LocalizedMessage на языке locale: текст по reason
+// из ErrorInfo, плейсхолдеры — из его metadata. FieldViolation получают свой перевод.
+// Ошибки без ErrorInfo или с reason не из каталога возвращаются как есть.
+func Localize(err error, locale string) error {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() == codes.OK {
+		return err
+	}
+
+	var info *errdetails.ErrorInfo
+	details := make([]protoadapt.MessageV1, 0, len(st.Details())+1)
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			info = d
+		case *errdetails.LocalizedMessage:
+			return err // уже переведено
+		}
+		if m, ok := d.(protoadapt.MessageV1); ok {
+			details = append(details, m)
+		}
+	}
+	if info == nil {
+		return err
+	}
+	for _, d := range details {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			localizeViolations(br, locale, info.GetMetadata())
+		}
+	}
+
+	msg, ok := i18n.Message(locale, info.GetReason(), info.GetMetadata())
+	if !ok {
+		return err
+	}
+	details = append(details, &errdetails.LocalizedMessage{Locale: locale, Message: msg})
+
+	localized, derr := status.New(st.Code(), st.Message()).WithDetails(details...)
+	if derr != nil {
+		return err
+	}
+	return localized.Err()
+}
+
+func localizeViolations(br *errdetails.BadRequest, locale string, metadata map[string]string) {
+	for _, v := range br.GetFieldViolations() {
+		params := map[string]string{"field": v.GetField()}
+		for k, val := range metadata {
+			if k != "field" {
+				params[k] = val
+			}
+		}
+		if msg, ok := i18n.Message(locale, v.GetReason(), params); ok {
+			v.LocalizedMessage = &errdetails.LocalizedMessage{Locale: locale, Message: msg}
+		}
+	}
+}
+
+func build(code codes.Code, reason, msg string, metadata map[string]string, details ...protoadapt.MessageV1) error {
+	st := status.New(code, msg)
+	all := make([]protoadapt.MessageV1, 0, len(details)+1)
+	all = append(all, &errdetails.ErrorInfo{Reason: reason, Domain: Domain, Metadata: metadata})
+	all = append(all, details...)
+
+	withDetails, err := st.WithDetails(all...)
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
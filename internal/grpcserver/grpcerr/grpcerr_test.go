@@ -0,0 +1,178 @@
+package grpcerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFromErrorMapping(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{"invalid credentials", modelerrors.ErrInvalidCredentials, codes.Unauthenticated, ReasonInvalidCredentials},
+		{"email taken", modelerrors.ErrEmailTaken, codes.AlreadyExists, ReasonEmailTaken},
+		{"link code invalid", modelerrors.ErrLinkCodeInvalid, codes.NotFound, ReasonLinkCodeInvalid},
+		{"link code expired", modelerrors.ErrLinkCodeExpired, codes.FailedPrecondition, ReasonLinkCodeExpired},
+		{"link code used", modelerrors.ErrLinkCodeUsed, codes.FailedPrecondition, ReasonLinkCodeUsed},
+		{"telegram already linked", modelerrors.ErrTelegramAlreadyLinked, codes.AlreadyExists, ReasonTelegramAlreadyLinked},
+		{"telegram not linked", modelerrors.ErrTelegramNotLinked, codes.NotFound, ReasonTelegramNotLinked},
+		{"user not found", modelerrors.ErrUserNotFound, codes.NotFound, ReasonUserNotFound},
+		{"user blocked", modelerrors.ErrUserBlocked, codes.PermissionDenied, ReasonUserBlocked},
+		{"webhook not found", modelerrors.ErrWebhookNotFound, codes.NotFound, ReasonWebhookNotFound},
+		{"merge not allowed", modelerrors.ErrMergeNotAllowed, codes.FailedPrecondition, ReasonMergeNotAllowed},
+		{"unauthorized", modelerrors.ErrUnauthorized, codes.Unauthenticated, ReasonUnauthenticated},
+		{"bad bot signature", modelerrors.ErrBadBotSignature, codes.Unauthenticated, ReasonBadBotSignature},
+		{"replay", modelerrors.ErrReplay, codes.Unauthenticated, ReasonReplayDetected},
+		{"forbidden", modelerrors.ErrForbidden, codes.PermissionDenied, ReasonForbidden},
+		{"deadline", context.DeadlineExceeded, codes.DeadlineExceeded, ReasonDeadlineExceeded},
+		{"canceled", context.Canceled, codes.Canceled, ReasonCanceled},
+		{"email invalid", modelerrors.ErrEmailInvalid, codes.InvalidArgument, ReasonEmailInvalid},
+		{"tx conflict", modelerrors.ErrTxConflict, codes.Aborted, ReasonConcurrentUpdate},
+		{"wrapped", fmt.Errorf("svcauth: login: %w", modelerrors.ErrUserBlocked), codes.PermissionDenied, ReasonUserBlocked},
+		{"unknown", errors.New("db exploded"), codes.Internal, ReasonInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromError(context.Background(), tt.err)
+			st, ok := status.FromError(err)
+			if !ok {
+				t.Fatalf("FromError(%v) = %v, want status error", tt.err, err)
+			}
+			if st.Code() != tt.code {
+				t.Errorf("code = %s, want %s", st.Code(), tt.code)
+			}
+			info := errorInfo(t, st)
+			if info.GetReason() != tt.reason {
+				t.Errorf("reason = %q, want %q", info.GetReason(), tt.reason)
+			}
+			if info.GetDomain() != Domain {
+				t.Errorf("domain = %q, want %q", info.GetDomain(), Domain)
+			}
+		})
+	}
+}
+
+func TestFromErrorPassthrough(t *testing.T) {
+	if err := FromError(context.Background(), nil); err != nil {
+		t.Errorf("FromError(nil) = %v, want nil", err)
+	}
+
+	in := status.Error(codes.InvalidArgument, "already a status")
+	if got := FromError(context.Background(), in); got != in {
+		t.Errorf("FromError(status) = %v, want the same error", got)
+	}
+}
+
+func TestFromErrorInternalHidesCause(t *testing.T) {
+	st := status.Convert(FromError(context.Background(), errors.New("password=hunter2")))
+	if st.Message() != "internal error" {
+		t.Errorf("message = %q, want %q", st.Message(), "internal error")
+	}
+}
+
+func TestFromErrorDetails(t *testing.T) {
+	t.Run("email invalid", func(t *testing.T) {
+		st := status.Convert(FromError(context.Background(), modelerrors.ErrEmailInvalid))
+		if got := errorInfo(t, st).GetMetadata()["field"]; got != "email" {
+			t.Errorf("metadata field = %q, want email", got)
+		}
+		violations := badRequest(t, st).GetFieldViolations()
+		if len(violations) != 1 || violations[0].GetField() != "email" || violations[0].GetReason() != ReasonEmailInvalid {
+			t.Errorf("violations = %v, want one email/%s", violations, ReasonEmailInvalid)
+		}
+	})
+
+	t.Run("tx conflict", func(t *testing.T) {
+		st := status.Convert(FromError(context.Background(), modelerrors.ErrTxConflict))
+		var retry *errdetails.RetryInfo
+		for _, d := range st.Details() {
+			if r, ok := d.(*errdetails.RetryInfo); ok {
+				retry = r
+			}
+		}
+		if retry == nil {
+			t.Fatal("no RetryInfo")
+		}
+		if got := retry.GetRetryDelay().AsDuration(); got != conflictRetryDelay {
+			t.Errorf("retry delay = %s, want %s", got, conflictRetryDelay)
+		}
+	})
+
+	t.Run("password policy", func(t *testing.T) {
+		policyErr := &modelerrors.PasswordPolicyError{Violations: []modelerrors.PasswordViolation{
+			{Rule: "min_length", Description: "must be at least 12 characters", Limit: 12},
+			{Rule: "digit", Description: "must contain a digit"},
+		}}
+		st := status.Convert(FromError(context.Background(), fmt.Errorf("register: %w", policyErr)))
+		if st.Code() != codes.InvalidArgument {
+			t.Errorf("code = %s, want %s", st.Code(), codes.InvalidArgument)
+		}
+
+		info := errorInfo(t, st)
+		if info.GetReason() != ReasonPasswordPolicy {
+			t.Errorf("reason = %q, want %q", info.GetReason(), ReasonPasswordPolicy)
+		}
+		if got := info.GetMetadata()["min_length"]; got != "12" {
+			t.Errorf("metadata min_length = %q, want 12", got)
+		}
+		if _, ok := info.GetMetadata()["digit"]; ok {
+			t.Error("metadata has digit, want only rules with a limit")
+		}
+
+		var reasons []string
+		for _, v := range badRequest(t, st).GetFieldViolations() {
+			if v.GetField() != "password" {
+				t.Errorf("violation field = %q, want password", v.GetField())
+			}
+			reasons = append(reasons, v.GetReason())
+		}
+		want := []string{"PASSWORD_POLICY_MIN_LENGTH", "PASSWORD_POLICY_DIGIT"}
+		if fmt.Sprint(reasons) != fmt.Sprint(want) {
+			t.Errorf("violation reasons = %v, want %v", reasons, want)
+		}
+	})
+}
+
+func TestReason(t *testing.T) {
+	if got := Reason(New(codes.NotFound, ReasonUserNotFound, "user not found")); got != ReasonUserNotFound {
+		t.Errorf("Reason = %q, want %q", got, ReasonUserNotFound)
+	}
+	if got := Reason(status.Error(codes.NotFound, "bare")); got != "" {
+		t.Errorf("Reason(bare status) = %q, want empty", got)
+	}
+	if got := Reason(errors.New("plain")); got != "" {
+		t.Errorf("Reason(plain) = %q, want empty", got)
+	}
+}
+
+func errorInfo(t *testing.T, st *status.Status) *errdetails.ErrorInfo {
+	t.Helper()
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	t.Fatalf("status %v has no ErrorInfo", st)
+	return nil
+}
+
+func badRequest(t *testing.T, st *status.Status) *errdetails.BadRequest {
+	t.Helper()
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			return br
+		}
+	}
+	t.Fatalf("status %v has no BadRequest", st)
+	return nil
+}
@@ -0,0 +1,110 @@
+package grpcerr
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/i18n"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLocalizeSelectsLocale(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		locale string
+		want   string
+	}{
+		{"ru", New(codes.PermissionDenied, ReasonUserBlocked, "user blocked"), i18n.LocaleRU, "Аккаунт заблокирован"},
+		{"en", New(codes.PermissionDenied, ReasonUserBlocked, "user blocked"), i18n.LocaleEN, "Account is blocked"},
+		{"field placeholder ru", InvalidField("email", ReasonFieldRequired, "email is required"), i18n.LocaleRU, "Поле email обязательно"},
+		{"field placeholder en", InvalidField("email", ReasonFieldRequired, "email is required"), i18n.LocaleEN, "Field email is required"},
+		{"mapped domain error", FromError(context.Background(), modelerrors.ErrLinkCodeExpired), i18n.LocaleEN, "Link code has expired"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(Localize(tt.err, tt.locale))
+			lm := localizedMessage(st)
+			if lm == nil {
+				t.Fatalf("status %v has no LocalizedMessage", st)
+			}
+			if lm.GetLocale() != tt.locale || lm.GetMessage() != tt.want {
+				t.Errorf("LocalizedMessage = %s/%q, want %s/%q", lm.GetLocale(), lm.GetMessage(), tt.locale, tt.want)
+			}
+			// код, исходный текст и ErrorInfo не меняются
+			orig := status.Convert(tt.err)
+			if st.Code() != orig.Code() || st.Message() != orig.Message() {
+				t.Errorf("status = %s %q, want %s %q", st.Code(), st.Message(), orig.Code(), orig.Message())
+			}
+			if Reason(st.Err()) != Reason(tt.err) {
+				t.Errorf("reason = %q, want %q", Reason(st.Err()), Reason(tt.err))
+			}
+		})
+	}
+}
+
+func TestLocalizePasswordViolations(t *testing.T) {
+	policyErr := &modelerrors.PasswordPolicyError{Violations: []modelerrors.PasswordViolation{
+		{Rule: "min_length", Description: "must be at least 12 characters", Limit: 12},
+		{Rule: "digit", Description: "must contain a digit"},
+	}}
+	tests := []struct {
+		locale string
+		want   []string
+	}{
+		{i18n.LocaleRU, []string{"Пароль должен быть не короче 12 символов", "Пароль должен содержать цифру"}},
+		{i18n.LocaleEN, []string{"Password must be at least 12 characters", "Password must contain a digit"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			st := status.Convert(Localize(PasswordPolicy(policyErr, "password"), tt.locale))
+			violations := badRequest(t, st).GetFieldViolations()
+			if len(violations) != len(tt.want) {
+				t.Fatalf("got %d violations, want %d", len(violations), len(tt.want))
+			}
+			for i, v := range violations {
+				lm := v.GetLocalizedMessage()
+				if lm.GetLocale() != tt.locale || lm.GetMessage() != tt.want[i] {
+					t.Errorf("violation %s: LocalizedMessage = %s/%q, want %s/%q",
+						v.GetReason(), lm.GetLocale(), lm.GetMessage(), tt.locale, tt.want[i])
+				}
+			}
+			if lm := localizedMessage(st); lm == nil || lm.GetLocale() != tt.locale {
+				t.Errorf("top-level LocalizedMessage = %v, want locale %s", lm, tt.locale)
+			}
+		})
+	}
+}
+
+func TestLocalizeLeavesAsIs(t *testing.T) {
+	translated := Localize(New(codes.NotFound, ReasonUserNotFound, "user not found"), i18n.LocaleRU)
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"not a status", errors.New("plain")},
+		{"no ErrorInfo", status.Error(codes.NotFound, "bare")},
+		{"reason not in catalog", New(codes.Unavailable, "SOMETHING_NEW", "new")},
+		{"already localized", translated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Localize(tt.err, i18n.LocaleEN); got != tt.err {
+				t.Errorf("Localize = %v, want the same error", got)
+			}
+		})
+	}
+}
+
+func localizedMessage(st *status.Status) *errdetails.LocalizedMessage {
+	for _, d := range st.Details() {
+		if lm, ok := d.(*errdetails.LocalizedMessage); ok {
+			return lm
+		}
+	}
+	return nil
+}
@@ -25,4 +25,11 @@ func ParseBearer(authz string) (string, bool) {
 	}
 	tok := strings.TrimSpace(authz[len(p):])
 	return tok, tok != ""
-}
\ No newline at end of file
+}
+
+// GatewayNetwork — сеть in-process канала REST-шлюза (bufconn). Снаружи к нему не подключиться,
+// поэтому x-forwarded-for и user agent клиента от шлюза принимаются без trust_proxy_headers.
+const GatewayNetwork = "bufconn"
+
+// GatewayUserAgentKey — user agent HTTP-клиента: заголовок user-agent gRPC-клиент шлюза перезаписывает.
+const GatewayUserAgentKey = "x-gateway-user-agent"
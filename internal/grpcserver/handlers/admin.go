@@ -0,0 +1,282 @@
+package grpchandlers
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/gen/authv1"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/grpcerr"
+	grpcports "github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/interface"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+type AdminHandler struct {
+	authv1.UnimplementedAdminServiceServer
+	svc      grpcports.AdminUsecase
+	webhooks grpcports.WebhookUsecase
+}
+
+func NewAdminHandler(svc grpcports.AdminUsecase, webhooks grpcports.WebhookUsecase) *AdminHandler {
+	return &AdminHandler{svc: svc, webhooks: webhooks}
+}
+
+func (h *AdminHandler) ListUsers(ctx context.Context, req *authv1.ListUsersRequest) (*authv1.ListUsersResponse, error) {
+	pageSize, err := validatePageSize(req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+
+	afterID, err := parsePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := h.svc.ListUsers(ctx, models.UserFilter{
+		AfterID:    afterID,
+		EmailQuery: strings.TrimSpace(req.GetEmailQuery()),
+		Limit:      pageSize,
+	})
+	if err != nil {
+		return nil, grpcerr.FromError(ctx, err)
+	}
+
+	resp := &authv1.ListUsersResponse{
+		Users: make([]*authv1.User, 0, len(users)),
+	}
+	for _, u := range users {
+		resp.Users = append(resp.Users, userToProto(u))
+	}
+	// полная страница — возможно, есть следующая
+	if len(users) == int(pageSize) {
+		resp.NextPageToken = strconv.FormatInt(int64(users[len(users)-1].ID), 10)
+	}
+	return resp, nil
+}
+
+func (h *AdminHandler) GetUser(ctx context.Context, req *authv1.GetUserRequest) (*authv1.GetUserResponse, error) {
+	userID, err := validateUserID(req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := h.svc.GetUser(ctx, userID)
+	if err != nil {
+		return nil, grpcerr.FromError(ctx, err)
+	}
+
+	resp := &authv1.GetUserResponse{
+		User:       userToProto(details.User),
+		Identities: make([]*authv1.Identity, 0, len(details.Identities)),
+		Sessions:   make([]*authv1.Session, 0, len(details.Sessions)),
+	}
+	for _, i := range details.Identities {
+		resp.Identities = append(resp.Identities, &authv1.Identity{
+			Id:             i.ID,
+			Provider:       i.Provider,
+			ProviderUserId: i.ProviderUserID,
+			Username:       i.Username,
+			FirstName:      i.FirstName,
+			LastName:       i.LastName,
+			ChatId:         i.ChatID,
+			CreatedAt:      timeToProto(i.CreatedAt),
+		})
+	}
+	for _, s := range details.Sessions {
+		resp.Sessions = append(resp.Sessions, &authv1.Session{
+			Id:        s.ID,
+			CreatedAt: timeToProto(s.CreatedAt),
+			ExpiresAt: timeToProto(s.ExpiresAt),
+			RevokedAt: timeToProto(s.RevokedAt),
+		})
+	}
+	return resp, nil
+}
+
+func (h *AdminHandler) BlockUser(ctx context.Context, req *authv1.BlockUserRequest) (*authv1.BlockUserResponse, error) {
+	userID, err := validateUserID(req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if err := h.svc.BlockUser(ctx, userID); err != nil {
+		return nil, grpcerr.FromError(ctx, err)
+	}
+	return &authv1.BlockUserResponse{Ok: true}, nil
+}
+
+func (h *AdminHandler) UnblockUser(ctx context.Context, req *authv1.UnblockUserRequest) (*authv1.UnblockUserResponse, error) {
+	userID, err := validateUserID(req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if err := h.svc.UnblockUser(ctx, userID); err != nil {
+		return nil, grpcerr.FromError(ctx, err)
+	}
+	return &authv1.UnblockUserResponse{Ok: true}, nil
+}
+
+func (h *AdminHandler) ForceLogout(ctx context.Context, req *authv1.ForceLogoutRequest) (*authv1.ForceLogoutResponse, error) {
+	userID, err := validateUserID(req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	revoked, err := h.svc.ForceLogout(ctx, userID)
+	if err != nil {
+		return nil, grpcerr.FromError(ctx, err)
+	}
+	return &authv1.ForceLogoutResponse{RevokedSessions: revoked}, nil
+}
+
+func (h *AdminHandler) DeleteUser(ctx context.Context, req *authv1.DeleteUserRequest) (*authv1.DeleteUserResponse, error) {
+	userID, err := validateUserID(req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if err := h.svc.DeleteUser(ctx, userID); err != nil {
+		return nil, grpcerr.FromError(ctx, err)
+	}
+	return &authv1.DeleteUserResponse{Ok: true}, nil
+}
+
+func (h *AdminHandler) ResetMfa(ctx context.Context, req *authv1.ResetMfaRequest) (*authv1.ResetMfaResponse, error) {
+	userID, err := validateUserID(req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if err := h.svc.ResetMfa(ctx, userID); err != nil {
+		return nil, grpcerr.FromError(ctx, err)
+	}
+	return &authv1.ResetMfaResponse{Ok: true}, nil
+}
+
+func (h *AdminHandler) ListAuditEvents(ctx context.Context, req *authv1.ListAuditEventsRequest) (*authv1.ListAuditEventsResponse, error) {
+	filter := models.AuditFilter{}
+
+	if req.GetUserId() != 0 {
+		userID, err := validateUserID(req.GetUserId())
+		if err != nil {
+			return nil, err
+		}
+		filter.UserID = userID
+	}
+	if req.GetFrom() != nil {
+		filter.From = req.GetFrom().AsTime()
+	}
+	if req.GetTo() != nil {
+		filter.To = req.GetTo().AsTime()
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, grpcerr.InvalidField("from", grpcerr.ReasonFieldInvalid, "from must be before to")
+	}
+
+	pageSize, err := validatePageSize(req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+	filter.Limit = pageSize
+
+	filter.BeforeID, err = parseBeforeIDToken(req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := h.svc.ListAuditEvents(ctx, filter)
+	if err != nil {
+		return nil, grpcerr.FromError(ctx, err)
+	}
+
+	resp := &authv1.ListAuditEventsResponse{
+		Events: make([]*authv1.AuditEvent, 0, len(events)),
+	}
+	for _, e := range events {
+		resp.Events = append(resp.Events, &authv1.AuditEvent{
+			Id:           e.ID,
+			Type:         e.Type,
+			ActorUserId:  int64(e.ActorUserID),
+			ActorBotId:   e.ActorBotID,
+			TargetUserId: int64(e.TargetUserID),
+			Ip:           e.IP,
+			UserAgent:    e.UserAgent,
+			RequestId:    e.RequestID,
+			Details:      e.Details,
+			OccurredAt:   timeToProto(e.OccurredAt),
+		})
+	}
+	if len(events) == int(pageSize) {
+		resp.NextPageToken = strconv.FormatInt(events[len(events)-1].ID, 10)
+	}
+	return resp, nil
+}
+
+// ----- Validation helpers -----
+
+func validatePageSize(pageSize int32) (int32, error) {
+	switch {
+	case pageSize < 0:
+		return 0, grpcerr.InvalidField("page_size", grpcerr.ReasonFieldInvalid, "page_size must be >= 0")
+	case pageSize == 0:
+		return defaultPageSize, nil
+	case pageSize > maxPageSize:
+		return maxPageSize, nil
+	}
+	return pageSize, nil
+}
+
+func validateUserID(id int64) (int32, error) {
+	if id <= 0 || id > math.MaxInt32 {
+		return 0, grpcerr.InvalidField("user_id", grpcerr.ReasonFieldInvalid, "user_id is invalid")
+	}
+	return int32(id), nil
+}
+
+// parsePageToken: токен страницы — id последнего пользователя предыдущей страницы.
+func parsePageToken(token string) (int32, error) {
+	if token == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(token, 10, 32)
+	if err != nil || id < 0 {
+		return 0, grpcerr.InvalidField("page_token", grpcerr.ReasonFieldInvalid, "page_token is invalid")
+	}
+	return int32(id), nil
+}
+
+// parseBeforeIDToken: для лент "от новых к старым" токен — id последней записи предыдущей страницы.
+func parseBeforeIDToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(token, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, grpcerr.InvalidField("page_token", grpcerr.ReasonFieldInvalid, "page_token is invalid")
+	}
+	return id, nil
+}
+
+// ----- Converters -----
+
+func userToProto(u models.User) *authv1.User {
+	return &authv1.User{
+		Id:        int64(u.ID),
+		Email:     u.Email,
+		Role:      u.Role,
+		Blocked:   u.Blocked(),
+		BlockedAt: timeToProto(u.BlockedAt),
+		CreatedAt: timeToProto(u.CreatedAt),
+	}
+}
+
+func timeToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
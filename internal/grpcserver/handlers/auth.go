@@ -2,7 +2,9 @@ package grpchandlers
 
 import (
 	"context"
+	"errors"
 	"net/mail"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,10 +12,9 @@ import (
 	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
 	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
 	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/authctx"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/grpcerr"
 	grpcports "github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/interface"
-	"github.com/IvanOplesnin/BotTradeService.git/internal/logger"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 type AuthHandler struct {
@@ -22,8 +23,8 @@ type AuthHandler struct {
 	codeTgTtlMinute int64
 }
 
-func NewAuthHandler(svc grpcports.AuthUsecase) *AuthHandler {
-	return &AuthHandler{svc: svc}
+func NewAuthHandler(svc grpcports.AuthUsecase, codeTgTtlMinute int64) *AuthHandler {
+	return &AuthHandler{svc: svc, codeTgTtlMinute: codeTgTtlMinute}
 }
 
 func (h *AuthHandler) Register(ctx context.Context, req *authv1.RegisterRequest) (*authv1.AuthResponse, error) {
@@ -33,14 +34,13 @@ func (h *AuthHandler) Register(ctx context.Context, req *authv1.RegisterRequest)
 	if err := validateEmail(email); err != nil {
 		return nil, err
 	}
-	if err := validatePassword(pass); err != nil {
+	if err := validatePassword("password", pass); err != nil {
 		return nil, err
 	}
 
 	toks, err := h.svc.Register(ctx, email, pass)
 	if err != nil {
-		logger.Log.Errorf("register error")
-		return nil, mapAuthErr(err)
+		return nil, grpcerr.FromError(ctx, err)
 	}
 
 	return &authv1.AuthResponse{
@@ -56,13 +56,67 @@ func (h *AuthHandler) Login(ctx context.Context, req *authv1.LoginRequest) (*aut
 	if err := validateEmail(email); err != nil {
 		return nil, err
 	}
-	if err := validatePassword(pass); err != nil { // можно не проверять строгую длину на логине
+	if err := validatePassword("password", pass); err != nil {
 		return nil, err
 	}
 
-	toks, err := h.svc.Login(ctx, email, pass)
+	login := h.svc.Login
+	if req.GetWebSession() {
+		login = h.svc.WebLogin
+	}
+	toks, err := login(ctx, email, pass)
+	if err != nil {
+		return nil, grpcerr.FromError(ctx, err)
+	}
+
+	return authResponse(toks), nil
+}
+
+func (h *AuthHandler) RefreshSession(ctx context.Context, req *authv1.RefreshSessionRequest) (*authv1.AuthResponse, error) {
+	if req.GetRefreshToken() == "" {
+		return nil, grpcerr.InvalidField("refresh_token", grpcerr.ReasonFieldRequired, "refresh_token is required")
+	}
+
+	toks, err := h.svc.RefreshWebSession(ctx, req.GetRefreshToken())
 	if err != nil {
-		return nil, mapAuthErr(err)
+		return nil, grpcerr.FromError(ctx, err)
+	}
+
+	return authResponse(toks), nil
+}
+
+func (h *AuthHandler) EndSession(ctx context.Context, req *authv1.EndSessionRequest) (*authv1.EndSessionResponse, error) {
+	if req.GetRefreshToken() == "" {
+		return nil, grpcerr.InvalidField("refresh_token", grpcerr.ReasonFieldRequired, "refresh_token is required")
+	}
+
+	if err := h.svc.EndWebSession(ctx, req.GetRefreshToken()); err != nil {
+		return nil, grpcerr.FromError(ctx, err)
+	}
+
+	return &authv1.EndSessionResponse{Ok: true}, nil
+}
+
+func (h *AuthHandler) ChangePassword(ctx context.Context, req *authv1.ChangePasswordRequest) (*authv1.AuthResponse, error) {
+	userID, ok := authctx.UserID(ctx)
+	if !ok || userID == "" {
+		return nil, grpcerr.New(codes.Unauthenticated, grpcerr.ReasonUnauthenticated, "missing user context")
+	}
+
+	if req.GetOldPassword() == "" {
+		return nil, grpcerr.InvalidField("old_password", grpcerr.ReasonFieldRequired, "old_password is required")
+	}
+	if err := validatePassword("new_password", req.GetNewPassword()); err != nil {
+		return nil, err
+	}
+
+	toks, err := h.svc.ChangePassword(ctx, userID, req.GetOldPassword(), req.GetNewPassword())
+	if err != nil {
+		var policyErr *modelerrors.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			return nil, grpcerr.PasswordPolicy(policyErr, "new_password")
+		}
+		return nil, grpcerr.FromError(ctx, err)
 	}
 
 	return &authv1.AuthResponse{
@@ -71,17 +125,83 @@ func (h *AuthHandler) Login(ctx context.Context, req *authv1.LoginRequest) (*aut
 	}, nil
 }
 
+func (h *AuthHandler) ListLoginHistory(ctx context.Context, req *authv1.ListLoginHistoryRequest) (*authv1.ListLoginHistoryResponse, error) {
+	userID, ok := authctx.UserID(ctx)
+	if !ok || userID == "" {
+		return nil, grpcerr.New(codes.Unauthenticated, grpcerr.ReasonUnauthenticated, "missing user context")
+	}
+
+	pageSize, err := validatePageSize(req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+	beforeID, err := parseBeforeIDToken(req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := h.svc.ListLoginHistory(ctx, userID, beforeID, pageSize)
+	if err != nil {
+		return nil, grpcerr.FromError(ctx, err)
+	}
+
+	resp := &authv1.ListLoginHistoryResponse{
+		Logins: make([]*authv1.LoginRecord, 0, len(records)),
+	}
+	for _, r := range records {
+		resp.Logins = append(resp.Logins, &authv1.LoginRecord{
+			Id:        r.ID,
+			Method:    r.Method,
+			Ip:        r.IP,
+			UserAgent: r.UserAgent,
+			Device:    r.DeviceName,
+			CreatedAt: timeToProto(r.CreatedAt),
+		})
+	}
+	if len(records) == int(pageSize) {
+		resp.NextPageToken = strconv.FormatInt(records[len(records)-1].ID, 10)
+	}
+	return resp, nil
+}
+
+func (h *AuthHandler) MergeAccounts(ctx context.Context, req *authv1.MergeAccountsRequest) (*authv1.MergeAccountsResponse, error) {
+	userID, ok := authctx.UserID(ctx)
+	if !ok || userID == "" {
+		return nil, grpcerr.New(codes.Unauthenticated, grpcerr.ReasonUnauthenticated, "missing user context")
+	}
+
+	proof := models.MergeProof{
+		AccessToken: strings.TrimSpace(req.GetSecondaryAccessToken()),
+		LinkCode:    strings.TrimSpace(req.GetSecondaryLinkCode()),
+	}
+	if (proof.AccessToken == "") == (proof.LinkCode == "") {
+		return nil, grpcerr.InvalidField("secondary_access_token", grpcerr.ReasonFieldRequired, "exactly one of secondary_access_token, secondary_link_code is required")
+	}
+
+	res, err := h.svc.MergeAccounts(ctx, userID, proof)
+	if err != nil {
+		return nil, grpcerr.FromError(ctx, err)
+	}
+
+	return &authv1.MergeAccountsResponse{
+		MergedUserId:    res.MergedUserID,
+		MovedIdentities: res.Identities,
+		MovedSessions:   res.Sessions,
+		MovedLogins:     res.Logins,
+	}, nil
+}
+
 func (h *AuthHandler) CreateTelegramLinkCode(ctx context.Context, _ *authv1.CreateTelegramLinkCodeRequest) (*authv1.CreateTelegramLinkCodeResponse, error) {
 	userID, ok := authctx.UserID(ctx)
 	if !ok || userID == "" {
-		return nil, status.Error(codes.Unauthenticated, "missing user context")
+		return nil, grpcerr.New(codes.Unauthenticated, grpcerr.ReasonUnauthenticated, "missing user context")
 	}
 
 	ttl := time.Duration(h.codeTgTtlMinute) * time.Minute
 
 	code, expSec, err := h.svc.CreateTelegramLinkCode(ctx, userID, ttl)
 	if err != nil {
-		return nil, mapAuthErr(err)
+		return nil, grpcerr.FromError(ctx, err)
 	}
 
 	return &authv1.CreateTelegramLinkCodeResponse{
@@ -93,10 +213,13 @@ func (h *AuthHandler) CreateTelegramLinkCode(ctx context.Context, _ *authv1.Crea
 func (h *AuthHandler) LinkTelegram(ctx context.Context, req *authv1.LinkTelegramRequest) (*authv1.LinkTelegramResponse, error) {
 	code := strings.TrimSpace(req.GetCode())
 	if code == "" {
-		return nil, status.Error(codes.InvalidArgument, "code is required")
+		return nil, grpcerr.InvalidField("code", grpcerr.ReasonFieldRequired, "code is required")
+	}
+	if req.GetTelegramUserId() <= 0 {
+		return nil, grpcerr.InvalidField("telegram_user_id", grpcerr.ReasonFieldInvalid, "telegram_user_id must be positive")
 	}
-	if req.GetTelegramUserId() <= 0 || req.GetChatId() <= 0 {
-		return nil, status.Error(codes.InvalidArgument, "telegram_user_id/chat_id must be positive")
+	if req.GetChatId() <= 0 {
+		return nil, grpcerr.InvalidField("chat_id", grpcerr.ReasonFieldInvalid, "chat_id must be positive")
 	}
 
 	tg := models.TelegramProfile{
@@ -108,7 +231,7 @@ func (h *AuthHandler) LinkTelegram(ctx context.Context, req *authv1.LinkTelegram
 	}
 
 	if err := h.svc.LinkTelegram(ctx, code, tg); err != nil {
-		return nil, mapAuthErr(err)
+		return nil, grpcerr.FromError(ctx, err)
 	}
 
 	return &authv1.LinkTelegramResponse{Ok: true}, nil
@@ -120,17 +243,17 @@ func (h *AuthHandler) TelegramAuth(
 ) (*authv1.AuthResponse, error) {
 
 	if req == nil {
-		return nil, status.Error(codes.InvalidArgument, "request is nil")
+		return nil, grpcerr.New(codes.InvalidArgument, grpcerr.ReasonFieldRequired, "request is nil")
 	}
 
 	tgUserID := req.GetTelegramUserId()
 	chatID := req.GetChatId()
 
 	if tgUserID <= 0 {
-		return nil, status.Error(codes.InvalidArgument, "telegram_user_id must be positive")
+		return nil, grpcerr.InvalidField("telegram_user_id", grpcerr.ReasonFieldInvalid, "telegram_user_id must be positive")
 	}
 	if chatID <= 0 {
-		return nil, status.Error(codes.InvalidArgument, "chat_id must be positive")
+		return nil, grpcerr.InvalidField("chat_id", grpcerr.ReasonFieldInvalid, "chat_id must be positive")
 	}
 
 	tg := models.TelegramProfile{
@@ -141,10 +264,11 @@ func (h *AuthHandler) TelegramAuth(
 		LastName:       strings.TrimSpace(req.GetLastName()),
 	}
 
-	// сервис делает "login-or-register" (если tg еще не привязан — создает юзера и привязку)
+	// вход только для уже привязанного Telegram (LinkTelegram), иначе ErrTelegramNotLinked;
+	// chat_id и username в identity обновляются
 	toks, err := h.svc.TelegramAuth(ctx, tg)
 	if err != nil {
-		return nil, mapAuthErr(err)
+		return nil, grpcerr.FromError(ctx, err)
 	}
 
 	return &authv1.AuthResponse{
@@ -153,54 +277,63 @@ func (h *AuthHandler) TelegramAuth(
 	}, nil
 }
 
+func (h *AuthHandler) PullBotMessages(ctx context.Context, req *authv1.PullBotMessagesRequest) (*authv1.PullBotMessagesResponse, error) {
+	limit, err := validatePageSize(req.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := h.svc.PullBotMessages(ctx, limit)
+	if err != nil {
+		return nil, grpcerr.FromError(ctx, err)
+	}
+
+	resp := &authv1.PullBotMessagesResponse{
+		Messages: make([]*authv1.BotMessage, 0, len(messages)),
+	}
+	for _, m := range messages {
+		resp.Messages = append(resp.Messages, &authv1.BotMessage{
+			Id:        m.ID,
+			ChatId:    m.ChatID,
+			Text:      m.Text,
+			CreatedAt: timeToProto(m.CreatedAt),
+		})
+	}
+	return resp, nil
+}
+
 // ----- Validation helpers -----
 
 func validateEmail(email string) error {
 	if email == "" {
-		return status.Error(codes.InvalidArgument, "email is required")
+		return grpcerr.InvalidField("email", grpcerr.ReasonFieldRequired, "email is required")
 	}
 	_, err := mail.ParseAddress(email)
 	if err != nil {
-		return status.Error(codes.InvalidArgument, "email is invalid")
+		return grpcerr.InvalidField("email", grpcerr.ReasonEmailInvalid, "email is invalid")
 	}
 	return nil
 }
 
-func validatePassword(p string) error {
-	// MVP-ограничения
-	if len(p) < 8 {
-		return status.Error(codes.InvalidArgument, "password must be at least 8 characters")
+// maxPasswordBytes — защита от огромных строк до хеширования; требования к паролю
+// проверяет парольная политика в сервисе.
+const maxPasswordBytes = 4096
+
+func validatePassword(field, p string) error {
+	if p == "" {
+		return grpcerr.InvalidField(field, grpcerr.ReasonFieldRequired, field+" is required")
 	}
-	if len(p) > 128 {
-		return status.Error(codes.InvalidArgument, "password too long")
+	if len(p) > maxPasswordBytes {
+		return grpcerr.InvalidField(field, grpcerr.ReasonFieldTooLong, field+" is too long")
 	}
 	return nil
 }
 
-// ----- Error mapping -----
-
-func mapAuthErr(err error) error {
-	switch err {
-	case modelerrors.ErrInvalidCredentials:
-		return status.Error(codes.Unauthenticated, "invalid credentials")
-	case modelerrors.ErrEmailTaken:
-		return status.Error(codes.AlreadyExists, "email already taken")
-
-	case modelerrors.ErrLinkCodeInvalid:
-		return status.Error(codes.NotFound, "link code not found")
-	case modelerrors.ErrLinkCodeExpired:
-		return status.Error(codes.FailedPrecondition, "link code expired")
-	case modelerrors.ErrLinkCodeUsed:
-		return status.Error(codes.FailedPrecondition, "link code already used")
-	case modelerrors.ErrTelegramAlreadyLinked:
-		return status.Error(codes.AlreadyExists, "telegram already linked")
-
-	case modelerrors.ErrUnauthorized, modelerrors.ErrBadBotSignature, modelerrors.ErrReplay:
-		return status.Error(codes.Unauthenticated, err.Error())
-	case modelerrors.ErrForbidden:
-		return status.Error(codes.PermissionDenied, err.Error())
-
-	default:
-		return status.Error(codes.Internal, "internal error")
+func authResponse(toks models.AuthTokens) *authv1.AuthResponse {
+	return &authv1.AuthResponse{
+		AccessToken:         toks.AccessToken,
+		ExpiresInSec:        toks.ExpiresInSec,
+		RefreshToken:        toks.RefreshToken,
+		RefreshExpiresInSec: toks.RefreshExpiresInSec,
 	}
 }
@@ -1,34 +1,101 @@
 package grpchandlers
 
 import (
+	"fmt"
+
 	"github.com/IvanOplesnin/BotTradeService.git/gen/authv1"
 	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/interceptor/authinterceptor"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/interceptor/errinterceptor"
 	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/interceptor/loggerinterceptor"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/interceptor/metainterceptor"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/interceptor/metricsinterceptor"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/interceptor/recoveryinterceptor"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/interceptor/requestidinterceptor"
 	grpcports "github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/interface"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 type InitHandlerDeps struct {
-	AuthUseCase   grpcports.AuthUsecase
-	BotVerifier   grpcports.BotVerifier
-	TokenVerifier grpcports.TokenVerifier
+	AuthUseCase    grpcports.AuthUsecase
+	AdminUseCase   grpcports.AdminUsecase
+	WebhookUseCase grpcports.WebhookUsecase
+	BotVerifier    grpcports.BotVerifier
+	TokenVerifier  grpcports.TokenVerifier
+	AdminVerifier  grpcports.AdminVerifier
+
+	CodeTgTtlMinute   int64
+	TrustProxyHeaders bool
+	// LogPayloads — debug-лог тел запросов и ответов без sensitive полей
+	LogPayloads bool
+
+	// Creds — транспорт gRPC listener-а (TLS/mTLS); nil — plaintext
+	Creds credentials.TransportCredentials
+	// ServiceIdentities — mTLS-идентичности и BOT-методы, доступные им без bot-signature
+	ServiceIdentities map[string][]string
+	// Health — статус grpc.health.v1, его ведёт healthcheck.Checker
+	Health *health.Server
+	// Crashes — журнал перехваченных panic для admin listener; nil — выключен
+	Crashes *recoveryinterceptor.CrashLog
 }
 
-func InitHandlers(deps InitHandlerDeps) *grpc.Server {
-	authHandler := NewAuthHandler(deps.AuthUseCase)
+func InitHandlers(deps InitHandlerDeps) (*grpc.Server, error) {
+	authHandler := NewAuthHandler(deps.AuthUseCase, deps.CodeTgTtlMinute)
+	adminHandler := NewAdminHandler(deps.AdminUseCase, deps.WebhookUseCase)
 	authInterceptor := authinterceptor.NewAuthInterceptor(authinterceptor.AuthInterceptorDeps{
 		BotVerifier:   deps.BotVerifier,
 		TokenVerifier: deps.TokenVerifier,
+		AdminVerifier: deps.AdminVerifier,
+
+		ServiceIdentities: deps.ServiceIdentities,
+		PublicServices:    []string{healthgrpc.Health_ServiceDesc.ServiceName},
 	})
-	loggerInterceptor := loggerinterceptor.NewLoggerInterceptor()
+	loggerInterceptor := loggerinterceptor.NewLoggerInterceptor(deps.LogPayloads)
+	requestIDInterceptor := requestidinterceptor.NewRequestIDInterceptor()
+	recoveryInterceptor := recoveryinterceptor.NewRecoveryInterceptor(recoveryinterceptor.RecoveryInterceptorDeps{
+		Crashes: deps.Crashes,
+	})
+	metaInterceptor := metainterceptor.NewMetaInterceptor(deps.TrustProxyHeaders)
+	errInterceptor := errinterceptor.NewErrInterceptor()
+	metricsInterceptor := metricsinterceptor.NewMetricsInterceptor()
 
-	server := grpc.NewServer(
+	opts := []grpc.ServerOption{
+		// server span на каждый RPC, родитель — traceparent из метаданных; health-пробы не трассируются
+		grpc.StatsHandler(otelgrpc.NewServerHandler(
+			otelgrpc.WithFilter(filters.Not(filters.HealthCheck())),
+		)),
 		grpc.ChainUnaryInterceptor(
+			recoveryInterceptor.Unary(),  // внешний слой: panic в самих интерцепторах ниже не роняет процесс
+			metricsInterceptor.Unary(),   // время и in-flight по всей цепочке
+			requestIDInterceptor.Unary(), // до meta и logger: id нужен в логах и аудите
+			metaInterceptor.Unary(),
 			loggerInterceptor.Unary(),
+			errInterceptor.Unary(),      // после meta: нужен язык; до auth: переводит и её ошибки
+			recoveryInterceptor.Unary(), // после logger и err: panic обработчика попадает в access log и переводится
 			authInterceptor.Unary(),
 		),
-	)
+		// stream-методов в authv1 нет, остаётся только health Watch для проб: ему не нужны
+		// request id, access log и метрики, а unary-интерцепторы к stream не применимы
+		grpc.ChainStreamInterceptor(
+			recoveryInterceptor.Stream(),
+		),
+	}
+	if deps.Creds != nil {
+		opts = append(opts, grpc.Creds(deps.Creds))
+	}
+	server := grpc.NewServer(opts...)
 
 	authv1.RegisterAuthServiceServer(server, authHandler)
-	return server
+	authv1.RegisterAdminServiceServer(server, adminHandler)
+	healthgrpc.RegisterHealthServer(server, deps.Health)
+
+	// каждый зарегистрированный метод должен иметь аннотацию уровня доступа
+	if err := authInterceptor.CheckServices(server.GetServiceInfo()); err != nil {
+		return nil, fmt.Errorf("grpchandlers.InitHandlers: %w", err)
+	}
+	return server, nil
 }
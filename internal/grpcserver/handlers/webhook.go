@@ -0,0 +1,172 @@
+package grpchandlers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/IvanOplesnin/BotTradeService.git/gen/authv1"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/grpcerr"
+)
+
+const (
+	maxWebhookURLLen    = 2048
+	minWebhookSecretLen = 16
+)
+
+func (h *AdminHandler) CreateWebhook(ctx context.Context, req *authv1.CreateWebhookRequest) (*authv1.CreateWebhookResponse, error) {
+	rawURL := strings.TrimSpace(req.GetUrl())
+	if err := validateWebhookURL(rawURL); err != nil {
+		return nil, err
+	}
+	eventTypes, err := validateEventFilter(req.GetEventTypes())
+	if err != nil {
+		return nil, err
+	}
+	secret := req.GetSecret()
+	if secret != "" && len(secret) < minWebhookSecretLen {
+		return nil, grpcerr.InvalidField("secret", grpcerr.ReasonFieldInvalid, fmt.Sprintf("secret must be at least %d characters", minWebhookSecretLen))
+	}
+
+	created, err := h.webhooks.CreateWebhook(ctx, models.Webhook{
+		URL:        rawURL,
+		EventTypes: eventTypes,
+		Secret:     secret,
+	})
+	if err != nil {
+		return nil, grpcerr.FromError(ctx, err)
+	}
+	return &authv1.CreateWebhookResponse{
+		Webhook: webhookToProto(created),
+		Secret:  created.Secret,
+	}, nil
+}
+
+func (h *AdminHandler) ListWebhooks(ctx context.Context, _ *authv1.ListWebhooksRequest) (*authv1.ListWebhooksResponse, error) {
+	webhooks, err := h.webhooks.ListWebhooks(ctx)
+	if err != nil {
+		return nil, grpcerr.FromError(ctx, err)
+	}
+
+	resp := &authv1.ListWebhooksResponse{
+		Webhooks: make([]*authv1.Webhook, 0, len(webhooks)),
+	}
+	for _, w := range webhooks {
+		resp.Webhooks = append(resp.Webhooks, webhookToProto(w))
+	}
+	return resp, nil
+}
+
+func (h *AdminHandler) DeleteWebhook(ctx context.Context, req *authv1.DeleteWebhookRequest) (*authv1.DeleteWebhookResponse, error) {
+	if req.GetWebhookId() <= 0 {
+		return nil, grpcerr.InvalidField("webhook_id", grpcerr.ReasonFieldInvalid, "webhook_id is invalid")
+	}
+	if err := h.webhooks.DeleteWebhook(ctx, req.GetWebhookId()); err != nil {
+		return nil, grpcerr.FromError(ctx, err)
+	}
+	return &authv1.DeleteWebhookResponse{Ok: true}, nil
+}
+
+func (h *AdminHandler) ListWebhookDeliveries(ctx context.Context, req *authv1.ListWebhookDeliveriesRequest) (*authv1.ListWebhookDeliveriesResponse, error) {
+	if req.GetWebhookId() < 0 {
+		return nil, grpcerr.InvalidField("webhook_id", grpcerr.ReasonFieldInvalid, "webhook_id is invalid")
+	}
+	pageSize, err := validatePageSize(req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+	beforeID, err := parseBeforeIDToken(req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := h.webhooks.ListWebhookDeliveries(ctx, models.WebhookDeliveryFilter{
+		WebhookID: req.GetWebhookId(),
+		BeforeID:  beforeID,
+		Limit:     pageSize,
+	})
+	if err != nil {
+		return nil, grpcerr.FromError(ctx, err)
+	}
+
+	resp := &authv1.ListWebhookDeliveriesResponse{
+		Deliveries: make([]*authv1.WebhookDelivery, 0, len(deliveries)),
+	}
+	for _, d := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, &authv1.WebhookDelivery{
+			Id:             d.ID,
+			WebhookId:      d.WebhookID,
+			EventId:        d.EventID,
+			EventType:      d.EventType,
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			LastStatusCode: d.LastStatusCode,
+			LastError:      d.LastError,
+			NextAttemptAt:  timeToProto(d.NextAttemptAt),
+			DeliveredAt:    timeToProto(d.DeliveredAt),
+			CreatedAt:      timeToProto(d.CreatedAt),
+		})
+	}
+	if len(deliveries) == int(pageSize) {
+		resp.NextPageToken = strconv.FormatInt(deliveries[len(deliveries)-1].ID, 10)
+	}
+	return resp, nil
+}
+
+func validateWebhookURL(rawURL string) error {
+	if rawURL == "" {
+		return grpcerr.InvalidField("url", grpcerr.ReasonFieldRequired, "url is required")
+	}
+	if len(rawURL) > maxWebhookURLLen {
+		return grpcerr.InvalidField("url", grpcerr.ReasonFieldTooLong, "url is too long")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil {
+		return grpcerr.InvalidField("url", grpcerr.ReasonFieldInvalid, "url must be an absolute http(s) url")
+	}
+	return nil
+}
+
+// validateEventFilter: известный тип события, "*" или префикс вида "user.*".
+func validateEventFilter(eventTypes []string) ([]string, error) {
+	filter := make([]string, 0, len(eventTypes))
+	for i, t := range eventTypes {
+		t = strings.TrimSpace(t)
+		if !knownEventFilter(t) {
+			return nil, grpcerr.InvalidField(fmt.Sprintf("event_types[%d]", i), grpcerr.ReasonFieldInvalid, fmt.Sprintf("unknown event type %q", t))
+		}
+		if !slices.Contains(filter, t) {
+			filter = append(filter, t)
+		}
+	}
+	return filter, nil
+}
+
+func knownEventFilter(t string) bool {
+	if t == "*" || slices.Contains(models.EventTypes, t) {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(t, ".*")
+	if !ok || prefix == "" {
+		return false
+	}
+	for _, known := range models.EventTypes {
+		if strings.HasPrefix(known, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func webhookToProto(w models.Webhook) *authv1.Webhook {
+	return &authv1.Webhook{
+		Id:         w.ID,
+		Url:        w.URL,
+		EventTypes: w.EventTypes,
+		CreatedAt:  timeToProto(w.CreatedAt),
+	}
+}
@@ -0,0 +1,135 @@
+package healthcheck
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/logger"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	defaultInterval = 5 * time.Second
+	defaultTimeout  = 2 * time.Second
+)
+
+// Probe — проверка зависимости, без которой сервис не может отвечать (Postgres).
+type Probe func(ctx context.Context) error
+
+// Checker ведёт статус grpc.health.v1: до первой успешной проверки и после провала —
+// NOT_SERVING, затем периодически перепроверяет зависимость. После Shutdown статус
+// больше не меняется, чтобы балансировщики успели увести трафик.
+type Checker struct {
+	server   *health.Server
+	probe    Probe
+	services []string
+	interval time.Duration
+	timeout  time.Duration
+
+	mu      sync.Mutex
+	serving bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+type CheckerDeps struct {
+	Server *health.Server
+	Probe  Probe
+	// Services — имена сервисов, которые получают тот же статус, что и сервер целиком ("")
+	Services []string
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+func NewChecker(deps CheckerDeps) *Checker {
+	c := &Checker{
+		server:   deps.Server,
+		probe:    deps.Probe,
+		services: append([]string{""}, deps.Services...),
+		interval: deps.Interval,
+		timeout:  deps.Timeout,
+	}
+	if c.interval <= 0 {
+		c.interval = defaultInterval
+	}
+	if c.timeout <= 0 {
+		c.timeout = defaultTimeout
+	}
+	c.set(healthpb.HealthCheckResponse_NOT_SERVING)
+	return c
+}
+
+// Start делает первую проверку синхронно (статус готов к моменту Serve) и запускает фоновые.
+func (c *Checker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+
+	c.check(ctx)
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.run(ctx)
+	}()
+}
+
+// Shutdown переводит сервер в NOT_SERVING навсегда; вызывается в начале остановки.
+func (c *Checker) Shutdown() {
+	c.server.Shutdown()
+	c.Stop()
+}
+
+func (c *Checker) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.wg.Wait()
+}
+
+func (c *Checker) run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.check(ctx)
+		}
+	}
+}
+
+func (c *Checker) check(ctx context.Context) {
+	probeCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	err := c.probe(probeCtx)
+	cancel()
+	if ctx.Err() != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case err != nil && c.serving:
+		logger.Log.Errorf("health: dependency check failed, NOT_SERVING: %s", err)
+	case err != nil:
+		logger.Log.Warnf("health: dependency check failed: %s", err)
+	case !c.serving:
+		logger.Log.Info("health: SERVING")
+	}
+	c.serving = err == nil
+
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if c.serving {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	c.set(status)
+}
+
+func (c *Checker) set(status healthpb.HealthCheckResponse_ServingStatus) {
+	for _, svc := range c.services {
+		c.server.SetServingStatus(svc, status)
+	}
+}
@@ -0,0 +1,82 @@
+package i18n
+
+// catalog — тексты по reason из grpcerr (ErrorInfo и FieldViolation).
+// Новые reason добавлять во все языки сразу.
+var catalog = map[string]map[string]string{
+	LocaleRU: {
+		"FIELD_REQUIRED":          "Поле {field} обязательно",
+		"FIELD_INVALID":           "Некорректное значение поля {field}",
+		"FIELD_TOO_LONG":          "Слишком длинное значение поля {field}",
+		"INVALID_CREDENTIALS":     "Неверный email или пароль",
+		"EMAIL_TAKEN":             "Этот email уже зарегистрирован",
+		"EMAIL_INVALID":           "Некорректный email",
+		"PASSWORD_POLICY":         "Пароль не соответствует требованиям",
+		"LINK_CODE_INVALID":       "Код привязки не найден",
+		"LINK_CODE_EXPIRED":       "Срок действия кода привязки истёк",
+		"LINK_CODE_USED":          "Код привязки уже использован",
+		"TELEGRAM_ALREADY_LINKED": "Этот Telegram уже привязан к аккаунту",
+		"TELEGRAM_NOT_LINKED":     "Telegram не привязан к аккаунту",
+		"USER_NOT_FOUND":          "Пользователь не найден",
+		"USER_BLOCKED":            "Аккаунт заблокирован",
+		"WEBHOOK_NOT_FOUND":       "Вебхук не найден",
+		"MERGE_NOT_ALLOWED":       "Эти аккаунты нельзя объединить",
+		"UNAUTHENTICATED":         "Требуется вход в аккаунт",
+		"BAD_BOT_SIGNATURE":       "Неверная подпись бота",
+		"REPLAY_DETECTED":         "Повторный запрос отклонён",
+		"FORBIDDEN":               "Недостаточно прав",
+		"CSRF_FAILED":             "Запрос отклонён: обновите страницу",
+		"CONCURRENT_UPDATE":       "Данные изменились одновременно с запросом, повторите попытку",
+		"DEADLINE_EXCEEDED":       "Превышено время ожидания",
+		"CANCELED":                "Запрос отменён",
+		"INTERNAL":                "Внутренняя ошибка, попробуйте позже",
+
+		"PASSWORD_POLICY_MIN_LENGTH":  "Пароль должен быть не короче {min_length} символов",
+		"PASSWORD_POLICY_MAX_LENGTH":  "Пароль должен быть не длиннее {max_length} символов",
+		"PASSWORD_POLICY_LOWERCASE":   "Пароль должен содержать строчную букву",
+		"PASSWORD_POLICY_UPPERCASE":   "Пароль должен содержать заглавную букву",
+		"PASSWORD_POLICY_DIGIT":       "Пароль должен содержать цифру",
+		"PASSWORD_POLICY_SYMBOL":      "Пароль должен содержать спецсимвол",
+		"PASSWORD_POLICY_MIN_CLASSES": "Пароль должен содержать не менее {min_classes} из: строчные, заглавные, цифры, спецсимволы",
+		"PASSWORD_POLICY_COMMON":      "Пароль слишком распространён",
+		"PASSWORD_POLICY_EMAIL":       "Пароль не должен содержать email",
+		"PASSWORD_POLICY_BREACHED":    "Пароль встречается в утечках данных",
+	},
+	LocaleEN: {
+		"FIELD_REQUIRED":          "Field {field} is required",
+		"FIELD_INVALID":           "Field {field} has an invalid value",
+		"FIELD_TOO_LONG":          "Field {field} is too long",
+		"INVALID_CREDENTIALS":     "Invalid email or password",
+		"EMAIL_TAKEN":             "This email is already registered",
+		"EMAIL_INVALID":           "Invalid email address",
+		"PASSWORD_POLICY":         "Password does not meet the requirements",
+		"LINK_CODE_INVALID":       "Link code not found",
+		"LINK_CODE_EXPIRED":       "Link code has expired",
+		"LINK_CODE_USED":          "Link code has already been used",
+		"TELEGRAM_ALREADY_LINKED": "This Telegram account is already linked",
+		"TELEGRAM_NOT_LINKED":     "Telegram is not linked to the account",
+		"USER_NOT_FOUND":          "User not found",
+		"USER_BLOCKED":            "Account is blocked",
+		"WEBHOOK_NOT_FOUND":       "Webhook not found",
+		"MERGE_NOT_ALLOWED":       "These accounts cannot be merged",
+		"UNAUTHENTICATED":         "Please sign in",
+		"BAD_BOT_SIGNATURE":       "Invalid bot signature",
+		"REPLAY_DETECTED":         "Repeated request rejected",
+		"FORBIDDEN":               "Permission denied",
+		"CSRF_FAILED":             "Request rejected, please reload the page",
+		"CONCURRENT_UPDATE":       "The data was changed concurrently, please retry",
+		"DEADLINE_EXCEEDED":       "Request timed out",
+		"CANCELED":                "Request was canceled",
+		"INTERNAL":                "Internal error, please try again later",
+
+		"PASSWORD_POLICY_MIN_LENGTH":  "Password must be at least {min_length} characters",
+		"PASSWORD_POLICY_MAX_LENGTH":  "Password must be at most {max_length} characters",
+		"PASSWORD_POLICY_LOWERCASE":   "Password must contain a lowercase letter",
+		"PASSWORD_POLICY_UPPERCASE":   "Password must contain an uppercase letter",
+		"PASSWORD_POLICY_DIGIT":       "Password must contain a digit",
+		"PASSWORD_POLICY_SYMBOL":      "Password must contain a symbol",
+		"PASSWORD_POLICY_MIN_CLASSES": "Password must contain at least {min_classes} of: lowercase, uppercase, digits, symbols",
+		"PASSWORD_POLICY_COMMON":      "Password is too common",
+		"PASSWORD_POLICY_EMAIL":       "Password must not contain the email address",
+		"PASSWORD_POLICY_BREACHED":    "Password appeared in a known data breach",
+	},
+}
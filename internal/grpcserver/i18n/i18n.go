@@ -0,0 +1,95 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Поддерживаемые языки сообщений об ошибках.
+const (
+	LocaleRU = "ru"
+	LocaleEN = "en"
+)
+
+// DefaultLocale — язык, если клиент не прислал поддерживаемый.
+const DefaultLocale = LocaleRU
+
+// Supported приводит тег (ru-RU, EN_us, ru) к поддерживаемому языку.
+func Supported(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	base, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	if _, ok := catalog[base]; ok {
+		return base, true
+	}
+	return "", false
+}
+
+// Resolve выбирает язык ответа: явный x-locale, затем language_code пользователя
+// Telegram (запросы бота), затем accept-language, иначе DefaultLocale.
+func Resolve(xLocale, languageCode, acceptLanguage string) string {
+	if locale, ok := Supported(xLocale); ok {
+		return locale
+	}
+	if locale, ok := Supported(languageCode); ok {
+		return locale
+	}
+	if locale, ok := FromAcceptLanguage(acceptLanguage); ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// FromAcceptLanguage выбирает поддерживаемый язык из accept-language с учётом q.
+func FromAcceptLanguage(header string) (string, bool) {
+	type candidate struct {
+		locale string
+		q      float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		locale, ok := Supported(tag)
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if v, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{locale: locale, q: q})
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	// при равном q порядок в заголовке сохраняется
+	sort.SliceStable(candidates, func(a, b int) bool { return candidates[a].q > candidates[b].q })
+	return candidates[0].locale, true
+}
+
+// Message — текст по ключу (reason ошибки) на языке locale.
+// Плейсхолдеры {name} заполняются из params; если ключа нет в каталоге
+// или не хватает параметра, ok = false.
+func Message(locale, key string, params map[string]string) (string, bool) {
+	msg, ok := catalog[locale][key]
+	if !ok {
+		return "", false
+	}
+	if len(params) > 0 {
+		pairs := make([]string, 0, len(params)*2)
+		for k, v := range params {
+			pairs = append(pairs, "{"+k+"}", v)
+		}
+		msg = strings.NewReplacer(pairs...).Replace(msg)
+	}
+	if strings.Contains(msg, "{") {
+		return "", false
+	}
+	return msg, true
+}
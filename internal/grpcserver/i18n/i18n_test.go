@@ -0,0 +1,75 @@
+package i18n
+
+import "testing"
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name           string
+		xLocale        string
+		languageCode   string
+		acceptLanguage string
+		want           string
+	}{
+		{"empty", "", "", "", DefaultLocale},
+		{"x-locale wins", "en", "ru", "ru", LocaleEN},
+		{"x-locale region", "EN_us", "", "", LocaleEN},
+		{"unsupported x-locale falls through", "de", "en", "", LocaleEN},
+		{"telegram language before accept-language", "", "en", "ru", LocaleEN},
+		{"accept-language", "", "", "en-US,en;q=0.9", LocaleEN},
+		{"accept-language q order", "", "", "en;q=0.3, ru;q=0.8", LocaleRU},
+		{"accept-language equal q keeps order", "", "", "en, ru", LocaleEN},
+		{"accept-language skips unsupported", "", "", "de, fr;q=0.9, en;q=0.1", LocaleEN},
+		{"accept-language q=0 ignored", "", "", "en;q=0", DefaultLocale},
+		{"accept-language bad q ignored", "", "", "en;q=abc", DefaultLocale},
+		{"nothing supported", "de", "fr", "es", DefaultLocale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Resolve(tt.xLocale, tt.languageCode, tt.acceptLanguage); got != tt.want {
+				t.Errorf("Resolve(%q, %q, %q) = %q, want %q",
+					tt.xLocale, tt.languageCode, tt.acceptLanguage, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale string
+		key    string
+		params map[string]string
+		want   string
+		ok     bool
+	}{
+		{"ru", LocaleRU, "USER_BLOCKED", nil, "Аккаунт заблокирован", true},
+		{"en", LocaleEN, "USER_BLOCKED", nil, "Account is blocked", true},
+		{"placeholder", LocaleEN, "FIELD_REQUIRED", map[string]string{"field": "email"}, "Field email is required", true},
+		{"limit placeholder", LocaleRU, "PASSWORD_POLICY_MIN_LENGTH", map[string]string{"min_length": "12"},
+			"Пароль должен быть не короче 12 символов", true},
+		{"missing param", LocaleEN, "FIELD_REQUIRED", nil, "", false},
+		{"unknown key", LocaleEN, "NO_SUCH_REASON", nil, "", false},
+		{"unknown locale", "de", "USER_BLOCKED", nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Message(tt.locale, tt.key, tt.params)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Message(%q, %q) = %q, %v; want %q, %v", tt.locale, tt.key, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+// Каталог должен быть полным: ключ, переведённый на одном языке, есть во всех.
+func TestCatalogComplete(t *testing.T) {
+	for locale, messages := range catalog {
+		for other, otherMessages := range catalog {
+			for key := range messages {
+				if _, ok := otherMessages[key]; !ok {
+					t.Errorf("key %s is in %s but missing in %s", key, locale, other)
+				}
+			}
+		}
+	}
+}
@@ -2,47 +2,104 @@ package authinterceptor
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 
-	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/gen/authv1"
 	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/reqmeta"
 	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/authctx"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/grpcerr"
 	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/grpcutil"
 	grpcports "github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/interface"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/servertls"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
+// botRejections — отклонённые вызовы BOT-методов по reason (BAD_BOT_SIGNATURE, REPLAY_DETECTED, ...).
+var botRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "bottrade_bot_signature_rejections_total",
+	Help: "Rejected bot-signed requests by error reason.",
+}, []string{"reason"})
+
 type AuthInterceptor struct {
 	svcBotVerifier   grpcports.BotVerifier
 	svcTokenVerifier grpcports.TokenVerifier
+	svcAdminVerifier grpcports.AdminVerifier
+
+	// levels — требуемый уровень доступа по full method name.
+	// Собирается из аннотаций (bottrade.auth.v1.auth) в proto при старте.
+	levels map[string]authv1.AuthLevel
 
-	PublicMethods map[string]struct{}
-	BotMethods    map[string]struct{}
+	// serviceMethods — BOT-методы, которые сервис с mTLS-идентичностью вызывает без bot-signature
+	serviceMethods map[string]map[string]bool
 }
 
 type AuthInterceptorDeps struct {
 	BotVerifier   grpcports.BotVerifier
 	TokenVerifier grpcports.TokenVerifier
+	AdminVerifier grpcports.AdminVerifier
+
+	// ServiceIdentities — идентичность клиентского сертификата -> разрешённые full method names
+	ServiceIdentities map[string][]string
+	// PublicServices — сторонние сервисы без наших аннотаций, открытые целиком (grpc.health.v1)
+	PublicServices []string
 }
 
 func NewAuthInterceptor(deps AuthInterceptorDeps) *AuthInterceptor {
 	return &AuthInterceptor{
 		svcBotVerifier:   deps.BotVerifier,
 		svcTokenVerifier: deps.TokenVerifier,
-		PublicMethods: map[string]struct{}{
-			"/bottrade.auth.v1.AuthService/Register": {},
-			"/bottrade.auth.v1.AuthService/Login":    {},
-		},
-		BotMethods: map[string]struct{}{
-			"/bottrade.auth.v1.AuthService/LinkTelegram": {},
-			"/bottrade.auth.v1.AuthService/TelegramAuth": {},
-		},
+		svcAdminVerifier: deps.AdminVerifier,
+		levels:           methodLevels(protoregistry.GlobalFiles, deps.PublicServices),
+		serviceMethods:   serviceMethods(deps.ServiceIdentities),
 	}
 }
 
+// CheckServices проверяет, что у каждого зарегистрированного на сервере метода
+// есть аннотация уровня доступа. Вызывается после регистрации сервисов.
+func (i *AuthInterceptor) CheckServices(services map[string]grpc.ServiceInfo) error {
+	var missing []string
+	for svcName, svc := range services {
+		for _, m := range svc.Methods {
+			fullMethod := "/" + svcName + "/" + m.Name
+			if i.levels[fullMethod] == authv1.AuthLevel_AUTH_LEVEL_UNSPECIFIED {
+				missing = append(missing, fullMethod)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("methods without (bottrade.auth.v1.auth) annotation: %s", strings.Join(missing, ", "))
+	}
+
+	// mTLS-идентичность заменяет только подпись бота: USER/ADMIN-методам нужен пользователь
+	var notBot []string
+	for id, methods := range i.serviceMethods {
+		for m := range methods {
+			if i.levels[m] != authv1.AuthLevel_BOT {
+				notBot = append(notBot, id+": "+m)
+			}
+		}
+	}
+	if len(notBot) > 0 {
+		sort.Strings(notBot)
+		return fmt.Errorf("service identities allow non-BOT or unknown methods: %s", strings.Join(notBot, ", "))
+	}
+	return nil
+}
+
 func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
@@ -51,48 +108,140 @@ func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 		handler grpc.UnaryHandler,
 	) (any, error) {
 
-		// 1) public methods: no auth
-		if _, ok := i.PublicMethods[info.FullMethod]; ok {
+		switch i.levels[info.FullMethod] {
+		case authv1.AuthLevel_PUBLIC:
+			// 1) public methods: no auth
 			return handler(ctx, req)
-		}
 
-		// 2) bot methods: require bot signature metadata
-		if _, ok := i.BotMethods[info.FullMethod]; ok {
-			md, _ := metadata.FromIncomingContext(ctx)
-			meta, err := extractBotMeta(md)
+		case authv1.AuthLevel_BOT:
+			// 2) bot methods: require bot signature metadata
+			ctx, err := i.verifyBot(ctx, info.FullMethod, req)
 			if err != nil {
+				botRejections.WithLabelValues(grpcerr.Reason(err)).Inc()
 				return nil, err
 			}
+			// bot methods обычно не кладут user_id, потому что user_id определяется позже (по tg_id).
+			return handler(ctx, req)
 
-			reqBytes, err := marshalReqBytes(req)
+		case authv1.AuthLevel_USER:
+			// 3) jwt required
+			ctx, err := i.verifyUser(ctx)
 			if err != nil {
-				return nil, status.Error(codes.Internal, "failed to marshal request")
+				return nil, err
 			}
+			return handler(ctx, req)
 
-			if err := i.svcBotVerifier.ValidateBotSignature(ctx, meta, info.FullMethod, reqBytes); err != nil {
-				return nil, mapSvcErr(err)
+		case authv1.AuthLevel_ADMIN:
+			// 4) jwt + роль admin
+			ctx, err := i.verifyUser(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if err := i.verifyAdmin(ctx); err != nil {
+				return nil, err
 			}
-
-			// bot methods обычно не кладут user_id, потому что user_id определяется позже (по tg_id).
 			return handler(ctx, req)
-		}
 
-		// 3) jwt required
-		md, _ := metadata.FromIncomingContext(ctx)
-		authz := grpcutil.GetMDString(md, "authorization")
-		token, ok := grpcutil.ParseBearer(authz)
-		if !ok {
-			return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+		default:
+			// нет аннотации — fail closed
+			return nil, grpcerr.New(codes.PermissionDenied, grpcerr.ReasonForbidden, "method access is not configured")
 		}
+	}
+}
 
-		userID, err := i.svcTokenVerifier.ValidateAccessToken(ctx, token)
-		if err != nil {
-			return nil, mapSvcErr(err)
+func (i *AuthInterceptor) verifyBot(ctx context.Context, fullMethod string, req any) (context.Context, error) {
+	// внутренний сервис с клиентским сертификатом: подпись не нужна, если метод ему разрешён
+	if id, ok := servertls.PeerIdentity(ctx); ok && i.serviceMethods[id][fullMethod] {
+		return reqmeta.WithBotID(ctx, id), nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	meta, err := extractBotMeta(md)
+	if err != nil {
+		return ctx, err
+	}
+
+	reqBytes, err := marshalReqBytes(req)
+	if err != nil {
+		return ctx, grpcerr.New(codes.Internal, grpcerr.ReasonInternal, "failed to marshal request")
+	}
+
+	if err := i.svcBotVerifier.ValidateBotSignature(ctx, meta, fullMethod, reqBytes); err != nil {
+		return ctx, grpcerr.FromError(ctx, err)
+	}
+
+	return reqmeta.WithBotID(ctx, meta.BotID), nil
+}
+
+func (i *AuthInterceptor) verifyUser(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	authz := grpcutil.GetMDString(md, "authorization")
+	token, ok := grpcutil.ParseBearer(authz)
+	if !ok {
+		return ctx, grpcerr.New(codes.Unauthenticated, grpcerr.ReasonUnauthenticated, "missing bearer token")
+	}
+
+	userID, err := i.svcTokenVerifier.ValidateAccessToken(ctx, token)
+	if err != nil {
+		return ctx, grpcerr.FromError(ctx, err)
+	}
+
+	ctx = authctx.WithUserID(ctx, userID)
+	if uid, err := strconv.ParseInt(userID, 10, 32); err == nil {
+		ctx = reqmeta.WithUserID(ctx, int32(uid))
+	}
+	return ctx, nil
+}
+
+func (i *AuthInterceptor) verifyAdmin(ctx context.Context) error {
+	if i.svcAdminVerifier == nil {
+		return grpcerr.New(codes.PermissionDenied, grpcerr.ReasonForbidden, "admin role required")
+	}
+	userID, _ := authctx.UserID(ctx)
+	if err := i.svcAdminVerifier.RequireAdmin(ctx, userID); err != nil {
+		return grpcerr.FromError(ctx, err)
+	}
+	return nil
+}
+
+// methodLevels собирает уровни доступа всех методов из зарегистрированных proto-дескрипторов;
+// методы publicServices — PUBLIC без аннотаций.
+func methodLevels(files *protoregistry.Files, publicServices []string) map[string]authv1.AuthLevel {
+	levels := make(map[string]authv1.AuthLevel)
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		services := fd.Services()
+		for si := 0; si < services.Len(); si++ {
+			sd := services.Get(si)
+			public := slices.Contains(publicServices, string(sd.FullName()))
+			methods := sd.Methods()
+			for mi := 0; mi < methods.Len(); mi++ {
+				md := methods.Get(mi)
+				if public {
+					levels["/"+string(sd.FullName())+"/"+string(md.Name())] = authv1.AuthLevel_PUBLIC
+					continue
+				}
+				opts, ok := md.Options().(*descriptorpb.MethodOptions)
+				if !ok || !proto.HasExtension(opts, authv1.E_Auth) {
+					continue
+				}
+				level := proto.GetExtension(opts, authv1.E_Auth).(authv1.AuthLevel)
+				levels["/"+string(sd.FullName())+"/"+string(md.Name())] = level
+			}
 		}
+		return true
+	})
+	return levels
+}
 
-		ctx = authctx.WithUserID(ctx, userID)
-		return handler(ctx, req)
+func serviceMethods(identities map[string][]string) map[string]map[string]bool {
+	out := make(map[string]map[string]bool, len(identities))
+	for id, methods := range identities {
+		out[id] = make(map[string]bool, len(methods))
+		for _, m := range methods {
+			out[id][m] = true
+		}
 	}
+	return out
 }
 
 func extractBotMeta(md metadata.MD) (models.BotMeta, error) {
@@ -102,17 +251,13 @@ func extractBotMeta(md metadata.MD) (models.BotMeta, error) {
 	sig := grpcutil.GetMDString(md, "x-signature")
 
 	if botID == "" || tsStr == "" || nonce == "" || sig == "" {
-		return models.BotMeta{}, status.Error(codes.Unauthenticated, "missing bot signature headers")
+		return models.BotMeta{}, grpcerr.New(codes.Unauthenticated, grpcerr.ReasonBadBotSignature, "missing bot signature headers")
 	}
 
-	// Timestamp парсим максимально просто (ожидаем unix seconds)
-	// Лучше в сервисе ещё проверить окно времени.
-	var ts int64
-	for _, ch := range tsStr {
-		if ch < '0' || ch > '9' {
-			return models.BotMeta{}, status.Error(codes.Unauthenticated, "bad x-ts")
-		}
-		ts = ts*10 + int64(ch-'0')
+	// x-ts — unix seconds; окно времени проверяет сервис
+	ts, err := strconv.ParseInt(tsStr, 10, 64)
+	if err != nil || ts <= 0 {
+		return models.BotMeta{}, grpcerr.New(codes.Unauthenticated, grpcerr.ReasonBadBotSignature, "bad x-ts")
 	}
 
 	return models.BotMeta{
@@ -128,22 +273,8 @@ func marshalReqBytes(req any) ([]byte, error) {
 	if !ok {
 		// fallback: deterministic hash от типа+текущего времени не нужен
 		// лучше считать, что все req — proto.Message
-		return nil, status.Error(codes.Internal, "request is not proto message")
-	}
-	// proto.Marshal для подписи достаточно; можно сделать deterministic, если нужен строгий порядок
-	return proto.Marshal(pm)
-}
-
-// mapSvcErr — общий маппинг сервисных ошибок в gRPC codes
-func mapSvcErr(err error) error {
-	switch err {
-	case modelerrors.ErrUnauthorized, modelerrors.ErrBadBotSignature:
-		return status.Error(codes.Unauthenticated, err.Error())
-	case modelerrors.ErrForbidden:
-		return status.Error(codes.PermissionDenied, err.Error())
-	case modelerrors.ErrReplay:
-		return status.Error(codes.Unauthenticated, "replay detected")
-	default:
-		return status.Error(codes.Internal, "internal error")
+		return nil, grpcerr.New(codes.Internal, grpcerr.ReasonInternal, "request is not proto message")
 	}
+	// подпись считается от детерминированной сериализации (см. svcauth.SignBotRequest)
+	return proto.MarshalOptions{Deterministic: true}.Marshal(pm)
 }
@@ -0,0 +1,291 @@
+package authinterceptor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/IvanOplesnin/BotTradeService.git/gen/authv1"
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/reqmeta"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/grpcerr"
+	grpcports "github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/interface"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type tokenVerifierFunc func(ctx context.Context, accessToken string) (string, error)
+
+func (f tokenVerifierFunc) ValidateAccessToken(ctx context.Context, accessToken string) (string, error) {
+	return f(ctx, accessToken)
+}
+
+type adminVerifierFunc func(ctx context.Context, userID string) error
+
+func (f adminVerifierFunc) RequireAdmin(ctx context.Context, userID string) error {
+	return f(ctx, userID)
+}
+
+type botVerifierFunc func(ctx context.Context, meta models.BotMeta, fullMethod string, reqBytes []byte) error
+
+func (f botVerifierFunc) ValidateBotSignature(ctx context.Context, meta models.BotMeta, fullMethod string, reqBytes []byte) error {
+	return f(ctx, meta, fullMethod, reqBytes)
+}
+
+// call прогоняет запрос через Unary и сообщает, дошёл ли он до обработчика.
+func call(t *testing.T, i *AuthInterceptor, ctx context.Context, method string) (reached bool, err error) {
+	t.Helper()
+	_, err = i.Unary()(ctx, &authv1.PullBotMessagesRequest{}, &grpc.UnaryServerInfo{FullMethod: method},
+		func(ctx context.Context, req any) (any, error) {
+			reached = true
+			return &authv1.PullBotMessagesResponse{}, nil
+		})
+	return reached, err
+}
+
+func botHeaders() context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"x-bot-id", "tg-bot", "x-ts", "1700000000", "x-nonce", "n1", "x-signature", "v1=00",
+	))
+}
+
+func TestBotRejectionsCounted(t *testing.T) {
+	i := NewAuthInterceptor(AuthInterceptorDeps{
+		BotVerifier: botVerifierFunc(func(context.Context, models.BotMeta, string, []byte) error {
+			return modelerrors.ErrReplay
+		}),
+	})
+	badSig := botRejections.WithLabelValues(grpcerr.ReasonBadBotSignature)
+	replay := botRejections.WithLabelValues(grpcerr.ReasonReplayDetected)
+	badBefore, replayBefore := testutil.ToFloat64(badSig), testutil.ToFloat64(replay)
+
+	// без заголовков подписи
+	reached, err := call(t, i, context.Background(), authv1.AuthService_PullBotMessages_FullMethodName)
+	if reached || status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("unsigned call: reached=%v err=%v", reached, err)
+	}
+	// подпись отвергнута сервисом
+	reached, err = call(t, i, botHeaders(), authv1.AuthService_PullBotMessages_FullMethodName)
+	if reached || status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("replayed call: reached=%v err=%v", reached, err)
+	}
+
+	if got := testutil.ToFloat64(badSig) - badBefore; got != 1 {
+		t.Errorf("BAD_BOT_SIGNATURE rejections = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(replay) - replayBefore; got != 1 {
+		t.Errorf("REPLAY_DETECTED rejections = %v, want 1", got)
+	}
+}
+
+// serviceInfo — то же, что grpc.Server.GetServiceInfo отдаёт для зарегистрированного сервиса.
+func serviceInfo(descs ...grpc.ServiceDesc) map[string]grpc.ServiceInfo {
+	out := make(map[string]grpc.ServiceInfo, len(descs))
+	for _, d := range descs {
+		var methods []grpc.MethodInfo
+		for _, m := range d.Methods {
+			methods = append(methods, grpc.MethodInfo{Name: m.MethodName})
+		}
+		out[d.ServiceName] = grpc.ServiceInfo{Methods: methods}
+	}
+	return out
+}
+
+func TestCheckServices(t *testing.T) {
+	annotated := []grpc.ServiceDesc{authv1.AuthService_ServiceDesc, authv1.AdminService_ServiceDesc}
+	if err := NewAuthInterceptor(AuthInterceptorDeps{}).CheckServices(serviceInfo(annotated...)); err != nil {
+		t.Fatalf("annotated services rejected: %v", err)
+	}
+
+	// сервис без аннотаций — сервер не должен стартовать
+	unannotated := grpc.ServiceDesc{
+		ServiceName: "bottrade.test.v1.Unannotated",
+		Methods:     []grpc.MethodDesc{{MethodName: "Do"}},
+	}
+	err := NewAuthInterceptor(AuthInterceptorDeps{}).CheckServices(serviceInfo(append(annotated, unannotated)...))
+	if err == nil || !strings.Contains(err.Error(), "/bottrade.test.v1.Unannotated/Do") {
+		t.Fatalf("unannotated method: err = %v", err)
+	}
+
+	// он же, объявленный публичным (как grpc.health.v1), не в реестре дескрипторов — тоже отказ
+	public := NewAuthInterceptor(AuthInterceptorDeps{PublicServices: []string{unannotated.ServiceName}})
+	if err := public.CheckServices(serviceInfo(unannotated)); err == nil {
+		t.Fatal("service unknown to the proto registry accepted")
+	}
+}
+
+func TestUnknownMethodDenied(t *testing.T) {
+	i := NewAuthInterceptor(AuthInterceptorDeps{
+		TokenVerifier: tokenVerifierFunc(func(context.Context, string) (string, error) { return "7", nil }),
+	})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer t"))
+
+	for _, method := range []string{"/bottrade.test.v1.Unannotated/Do", "/bottrade.auth.v1.AuthService/Nope", ""} {
+		reached, err := call(t, i, ctx, method)
+		if reached || status.Code(err) != codes.PermissionDenied || grpcerr.Reason(err) != grpcerr.ReasonForbidden {
+			t.Errorf("%q: reached=%v err=%v", method, reached, err)
+		}
+	}
+}
+
+func TestAdminLevelRequiresAdmin(t *testing.T) {
+	tokens := tokenVerifierFunc(func(_ context.Context, token string) (string, error) {
+		if token != "valid" {
+			return "", modelerrors.ErrUnauthorized
+		}
+		return "7", nil
+	})
+	bearer := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	}
+	var checkedUser string
+	admins := adminVerifierFunc(func(_ context.Context, userID string) error {
+		checkedUser = userID
+		if userID != "1" {
+			return modelerrors.ErrForbidden
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name     string
+		admins   grpcports.AdminVerifier
+		ctx      context.Context
+		want     codes.Code
+		wantUser string
+	}{
+		{name: "no token", admins: admins, ctx: context.Background(), want: codes.Unauthenticated},
+		{name: "bad token", admins: admins, ctx: bearer("bad"), want: codes.Unauthenticated},
+		{name: "user is not admin", admins: admins, ctx: bearer("valid"), want: codes.PermissionDenied, wantUser: "7"},
+		{name: "no admin verifier", ctx: bearer("valid"), want: codes.PermissionDenied},
+		{
+			name:     "admin",
+			admins:   adminVerifierFunc(func(_ context.Context, userID string) error { checkedUser = userID; return nil }),
+			ctx:      bearer("valid"),
+			want:     codes.OK,
+			wantUser: "7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkedUser = ""
+			i := NewAuthInterceptor(AuthInterceptorDeps{TokenVerifier: tokens, AdminVerifier: tt.admins})
+
+			reached, err := call(t, i, tt.ctx, authv1.AdminService_ListUsers_FullMethodName)
+			if status.Code(err) != tt.want || reached != (tt.want == codes.OK) {
+				t.Fatalf("reached=%v err=%v, want %v", reached, err, tt.want)
+			}
+			if checkedUser != tt.wantUser {
+				t.Fatalf("RequireAdmin called for %q, want %q", checkedUser, tt.wantUser)
+			}
+		})
+	}
+
+	// тот же пользователь на USER-методе RequireAdmin не проходит
+	checkedUser = ""
+	i := NewAuthInterceptor(AuthInterceptorDeps{TokenVerifier: tokens, AdminVerifier: admins})
+	if reached, err := call(t, i, bearer("valid"), authv1.AuthService_ListLoginHistory_FullMethodName); !reached || err != nil {
+		t.Fatalf("USER method: reached=%v err=%v", reached, err)
+	}
+	if checkedUser != "" {
+		t.Fatal("RequireAdmin called for USER method")
+	}
+}
+
+// peerWithCert — контекст соединения с проверенным клиентским сертификатом (mTLS).
+func peerWithCert(ctx context.Context, cert *x509.Certificate) context.Context {
+	return peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{
+		State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+	}})
+}
+
+func TestServiceIdentityOnlyForAllowedBotMethods(t *testing.T) {
+	const notifier = "spiffe://bottrade/notify"
+	identities := map[string][]string{notifier: {authv1.AuthService_PullBotMessages_FullMethodName}}
+	var verified bool
+	i := NewAuthInterceptor(AuthInterceptorDeps{
+		BotVerifier: botVerifierFunc(func(context.Context, models.BotMeta, string, []byte) error {
+			verified = true
+			return modelerrors.ErrBadBotSignature
+		}),
+		TokenVerifier:     tokenVerifierFunc(func(context.Context, string) (string, error) { return "", modelerrors.ErrUnauthorized }),
+		ServiceIdentities: identities,
+	})
+	service := peerWithCert(context.Background(), &x509.Certificate{URIs: []*url.URL{{Scheme: "spiffe", Host: "bottrade", Path: "/notify"}}})
+	stranger := peerWithCert(context.Background(), &x509.Certificate{Subject: pkix.Name{CommonName: "stranger"}})
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		reached  bool
+		verified bool // дошло до проверки bot-signature
+	}{
+		{name: "allowed bot method", ctx: service, method: authv1.AuthService_PullBotMessages_FullMethodName, reached: true},
+		{name: "other bot method needs signature", ctx: service, method: authv1.AuthService_TelegramAuth_FullMethodName},
+		{name: "user method needs jwt", ctx: service, method: authv1.AuthService_ListLoginHistory_FullMethodName},
+		{name: "admin method needs jwt", ctx: service, method: authv1.AdminService_ListUsers_FullMethodName},
+		{name: "unknown identity needs signature", ctx: stranger, method: authv1.AuthService_PullBotMessages_FullMethodName},
+		{
+			name:     "signed call from service still verified",
+			ctx:      metadata.NewIncomingContext(service, metadata.Pairs("x-bot-id", "tg-bot", "x-ts", "1", "x-nonce", "n", "x-signature", "v1=00")),
+			method:   authv1.AuthService_TelegramAuth_FullMethodName,
+			verified: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verified = false
+			var botID string
+			_, err := i.Unary()(tt.ctx, &authv1.PullBotMessagesRequest{}, &grpc.UnaryServerInfo{FullMethod: tt.method},
+				func(ctx context.Context, req any) (any, error) {
+					botID = reqmeta.From(ctx).BotID
+					return nil, nil
+				})
+			if reached := err == nil; reached != tt.reached {
+				t.Fatalf("reached=%v err=%v, want reached=%v", reached, err, tt.reached)
+			}
+			if tt.reached && botID != notifier {
+				t.Fatalf("bot id = %q, want %q", botID, notifier)
+			}
+			if !tt.reached && status.Code(err) != codes.Unauthenticated {
+				t.Fatalf("err = %v, want Unauthenticated", err)
+			}
+			if verified != tt.verified {
+				t.Fatalf("signature verified = %v, want %v", verified, tt.verified)
+			}
+		})
+	}
+}
+
+func TestCheckServicesRejectsNonBotIdentityMethods(t *testing.T) {
+	services := serviceInfo(authv1.AuthService_ServiceDesc, authv1.AdminService_ServiceDesc)
+	for _, method := range []string{
+		authv1.AuthService_ListLoginHistory_FullMethodName,
+		authv1.AdminService_ListUsers_FullMethodName,
+		authv1.AuthService_Login_FullMethodName,
+		"/bottrade.auth.v1.AuthService/Nope",
+	} {
+		i := NewAuthInterceptor(AuthInterceptorDeps{ServiceIdentities: map[string][]string{"svc": {method}}})
+		if err := i.CheckServices(services); err == nil || !strings.Contains(err.Error(), method) {
+			t.Errorf("identity allowed %s: err = %v", method, err)
+		}
+	}
+
+	i := NewAuthInterceptor(AuthInterceptorDeps{ServiceIdentities: map[string][]string{
+		"svc": {authv1.AuthService_PullBotMessages_FullMethodName, authv1.AuthService_TelegramAuth_FullMethodName},
+	}})
+	if err := i.CheckServices(services); err != nil {
+		t.Fatalf("bot methods rejected: %v", err)
+	}
+}
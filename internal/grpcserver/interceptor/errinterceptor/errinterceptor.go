@@ -0,0 +1,32 @@
+package errinterceptor
+
+import (
+	"context"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/reqmeta"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/grpcerr"
+	"google.golang.org/grpc"
+)
+
+// ErrInterceptor прогоняет ошибку любого обработчика или интерцептора глубже по цепочке
+// через grpcerr.FromError и переводит её на язык запроса (reqmeta.Locale).
+type ErrInterceptor struct{}
+
+func NewErrInterceptor() *ErrInterceptor {
+	return &ErrInterceptor{}
+}
+
+func (i *ErrInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, grpcerr.Localize(grpcerr.FromError(ctx, err), reqmeta.From(ctx).Locale)
+		}
+		return resp, nil
+	}
+}
@@ -4,8 +4,7 @@ import (
 	"context"
 	"time"
 
-	// <-- замени на свой logger пакет
-
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/redact"
 	l "github.com/IvanOplesnin/BotTradeService.git/internal/logger"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
@@ -15,11 +14,14 @@ import (
 )
 
 // LoggerInterceptor логирует каждый unary запрос.
-type LoggerInterceptor struct{}
+type LoggerInterceptor struct {
+	// logPayloads — отдельная debug-запись с телами запроса и ответа через redact.JSON
+	logPayloads bool
+}
 
 // NewLoggerInterceptor constructor (опционально)
-func NewLoggerInterceptor() *LoggerInterceptor {
-	return &LoggerInterceptor{}
+func NewLoggerInterceptor(logPayloads bool) *LoggerInterceptor {
+	return &LoggerInterceptor{logPayloads: logPayloads}
 }
 
 func (i *LoggerInterceptor) Unary() grpc.UnaryServerInterceptor {
@@ -31,8 +33,6 @@ func (i *LoggerInterceptor) Unary() grpc.UnaryServerInterceptor {
 	) (resp any, err error) {
 		start := time.Now()
 
-		method := info.FullMethod
-
 		// Если нужно — вытащим peer адрес (кто вызвал)
 		// p, _ := peer.FromContext(ctx)
 
@@ -49,8 +49,8 @@ func (i *LoggerInterceptor) Unary() grpc.UnaryServerInterceptor {
 		duration := time.Since(start)
 
 		// Чтобы level был “умнее”: ошибки -> Warn/Error
-		entry := l.Log.WithFields(logrus.Fields{
-			"method":      method,
+		// request id и метод — из ctx; пользователь ещё не известен, его выставит auth позже
+		entry := l.FromContext(ctx).WithFields(logrus.Fields{
 			"grpc_code":   code.String(),
 			"status_code": int(code), // иногда удобно
 			"duration":    duration,
@@ -58,6 +58,14 @@ func (i *LoggerInterceptor) Unary() grpc.UnaryServerInterceptor {
 			"resp_size":   respSize,
 		})
 
+		if i.logPayloads && l.Log.IsLevelEnabled(logrus.DebugLevel) {
+			payload := entry.WithField("request", redact.JSON(asProto(req)))
+			if err == nil {
+				payload = payload.WithField("response", redact.JSON(asProto(resp)))
+			}
+			payload.Debug("grpc payload")
+		}
+
 		if err != nil {
 			// gRPC codes: NotFound/InvalidArgument обычно Warn, Internal/Unavailable — Error
 			switch code {
@@ -75,13 +83,15 @@ func (i *LoggerInterceptor) Unary() grpc.UnaryServerInterceptor {
 }
 
 func protoSize(v any) int {
-	if v == nil {
-		return 0
-	}
-	m, ok := v.(proto.Message)
-	if !ok {
+	m := asProto(v)
+	if m == nil {
 		return 0
 	}
 	// proto.Size быстрее чем Marshal
 	return proto.Size(m)
 }
+
+func asProto(v any) proto.Message {
+	m, _ := v.(proto.Message)
+	return m
+}
@@ -0,0 +1,99 @@
+package metainterceptor
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/reqmeta"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/grpcutil"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/i18n"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// MetaInterceptor кладёт в ctx сведения о клиенте: метод, IP, user agent, язык.
+// Request id к этому моменту уже выставлен requestidinterceptor.
+type MetaInterceptor struct {
+	// trustProxyHeaders — брать IP из x-forwarded-for/x-real-ip.
+	// Включать только за своим балансировщиком, иначе заголовок подделывается клиентом.
+	trustProxyHeaders bool
+}
+
+func NewMetaInterceptor(trustProxyHeaders bool) *MetaInterceptor {
+	return &MetaInterceptor{trustProxyHeaders: trustProxyHeaders}
+}
+
+func (i *MetaInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		return handler(i.withMeta(ctx, info.FullMethod, req), req)
+	}
+}
+
+func (i *MetaInterceptor) withMeta(ctx context.Context, method string, req any) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	p, _ := peer.FromContext(ctx)
+	fromGateway := p != nil && p.Addr != nil && p.Addr.Network() == grpcutil.GatewayNetwork
+
+	userAgent := grpcutil.GetMDString(md, "user-agent")
+	if fromGateway {
+		userAgent = grpcutil.GetMDString(md, grpcutil.GatewayUserAgentKey)
+	}
+
+	return reqmeta.With(ctx, reqmeta.Meta{
+		RequestID: reqmeta.From(ctx).RequestID,
+		Method:    method,
+		IP:        i.clientIP(p, md, fromGateway),
+		UserAgent: userAgent,
+		Locale:    requestLocale(md, req),
+	})
+}
+
+// languageCoder — запросы бота с language_code пользователя Telegram.
+type languageCoder interface {
+	GetLanguageCode() string
+}
+
+// requestLocale: x-locale, затем language_code из запроса бота, затем accept-language.
+func requestLocale(md metadata.MD, req any) string {
+	var languageCode string
+	if lc, ok := req.(languageCoder); ok {
+		languageCode = lc.GetLanguageCode()
+	}
+	return i18n.Resolve(
+		grpcutil.GetMDString(md, "x-locale"),
+		languageCode,
+		strings.Join(md.Get("accept-language"), ","),
+	)
+}
+
+func (i *MetaInterceptor) clientIP(p *peer.Peer, md metadata.MD, fromGateway bool) string {
+	if i.trustProxyHeaders || fromGateway {
+		// x-forwarded-for: client, proxy1, proxy2 — нужен первый
+		if xff := grpcutil.GetMDString(md, "x-forwarded-for"); xff != "" {
+			first, _, _ := strings.Cut(xff, ",")
+			if ip := strings.TrimSpace(first); ip != "" {
+				return ip
+			}
+		}
+		if ip := strings.TrimSpace(grpcutil.GetMDString(md, "x-real-ip")); ip != "" {
+			return ip
+		}
+	}
+
+	if p == nil || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
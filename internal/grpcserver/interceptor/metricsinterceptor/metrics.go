@@ -0,0 +1,73 @@
+package metricsinterceptor
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	handlingSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "bottrade_grpc_server_handling_seconds",
+		Help:    "Unary RPC latency by method and gRPC code.",
+		Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"method", "code"})
+	requestBytes = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "bottrade_grpc_server_request_size_bytes",
+		Help:    "Unary RPC request message size.",
+		Buckets: prometheus.ExponentialBuckets(16, 4, 8),
+	}, []string{"method"})
+	responseBytes = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "bottrade_grpc_server_response_size_bytes",
+		Help:    "Unary RPC response message size (successful calls).",
+		Buckets: prometheus.ExponentialBuckets(16, 4, 8),
+	}, []string{"method"})
+	inFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "bottrade_grpc_server_in_flight_requests",
+		Help: "Unary RPCs currently being handled.",
+	}, []string{"method"})
+)
+
+// MetricsInterceptor пишет метрики Prometheus по каждому unary запросу.
+// Стоит первым после внешнего recovery, чтобы время включало все остальные интерцепторы.
+type MetricsInterceptor struct{}
+
+func NewMetricsInterceptor() *MetricsInterceptor {
+	return &MetricsInterceptor{}
+}
+
+func (i *MetricsInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		method := info.FullMethod
+		start := time.Now()
+		inFlight.WithLabelValues(method).Inc()
+		defer inFlight.WithLabelValues(method).Dec()
+
+		requestBytes.WithLabelValues(method).Observe(float64(protoSize(req)))
+		resp, err := handler(ctx, req)
+
+		handlingSeconds.WithLabelValues(method, status.Code(err).String()).Observe(time.Since(start).Seconds())
+		if err == nil {
+			responseBytes.WithLabelValues(method).Observe(float64(protoSize(resp)))
+		}
+		return resp, err
+	}
+}
+
+func protoSize(v any) int {
+	m, ok := v.(proto.Message)
+	if !ok || m == nil {
+		return 0
+	}
+	return proto.Size(m)
+}
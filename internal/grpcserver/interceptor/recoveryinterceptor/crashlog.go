@@ -0,0 +1,55 @@
+package recoveryinterceptor
+
+import (
+	"sync"
+	"time"
+)
+
+// Crash — одно перехваченное падение.
+type Crash struct {
+	Time      time.Time `json:"time"`
+	Method    string    `json:"method"`
+	RequestID string    `json:"request_id,omitempty"`
+	Panic     string    `json:"panic"`
+	Stack     string    `json:"stack"`
+}
+
+// CrashLog — кольцевой буфер последних падений: старые записи вытесняются новыми.
+type CrashLog struct {
+	mu      sync.Mutex
+	entries []Crash
+	next    int
+	full    bool
+}
+
+func NewCrashLog(size int) *CrashLog {
+	return &CrashLog{entries: make([]Crash, size)}
+}
+
+func (l *CrashLog) Add(c Crash) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.entries) == 0 {
+		return
+	}
+	l.entries[l.next] = c
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Recent — падения от новых к старым.
+func (l *CrashLog) Recent() []Crash {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := l.next
+	if l.full {
+		n = len(l.entries)
+	}
+	out := make([]Crash, 0, n)
+	for k := 1; k <= n; k++ {
+		out = append(out, l.entries[(l.next-k+len(l.entries))%len(l.entries)])
+	}
+	return out
+}
@@ -0,0 +1,104 @@
+package recoveryinterceptor
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/reqmeta"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/grpcerr"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/grpcutil"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+var panicsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "bottrade_grpc_server_panics_total",
+	Help: "Panics recovered in gRPC handlers by method.",
+}, []string{"method"})
+
+// RecoveryInterceptor перехватывает panic в обработчике и интерцепторах после него:
+// клиент получает Internal, в лог уходит стек с request id, процесс продолжает работу.
+// В цепочке два слоя: внутренний после logger и err, чтобы ошибка прошла через access log
+// и перевод, и внешний первым — для panic в самих интерцепторах.
+type RecoveryInterceptor struct {
+	crashes *CrashLog // nil — без журнала падений
+}
+
+type RecoveryInterceptorDeps struct {
+	// Crashes — кольцевой журнал последних падений для admin-эндпоинта; nil — выключен
+	Crashes *CrashLog
+}
+
+func NewRecoveryInterceptor(deps RecoveryInterceptorDeps) *RecoveryInterceptor {
+	return &RecoveryInterceptor{crashes: deps.Crashes}
+}
+
+func (i *RecoveryInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = i.recovered(ctx, info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+func (i *RecoveryInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = i.recovered(ss.Context(), info.FullMethod, r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+func (i *RecoveryInterceptor) recovered(ctx context.Context, method string, r any) error {
+	crash := Crash{
+		Time:      time.Now().UTC(),
+		Method:    method,
+		RequestID: requestID(ctx),
+		Panic:     fmt.Sprint(r),
+		Stack:     string(debug.Stack()),
+	}
+
+	panicsTotal.WithLabelValues(method).Inc()
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"method":     crash.Method,
+		"request_id": crash.RequestID,
+		"stack":      crash.Stack,
+	}).Errorf("grpc: panic recovered: %s", crash.Panic)
+	if i.crashes != nil {
+		i.crashes.Add(crash)
+	}
+
+	return grpcerr.New(codes.Internal, grpcerr.ReasonInternal, "internal error")
+}
+
+// requestID — из reqmeta; у stream-вызовов его никто не выставляет, берём из metadata.
+func requestID(ctx context.Context) string {
+	if id := reqmeta.From(ctx).RequestID; id != "" {
+		return id
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return grpcutil.GetMDString(md, "x-request-id")
+}
@@ -0,0 +1,116 @@
+package recoveryinterceptor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/reqmeta"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/grpcerr"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/interceptor/errinterceptor"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testMethod = "/bottrade.auth.v1.AuthService/Login"
+
+func TestPanicBecomesLocalizedInternal(t *testing.T) {
+	crashes := NewCrashLog(4)
+	recovery := NewRecoveryInterceptor(RecoveryInterceptorDeps{Crashes: crashes})
+	info := &grpc.UnaryServerInfo{FullMethod: testMethod}
+	panics := panicsTotal.WithLabelValues(testMethod)
+	before := testutil.ToFloat64(panics)
+
+	// та же связка, что в InitHandlers: err снаружи recovery
+	ctx := reqmeta.With(context.Background(), reqmeta.Meta{RequestID: "req-1", Locale: "ru"})
+	_, err := errinterceptor.NewErrInterceptor().Unary()(ctx, nil, info,
+		func(ctx context.Context, req any) (any, error) {
+			return recovery.Unary()(ctx, req, info, func(context.Context, any) (any, error) {
+				panic("boom")
+			})
+		})
+
+	st := status.Convert(err)
+	if st.Code() != codes.Internal || grpcerr.Reason(err) != grpcerr.ReasonInternal {
+		t.Fatalf("err = %v (reason %q), want Internal/%s", err, grpcerr.Reason(err), grpcerr.ReasonInternal)
+	}
+	if strings.Contains(st.Message(), "boom") {
+		t.Fatalf("panic value leaked to client: %q", st.Message())
+	}
+	var localized *errdetails.LocalizedMessage
+	for _, d := range st.Details() {
+		if m, ok := d.(*errdetails.LocalizedMessage); ok {
+			localized = m
+		}
+	}
+	if localized == nil || localized.GetLocale() != "ru" || localized.GetMessage() == "" {
+		t.Fatalf("LocalizedMessage = %v", localized)
+	}
+
+	recent := crashes.Recent()
+	if len(recent) != 1 {
+		t.Fatalf("crash log has %d entries, want 1", len(recent))
+	}
+	c := recent[0]
+	if c.Method != testMethod || c.RequestID != "req-1" || c.Panic != "boom" || !strings.Contains(c.Stack, "recovery_test.go") {
+		t.Fatalf("crash = %+v", c)
+	}
+	if got := testutil.ToFloat64(panics) - before; got != 1 {
+		t.Fatalf("panics_total delta = %v, want 1", got)
+	}
+}
+
+func TestOuterRecoveryCatchesInterceptorPanic(t *testing.T) {
+	crashes := NewCrashLog(4)
+	recovery := NewRecoveryInterceptor(RecoveryInterceptorDeps{Crashes: crashes})
+	info := &grpc.UnaryServerInfo{FullMethod: testMethod}
+
+	// как в InitHandlers: recovery первым, panic — в интерцепторе до внутреннего recovery
+	panicking := func(context.Context, any, *grpc.UnaryServerInfo, grpc.UnaryHandler) (any, error) {
+		panic("interceptor bug")
+	}
+	_, err := recovery.Unary()(context.Background(), nil, info,
+		func(ctx context.Context, req any) (any, error) {
+			return panicking(ctx, req, info, func(context.Context, any) (any, error) {
+				t.Fatal("handler reached")
+				return nil, nil
+			})
+		})
+
+	if status.Code(err) != codes.Internal || grpcerr.Reason(err) != grpcerr.ReasonInternal {
+		t.Fatalf("err = %v, want Internal/%s", err, grpcerr.ReasonInternal)
+	}
+	if recent := crashes.Recent(); len(recent) != 1 || recent[0].Panic != "interceptor bug" {
+		t.Fatalf("crash log = %+v", recent)
+	}
+}
+
+func TestNoPanicPassesThrough(t *testing.T) {
+	crashes := NewCrashLog(4)
+	recovery := NewRecoveryInterceptor(RecoveryInterceptorDeps{Crashes: crashes})
+
+	resp, err := recovery.Unary()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: testMethod},
+		func(context.Context, any) (any, error) { return "ok", nil })
+	if err != nil || resp != "ok" {
+		t.Fatalf("resp=%v err=%v", resp, err)
+	}
+	if len(crashes.Recent()) != 0 {
+		t.Fatal("crash recorded without panic")
+	}
+}
+
+func TestCrashLogRing(t *testing.T) {
+	l := NewCrashLog(2)
+	for _, p := range []string{"a", "b", "c"} {
+		l.Add(Crash{Panic: p})
+	}
+	recent := l.Recent()
+	if len(recent) != 2 || recent[0].Panic != "c" || recent[1].Panic != "b" {
+		t.Fatalf("Recent = %+v, want c, b", recent)
+	}
+
+	NewCrashLog(0).Add(Crash{Panic: "ignored"}) // нулевой размер не должен паниковать
+}
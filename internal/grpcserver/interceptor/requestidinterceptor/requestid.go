@@ -0,0 +1,66 @@
+package requestidinterceptor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/reqmeta"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/grpcutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	header = "x-request-id"
+	// maxLen — длиннее не принимаем: id попадает в логи и в аудит
+	maxLen = 64
+)
+
+// RequestIDInterceptor берёт request id из x-request-id клиента или выдаёт новый,
+// кладёт его в reqmeta и возвращает клиенту в заголовке ответа x-request-id.
+type RequestIDInterceptor struct{}
+
+func NewRequestIDInterceptor() *RequestIDInterceptor {
+	return &RequestIDInterceptor{}
+}
+
+func (i *RequestIDInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		id := grpcutil.GetMDString(md, header)
+		if !valid(id) {
+			id = newRequestID()
+		}
+		// заголовки уходят и с ошибкой, так что id виден и в неудачных ответах
+		_ = grpc.SetHeader(ctx, metadata.Pairs(header, id))
+		return handler(reqmeta.WithRequestID(ctx, id), req)
+	}
+}
+
+// valid пропускает только печатные id без пробелов (uuid, hex, ulid и подобные).
+func valid(id string) bool {
+	if id == "" || len(id) > maxLen {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // crypto/rand.Read не возвращает ошибок
+	return hex.EncodeToString(b)
+}
@@ -12,6 +12,20 @@ type AuthUsecase interface {
 	Register(ctx context.Context, email, password string) (models.AuthTokens, error)
 	Login(ctx context.Context, email, password string) (models.AuthTokens, error)
 
+	// Web-сессия (HttpOnly cookie): вход с refresh token, его ротация и выход
+	WebLogin(ctx context.Context, email, password string) (models.AuthTokens, error)
+	RefreshWebSession(ctx context.Context, refreshToken string) (models.AuthTokens, error)
+	EndWebSession(ctx context.Context, refreshToken string) error
+
+	// Web: смена пароля (JWT required, userID берём из ctx)
+	ChangePassword(ctx context.Context, userID string, oldPassword, newPassword string) (models.AuthTokens, error)
+
+	// Web: история входов (JWT required)
+	ListLoginHistory(ctx context.Context, userID string, beforeID int64, limit int32) ([]models.LoginRecord, error)
+
+	// Web: слияние второго аккаунта пользователя в текущий (JWT required)
+	MergeAccounts(ctx context.Context, userID string, proof models.MergeProof) (models.MergeResult, error)
+
 	// Web: код для привязки Telegram (JWT required, userID берём из ctx)
 	CreateTelegramLinkCode(ctx context.Context, userID string, ttl time.Duration) (code string, expiresInSec int64, err error)
 
@@ -20,12 +34,40 @@ type AuthUsecase interface {
 
 	// Telegram: логин после привязки (bot-signature required)
 	TelegramAuth(ctx context.Context, tg models.TelegramProfile) (models.AuthTokens, error)
+
+	// Telegram: сообщения для пользователей, накопившиеся для бота (bot-signature required)
+	PullBotMessages(ctx context.Context, limit int32) ([]models.BotMessage, error)
+}
+
+// AdminUsecase — операции операторов над пользователями (роль admin).
+type AdminUsecase interface {
+	ListUsers(ctx context.Context, filter models.UserFilter) ([]models.User, error)
+	GetUser(ctx context.Context, userID int32) (models.UserDetails, error)
+	BlockUser(ctx context.Context, userID int32) error
+	UnblockUser(ctx context.Context, userID int32) error
+	ForceLogout(ctx context.Context, userID int32) (revoked int64, err error)
+	DeleteUser(ctx context.Context, userID int32) error
+	ResetMfa(ctx context.Context, userID int32) error
+
+	ListAuditEvents(ctx context.Context, filter models.AuditFilter) ([]models.AuditEvent, error)
+}
+
+// WebhookUsecase — подписки интеграций на доменные события (роль admin).
+type WebhookUsecase interface {
+	CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	ListWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error)
 }
 
 type TokenVerifier interface {
 	ValidateAccessToken(ctx context.Context, accessToken string) (userID string, err error)
 }
 
+type AdminVerifier interface {
+	RequireAdmin(ctx context.Context, userID string) error
+}
+
 type BotVerifier interface {
 	ValidateBotSignature(ctx context.Context, meta models.BotMeta, fullMethod string, reqBytes []byte) error
 }
@@ -0,0 +1,75 @@
+package redact
+
+import (
+	"github.com/IvanOplesnin/BotTradeService.git/gen/authv1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Placeholder — значение строковых sensitive полей в выводе; по нему видно,
+// что поле было заполнено.
+const Placeholder = "[REDACTED]"
+
+var marshalOpts = protojson.MarshalOptions{}
+
+// JSON — сообщение в JSON для логов: поля с опцией (bottrade.auth.v1.sensitive)
+// заменены на Placeholder (строки) или вырезаны (остальные типы), в том числе
+// во вложенных сообщениях, списках и map. Исходное сообщение не меняется.
+func JSON(m proto.Message) string {
+	if m == nil {
+		return "null"
+	}
+	clone := proto.Clone(m)
+	redactMessage(clone.ProtoReflect())
+	b, err := marshalOpts.Marshal(clone)
+	if err != nil {
+		return `"<unmarshalable ` + string(m.ProtoReflect().Descriptor().FullName()) + `>"`
+	}
+	return string(b)
+}
+
+func redactMessage(msg protoreflect.Message) {
+	// менять сообщение внутри Range нельзя: сначала собираем sensitive поля
+	var hidden []protoreflect.FieldDescriptor
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case sensitive(fd):
+			hidden = append(hidden, fd)
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					redactMessage(mv.Message())
+					return true
+				})
+			}
+		case fd.IsList():
+			if fd.Message() != nil {
+				list := v.List()
+				for i := 0; i < list.Len(); i++ {
+					redactMessage(list.Get(i).Message())
+				}
+			}
+		case fd.Message() != nil:
+			redactMessage(v.Message())
+		}
+		return true
+	})
+
+	for _, fd := range hidden {
+		if fd.Kind() == protoreflect.StringKind && fd.Cardinality() != protoreflect.Repeated {
+			msg.Set(fd, protoreflect.ValueOfString(Placeholder))
+		} else {
+			msg.Clear(fd)
+		}
+	}
+}
+
+func sensitive(fd protoreflect.FieldDescriptor) bool {
+	opts := fd.Options()
+	if opts == nil {
+		return false
+	}
+	on, _ := proto.GetExtension(opts, authv1.E_Sensitive).(bool)
+	return on
+}
@@ -0,0 +1,172 @@
+package redact
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/IvanOplesnin/BotTradeService.git/gen/authv1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// testFile — сообщения с sensitive полями во всех местах, которых нет в реальных proto:
+//
+//	message Secret { string token = 1 [sensitive]; string note = 2; int64 pin = 3 [sensitive];
+//	                 repeated string codes = 4 [sensitive]; }
+//	message Holder { Secret one = 1; repeated Secret many = 2; map<string, Secret> by_key = 3;
+//	                 map<string, string> headers = 4 [sensitive]; string name = 5; }
+func testFile(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+	sensitive := func() *descriptorpb.FieldOptions {
+		opts := &descriptorpb.FieldOptions{}
+		proto.SetExtension(opts, authv1.E_Sensitive, true)
+		return opts
+	}
+	field := func(name string, num int32, label descriptorpb.FieldDescriptorProto_Label,
+		typ descriptorpb.FieldDescriptorProto_Type, typeName string, opts *descriptorpb.FieldOptions) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(num),
+			Label:    label.Enum(),
+			Type:     typ.Enum(),
+			Options:  opts,
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	const (
+		optional = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+		repeated = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+		str      = descriptorpb.FieldDescriptorProto_TYPE_STRING
+		i64      = descriptorpb.FieldDescriptorProto_TYPE_INT64
+		msg      = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+	)
+	mapEntry := func(name, valueType string, value descriptorpb.FieldDescriptorProto_Type) *descriptorpb.DescriptorProto {
+		return &descriptorpb.DescriptorProto{
+			Name: proto.String(name),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("key", 1, optional, str, "", nil),
+				field("value", 2, optional, value, valueType, nil),
+			},
+			Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+		}
+	}
+
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("redact_test.proto"),
+		Package:    proto.String("bottrade.test.v1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{authv1.File_options_proto.Path()},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Secret"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("token", 1, optional, str, "", sensitive()),
+					field("note", 2, optional, str, "", nil),
+					field("pin", 3, optional, i64, "", sensitive()),
+					field("codes", 4, repeated, str, "", sensitive()),
+				},
+			},
+			{
+				Name: proto.String("Holder"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("one", 1, optional, msg, ".bottrade.test.v1.Secret", nil),
+					field("many", 2, repeated, msg, ".bottrade.test.v1.Secret", nil),
+					field("by_key", 3, repeated, msg, ".bottrade.test.v1.Holder.ByKeyEntry", nil),
+					field("headers", 4, repeated, msg, ".bottrade.test.v1.Holder.HeadersEntry", sensitive()),
+					field("name", 5, optional, str, "", nil),
+				},
+				NestedType: []*descriptorpb.DescriptorProto{
+					mapEntry("ByKeyEntry", ".bottrade.test.v1.Secret", msg),
+					mapEntry("HeadersEntry", "", str),
+				},
+			},
+		},
+	}
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("build test descriptor: %v", err)
+	}
+	return fd
+}
+
+func TestJSONRedactsNestedFields(t *testing.T) {
+	fd := testFile(t)
+	secretDesc, holderDesc := fd.Messages().ByName("Secret"), fd.Messages().ByName("Holder")
+
+	secret := func(token, note string) protoreflect.Message {
+		s := dynamicpb.NewMessage(secretDesc)
+		s.Set(secretDesc.Fields().ByName("token"), protoreflect.ValueOfString(token))
+		s.Set(secretDesc.Fields().ByName("note"), protoreflect.ValueOfString(note))
+		s.Set(secretDesc.Fields().ByName("pin"), protoreflect.ValueOfInt64(1234))
+		codes := s.Mutable(secretDesc.Fields().ByName("codes")).List()
+		codes.Append(protoreflect.ValueOfString("c1"))
+		codes.Append(protoreflect.ValueOfString("c2"))
+		return s
+	}
+	f := holderDesc.Fields()
+	h := dynamicpb.NewMessage(holderDesc)
+	h.Set(f.ByName("one"), protoreflect.ValueOfMessage(secret("t-one", "n-one")))
+	many := h.Mutable(f.ByName("many")).List()
+	many.Append(protoreflect.ValueOfMessage(secret("t-m0", "n-m0")))
+	many.Append(protoreflect.ValueOfMessage(secret("t-m1", "n-m1")))
+	h.Mutable(f.ByName("by_key")).Map().Set(protoreflect.ValueOfString("k").MapKey(), protoreflect.ValueOfMessage(secret("t-k", "n-k")))
+	h.Mutable(f.ByName("headers")).Map().Set(protoreflect.ValueOfString("authorization").MapKey(), protoreflect.ValueOfString("Bearer x"))
+	h.Set(f.ByName("name"), protoreflect.ValueOfString("holder"))
+	before := proto.Clone(h)
+
+	got := decode(t, JSON(h))
+
+	// строки — Placeholder, остальные sensitive поля вырезаны, обычные — как есть
+	redacted := func(note string) map[string]any { return map[string]any{"token": Placeholder, "note": note} }
+	want := map[string]any{
+		"one":    redacted("n-one"),
+		"many":   []any{redacted("n-m0"), redacted("n-m1")},
+		"by_key": map[string]any{"k": redacted("n-k")},
+		"name":   "holder",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("JSON = %v\nwant  %v", got, want)
+	}
+	if !proto.Equal(h, before) {
+		t.Fatal("source message was modified")
+	}
+}
+
+func TestJSONRealMessages(t *testing.T) {
+	req := &authv1.LoginRequest{Email: "ivan@example.com", Password: "hunter2", WebSession: true}
+	want := map[string]any{"email": "ivan@example.com", "password": Placeholder, "webSession": true}
+	if got := decode(t, JSON(req)); !reflect.DeepEqual(got, want) {
+		t.Fatalf("JSON = %v, want %v", got, want)
+	}
+	if req.GetPassword() != "hunter2" {
+		t.Fatal("source message was modified")
+	}
+
+	// пустое sensitive поле не выдаётся за заполненное
+	want = map[string]any{"email": "ivan@example.com"}
+	if got := decode(t, JSON(&authv1.LoginRequest{Email: "ivan@example.com"})); !reflect.DeepEqual(got, want) {
+		t.Fatalf("JSON = %v, want %v", got, want)
+	}
+	if got := JSON(nil); got != "null" {
+		t.Fatalf("JSON(nil) = %s", got)
+	}
+}
+
+// decode — JSON в map: protojson намеренно нестабилен в пробелах.
+func decode(t *testing.T, s string) map[string]any {
+	t.Helper()
+	var out map[string]any
+	if err := json.Unmarshal([]byte(s), &out); err != nil {
+		t.Fatalf("JSON %q is not valid: %v", s, err)
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+package servertls
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/grpcserver/grpcutil"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+)
+
+// NewCredentials — TLS для внешних соединений. In-process канал REST-шлюза (bufconn)
+// обслуживается тем же grpc.Server, но остаётся без TLS: он не покидает процесс.
+func NewCredentials(cfg *tls.Config) credentials.TransportCredentials {
+	return &serverCreds{
+		tls:   credentials.NewTLS(cfg),
+		plain: insecure.NewCredentials(),
+	}
+}
+
+type serverCreds struct {
+	tls   credentials.TransportCredentials
+	plain credentials.TransportCredentials
+}
+
+func (c *serverCreds) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	if conn.LocalAddr().Network() == grpcutil.GatewayNetwork {
+		return c.plain.ServerHandshake(conn)
+	}
+	return c.tls.ServerHandshake(conn)
+}
+
+func (c *serverCreds) ClientHandshake(context.Context, string, net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return nil, nil, errors.New("servertls: server-only credentials")
+}
+
+func (c *serverCreds) Info() credentials.ProtocolInfo {
+	return c.tls.Info()
+}
+
+func (c *serverCreds) Clone() credentials.TransportCredentials {
+	return &serverCreds{tls: c.tls.Clone(), plain: c.plain.Clone()}
+}
+
+// OverrideServerName устарел, но входит в интерфейс TransportCredentials.
+func (c *serverCreds) OverrideServerName(name string) error {
+	return c.tls.OverrideServerName(name)
+}
+
+// PeerIdentity — идентичность сервиса из проверенного клиентского сертификата (mTLS):
+// первый URI SAN (например, spiffe://...), иначе первый DNS SAN, иначе CN.
+func PeerIdentity(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return "", false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+
+	cert := info.State.VerifiedChains[0][0]
+	switch {
+	case len(cert.URIs) > 0:
+		return cert.URIs[0].String(), true
+	case len(cert.DNSNames) > 0:
+		return cert.DNSNames[0], true
+	case cert.Subject.CommonName != "":
+		return cert.Subject.CommonName, true
+	default:
+		return "", false
+	}
+}
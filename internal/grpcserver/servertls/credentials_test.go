@@ -0,0 +1,48 @@
+package servertls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"testing"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+func TestPeerIdentity(t *testing.T) {
+	verified := func(cert *x509.Certificate) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+		}})
+	}
+	spiffe := &url.URL{Scheme: "spiffe", Host: "bottrade", Path: "/notify"}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+		ok   bool
+	}{
+		{name: "uri san first", ctx: verified(&x509.Certificate{URIs: []*url.URL{spiffe}, DNSNames: []string{"notify"}, Subject: pkix.Name{CommonName: "cn"}}), want: "spiffe://bottrade/notify", ok: true},
+		{name: "dns san", ctx: verified(&x509.Certificate{DNSNames: []string{"notify"}, Subject: pkix.Name{CommonName: "cn"}}), want: "notify", ok: true},
+		{name: "common name", ctx: verified(&x509.Certificate{Subject: pkix.Name{CommonName: "cn"}}), want: "cn", ok: true},
+		{name: "empty certificate", ctx: verified(&x509.Certificate{})},
+		{name: "no peer", ctx: context.Background()},
+		// сертификат предъявлен, но не проверен (VerifyClientCertIfGiven без цепочки) — не идентичность
+		{name: "unverified certificate", ctx: peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "cn"}}}},
+		}})},
+		{name: "plaintext", ctx: peer.NewContext(context.Background(), &peer.Peer{})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := PeerIdentity(tt.ctx)
+			if got != tt.want || ok != tt.ok {
+				t.Fatalf("PeerIdentity = %q, %v; want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
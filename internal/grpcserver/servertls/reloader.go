@@ -0,0 +1,165 @@
+package servertls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/logger"
+)
+
+// defaultReloadInterval — как часто при handshake проверять, не сменились ли файлы.
+const defaultReloadInterval = 30 * time.Second
+
+// Reloader отдаёт TLS-конфиг с сертификатом и CA клиентов из файлов и перечитывает их,
+// когда у файлов меняется mtime или размер: ротация сертификата не требует рестарта.
+// Если новые файлы не читаются (например, cert уже заменён, а key ещё нет), остаётся
+// прежний конфиг, а попытка повторится на следующей проверке.
+type Reloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+	clientAuth   tls.ClientAuthType
+	interval     time.Duration
+
+	mu      sync.Mutex
+	current *tls.Config
+	stamps  []fileStamp
+	checked time.Time
+}
+
+type ReloaderDeps struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile — CA клиентских сертификатов; пусто — mTLS выключен
+	ClientCAFile      string
+	RequireClientCert bool
+	ReloadInterval    time.Duration
+}
+
+func NewReloader(deps ReloaderDeps) (*Reloader, error) {
+	r := &Reloader{
+		certFile:     deps.CertFile,
+		keyFile:      deps.KeyFile,
+		clientCAFile: deps.ClientCAFile,
+		clientAuth:   tls.NoClientCert,
+		interval:     deps.ReloadInterval,
+	}
+	if r.interval <= 0 {
+		r.interval = defaultReloadInterval
+	}
+	if r.clientCAFile != "" {
+		r.clientAuth = tls.VerifyClientCertIfGiven
+		if deps.RequireClientCert {
+			r.clientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	r.checked = time.Now()
+	return r, nil
+}
+
+// TLSConfig — серверный конфиг; актуальный сертификат выбирается на каждом handshake.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.config(), nil
+		},
+	}
+}
+
+func (r *Reloader) config() *tls.Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now := time.Now(); now.Sub(r.checked) >= r.interval {
+		r.checked = now
+		if r.changed() {
+			if err := r.load(); err != nil {
+				logger.Log.Errorf("tls: reload certificates: %s", err)
+			} else {
+				logger.Log.Infof("tls: certificates reloaded from %s", r.certFile)
+			}
+		}
+	}
+	return r.current
+}
+
+func (r *Reloader) files() []string {
+	files := []string{r.certFile, r.keyFile}
+	if r.clientCAFile != "" {
+		files = append(files, r.clientCAFile)
+	}
+	return files
+}
+
+// load читает файлы и подменяет конфиг; вызывается под mu (или до публикации r).
+func (r *Reloader) load() error {
+	stamps, err := statFiles(r.files())
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load key pair: %w", err)
+	}
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   r.clientAuth,
+	}
+	if r.clientCAFile != "" {
+		pem, err := os.ReadFile(r.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("read client ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("client ca %s: no PEM certificates", r.clientCAFile)
+		}
+		cfg.ClientCAs = pool
+	}
+
+	r.current = cfg
+	r.stamps = stamps
+	return nil
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func (r *Reloader) changed() bool {
+	stamps, err := statFiles(r.files())
+	if err != nil {
+		// файл временно пропал при замене — ждём следующей проверки
+		return false
+	}
+	for i := range stamps {
+		if stamps[i] != r.stamps[i] {
+			return true
+		}
+	}
+	return false
+}
+
+func statFiles(files []string) ([]fileStamp, error) {
+	stamps := make([]fileStamp, 0, len(files))
+	for _, f := range files {
+		fi, err := os.Stat(f)
+		if err != nil {
+			return nil, fmt.Errorf("stat %s: %w", f, err)
+		}
+		stamps = append(stamps, fileStamp{modTime: fi.ModTime(), size: fi.Size()})
+	}
+	return stamps, nil
+}
@@ -0,0 +1,164 @@
+package servertls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// mtimeTick — сдвиг mtime каждой записи, чтобы Reloader гарантированно увидел изменение.
+var mtimeTick atomic.Int64
+
+// writeCert пишет самоподписанный сертификат с заданным serial и его ключ.
+func writeCert(t *testing.T, certFile, keyFile string, serial int64) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "bottrade-auth"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+}
+
+func writePEM(t *testing.T, path, typ string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// mtime в будущем: на быстрой файловой системе перезапись может не сдвинуть его
+	stamp := time.Now().Add(time.Duration(mtimeTick.Add(1)) * time.Second)
+	if err := os.Chtimes(path, stamp, stamp); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func servedSerial(t *testing.T, r *Reloader) int64 {
+	t.Helper()
+	cfg, err := r.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cfg.Certificates[0].Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.SerialNumber.Int64()
+}
+
+func TestReloaderHotReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	writeCert(t, certFile, keyFile, 1)
+
+	r, err := NewReloader(ReloaderDeps{CertFile: certFile, KeyFile: keyFile, ReloadInterval: time.Nanosecond})
+	if err != nil {
+		t.Fatalf("NewReloader: %v", err)
+	}
+	if got := servedSerial(t, r); got != 1 {
+		t.Fatalf("serial = %d, want 1", got)
+	}
+
+	// ротация: новый сертификат подхватывается без рестарта
+	writeCert(t, certFile, keyFile, 2)
+	if got := servedSerial(t, r); got != 2 {
+		t.Fatalf("serial after rotation = %d, want 2", got)
+	}
+
+	// cert заменён, key ещё старый: пара не сходится, остаётся прежний конфиг
+	otherCert, otherKey := filepath.Join(dir, "other.crt"), filepath.Join(dir, "other.key")
+	writeCert(t, otherCert, otherKey, 3)
+	raw, err := os.ReadFile(otherCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, certFile, "CERTIFICATE", pemBody(t, raw))
+	if got := servedSerial(t, r); got != 2 {
+		t.Fatalf("serial with mismatched key = %d, want 2", got)
+	}
+
+	// key догнал cert — перечитывается на следующей проверке
+	raw, err = os.ReadFile(otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, keyFile, "EC PRIVATE KEY", pemBody(t, raw))
+	if got := servedSerial(t, r); got != 3 {
+		t.Fatalf("serial after key update = %d, want 3", got)
+	}
+}
+
+func TestReloaderClientAuth(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	writeCert(t, certFile, keyFile, 1)
+
+	tests := []struct {
+		name    string
+		deps    ReloaderDeps
+		want    tls.ClientAuthType
+		wantErr bool
+	}{
+		{name: "no client ca", deps: ReloaderDeps{}, want: tls.NoClientCert},
+		{name: "optional mtls", deps: ReloaderDeps{ClientCAFile: certFile}, want: tls.VerifyClientCertIfGiven},
+		{name: "required mtls", deps: ReloaderDeps{ClientCAFile: certFile, RequireClientCert: true}, want: tls.RequireAndVerifyClientCert},
+		{name: "ca without pem", deps: ReloaderDeps{ClientCAFile: keyFile}, wantErr: true},
+		{name: "missing ca", deps: ReloaderDeps{ClientCAFile: filepath.Join(dir, "none.crt")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := tt.deps
+			deps.CertFile, deps.KeyFile = certFile, keyFile
+			r, err := NewReloader(deps)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("NewReloader succeeded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewReloader: %v", err)
+			}
+			cfg, _ := r.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+			if cfg.ClientAuth != tt.want || (tt.want != tls.NoClientCert) != (cfg.ClientCAs != nil) {
+				t.Fatalf("ClientAuth = %v, ClientCAs set = %v", cfg.ClientAuth, cfg.ClientCAs != nil)
+			}
+		})
+	}
+}
+
+func pemBody(t *testing.T, raw []byte) []byte {
+	t.Helper()
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		t.Fatal("no PEM block")
+	}
+	return block.Bytes
+}
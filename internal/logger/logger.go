@@ -1,11 +1,13 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/IvanOplesnin/BotTradeService.git/internal/config"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/reqmeta"
 	"github.com/sirupsen/logrus"
 )
 
@@ -32,6 +34,26 @@ func SetupLogger(cfg *config.Logger) error {
 	return nil
 }
 
+// FromContext — запись лога с полями запроса из ctx: request id, метод, пользователь и бот.
+// Вне запроса (фоновые воркеры) поля просто пустые, это тот же Log.
+func FromContext(ctx context.Context) *logrus.Entry {
+	m := reqmeta.From(ctx)
+	fields := logrus.Fields{}
+	if m.RequestID != "" {
+		fields["request_id"] = m.RequestID
+	}
+	if m.Method != "" {
+		fields["method"] = m.Method
+	}
+	if m.UserID != 0 {
+		fields["user_id"] = m.UserID
+	}
+	if m.BotID != "" {
+		fields["bot_id"] = m.BotID
+	}
+	return Log.WithContext(ctx).WithFields(fields)
+}
+
 func getFormatter(format string) (logrus.Formatter, error) {
 	form := strings.ToLower(string(format))
 
@@ -0,0 +1,105 @@
+package inmemory
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+func (r *Repo) ListUsers(_ context.Context, filter models.UserFilter) ([]models.User, error) {
+	defer r.lock()()
+
+	query := strings.ToLower(filter.EmailQuery)
+	users := make([]models.User, 0, filter.Limit)
+	for _, id := range sortedKeys(r.st.users) {
+		if len(users) == int(filter.Limit) {
+			break
+		}
+		u := r.st.users[id]
+		if u.ID <= filter.AfterID {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(u.Email), query) {
+			continue
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+func (r *Repo) BlockUser(_ context.Context, userID int32) error {
+	defer r.lock()()
+
+	u, ok := r.st.users[userID]
+	if !ok {
+		return modelerrors.ErrNoRows
+	}
+	if u.BlockedAt.IsZero() {
+		u.BlockedAt = time.Now()
+	}
+	r.st.users[userID] = u
+	return nil
+}
+
+func (r *Repo) UnblockUser(_ context.Context, userID int32) error {
+	defer r.lock()()
+
+	u, ok := r.st.users[userID]
+	if !ok {
+		return modelerrors.ErrNoRows
+	}
+	u.BlockedAt = time.Time{}
+	r.st.users[userID] = u
+	return nil
+}
+
+func (r *Repo) ResetUserMfa(_ context.Context, userID int32) error {
+	defer r.lock()()
+
+	if _, ok := r.st.users[userID]; !ok {
+		return modelerrors.ErrNoRows
+	}
+	delete(r.st.mfaSecrets, userID)
+	return nil
+}
+
+// DeleteUser повторяет ON DELETE CASCADE из схемы: identities, коды, сессии, история входов.
+func (r *Repo) DeleteUser(_ context.Context, userID int32) error {
+	defer r.lock()()
+
+	u, ok := r.st.users[userID]
+	if !ok {
+		return modelerrors.ErrNoRows
+	}
+	delete(r.st.users, userID)
+	delete(r.st.usersByEmail, u.EmailNormalized)
+	delete(r.st.mfaSecrets, userID)
+
+	for id, identity := range r.st.identities {
+		if identity.UserID == userID {
+			delete(r.st.identities, id)
+			delete(r.st.identityKeys, identityKey{identity.Provider, identity.ProviderUserID})
+		}
+	}
+	for code, lc := range r.st.linkCodes {
+		if lc.UserID == userID {
+			delete(r.st.linkCodes, code)
+		}
+	}
+	for id, s := range r.st.sessions {
+		if s.UserID == userID {
+			delete(r.st.sessions, id)
+		}
+	}
+	logins := r.st.logins[:0:0]
+	for _, l := range r.st.logins {
+		if l.UserID != userID {
+			logins = append(logins, l)
+		}
+	}
+	r.st.logins = logins
+	return nil
+}
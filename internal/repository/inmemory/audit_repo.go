@@ -0,0 +1,45 @@
+package inmemory
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+func (r *Repo) RecordAuditEvent(_ context.Context, event models.AuditEvent) error {
+	defer r.lock()()
+
+	r.st.lastAuditID++
+	event.ID = r.st.lastAuditID
+	event.OccurredAt = time.Now()
+	event.Details = maps.Clone(event.Details)
+	if event.Details == nil {
+		event.Details = map[string]string{}
+	}
+	r.st.audit = append(r.st.audit, event)
+	return nil
+}
+
+func (r *Repo) ListAuditEvents(_ context.Context, filter models.AuditFilter) ([]models.AuditEvent, error) {
+	defer r.lock()()
+
+	events := make([]models.AuditEvent, 0, filter.Limit)
+	for i := len(r.st.audit) - 1; i >= 0 && len(events) < int(filter.Limit); i-- {
+		e := r.st.audit[i]
+		switch {
+		case filter.UserID != 0 && e.ActorUserID != filter.UserID && e.TargetUserID != filter.UserID:
+			continue
+		case !filter.From.IsZero() && e.OccurredAt.Before(filter.From):
+			continue
+		case !filter.To.IsZero() && !e.OccurredAt.Before(filter.To):
+			continue
+		case filter.BeforeID != 0 && e.ID >= filter.BeforeID:
+			continue
+		}
+		e.Details = maps.Clone(e.Details)
+		events = append(events, e)
+	}
+	return events, nil
+}
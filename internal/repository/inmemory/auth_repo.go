@@ -0,0 +1,60 @@
+package inmemory
+
+import (
+	"context"
+	"time"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+func (r *Repo) CreateUser(_ context.Context, user models.User) (int32, error) {
+	defer r.lock()()
+
+	if _, ok := r.st.usersByEmail[user.EmailNormalized]; ok {
+		return 0, modelerrors.ErrEmailTaken
+	}
+	r.st.lastUserID++
+	user.ID = r.st.lastUserID
+	if user.Role == "" {
+		user.Role = models.RoleUser
+	}
+	user.BlockedAt = time.Time{}
+	user.CreatedAt = time.Now()
+
+	r.st.users[user.ID] = user
+	r.st.usersByEmail[user.EmailNormalized] = user.ID
+	return user.ID, nil
+}
+
+func (r *Repo) GetByEmail(_ context.Context, normalizedEmail string) (models.User, error) {
+	defer r.lock()()
+
+	id, ok := r.st.usersByEmail[normalizedEmail]
+	if !ok {
+		return models.User{}, modelerrors.ErrNoRows
+	}
+	return r.st.users[id], nil
+}
+
+func (r *Repo) GetUserByID(_ context.Context, userID int32) (models.User, error) {
+	defer r.lock()()
+
+	u, ok := r.st.users[userID]
+	if !ok {
+		return models.User{}, modelerrors.ErrNoRows
+	}
+	return u, nil
+}
+
+func (r *Repo) UpdatePassword(_ context.Context, userID int32, hashPassword string) error {
+	defer r.lock()()
+
+	u, ok := r.st.users[userID]
+	if !ok {
+		return modelerrors.ErrNoRows
+	}
+	u.HashPassword = hashPassword
+	r.st.users[userID] = u
+	return nil
+}
@@ -0,0 +1,41 @@
+package inmemory
+
+import (
+	"context"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+func (r *Repo) EnqueueBotMessage(_ context.Context, chatID int64, text string) error {
+	defer r.lock()()
+
+	r.st.lastBotMsgID++
+	r.st.botMessages = append(r.st.botMessages, botMessageRow{
+		BotMessage: models.BotMessage{
+			ID:        r.st.lastBotMsgID,
+			ChatID:    chatID,
+			Text:      text,
+			CreatedAt: time.Now(),
+		},
+	})
+	return nil
+}
+
+// ClaimBotMessages отдаёт до limit недоставленных сообщений и сразу помечает их доставленными.
+func (r *Repo) ClaimBotMessages(_ context.Context, limit int32) ([]models.BotMessage, error) {
+	defer r.lock()()
+
+	messages := make([]models.BotMessage, 0, limit)
+	for i := range r.st.botMessages {
+		if len(messages) == int(limit) {
+			break
+		}
+		if m := &r.st.botMessages[i]; !m.delivered {
+			m.delivered = true
+			m.deliveredAt = time.Now()
+			messages = append(messages, m.BotMessage)
+		}
+	}
+	return messages, nil
+}
@@ -0,0 +1,19 @@
+package inmemory
+
+import (
+	"context"
+	"time"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+)
+
+func (r *Repo) UseBotNonce(_ context.Context, botID, nonce string, expiresAt time.Time) error {
+	defer r.lock()()
+
+	key := botNonceKey{botID: botID, nonce: nonce}
+	if _, ok := r.st.botNonces[key]; ok {
+		return modelerrors.ErrReplay
+	}
+	r.st.botNonces[key] = expiresAt
+	return nil
+}
@@ -0,0 +1,11 @@
+package inmemory
+
+import (
+	"testing"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/repotest"
+)
+
+func TestContract(t *testing.T) {
+	repotest.Run(t, func(*testing.T) *Repo { return New() })
+}
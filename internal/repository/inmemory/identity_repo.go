@@ -0,0 +1,82 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+func (r *Repo) GetIdentity(_ context.Context, provider, providerUserID string) (models.Identity, error) {
+	defer r.lock()()
+
+	id, ok := r.st.identityKeys[identityKey{provider, providerUserID}]
+	if !ok {
+		return models.Identity{}, modelerrors.ErrNoRows
+	}
+	return r.st.identities[id], nil
+}
+
+func (r *Repo) ListIdentitiesByUser(_ context.Context, userID int32) ([]models.Identity, error) {
+	defer r.lock()()
+
+	var identities []models.Identity
+	for _, id := range sortedKeys(r.st.identities) {
+		if identity := r.st.identities[id]; identity.UserID == userID {
+			identities = append(identities, identity)
+		}
+	}
+	return slices.Clip(identities), nil
+}
+
+func (r *Repo) CreateIdentity(_ context.Context, identity models.Identity) (int64, error) {
+	defer r.lock()()
+
+	key := identityKey{identity.Provider, identity.ProviderUserID}
+	if _, ok := r.st.identityKeys[key]; ok {
+		return 0, modelerrors.ErrTelegramAlreadyLinked
+	}
+	if _, ok := r.st.users[identity.UserID]; !ok {
+		return 0, fmt.Errorf("inmemory: identity references missing user %d", identity.UserID)
+	}
+
+	r.st.lastIdentityID++
+	identity.ID = r.st.lastIdentityID
+	identity.CreatedAt = time.Now()
+
+	r.st.identities[identity.ID] = identity
+	r.st.identityKeys[key] = identity.ID
+	return identity.ID, nil
+}
+
+func (r *Repo) MoveIdentities(_ context.Context, fromUserID, toUserID int32) (int64, error) {
+	defer r.lock()()
+
+	var n int64
+	for id, identity := range r.st.identities {
+		if identity.UserID == fromUserID {
+			identity.UserID = toUserID
+			r.st.identities[id] = identity
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *Repo) UpdateIdentityProfile(_ context.Context, identity models.Identity) error {
+	defer r.lock()()
+
+	stored, ok := r.st.identities[identity.ID]
+	if !ok {
+		return nil
+	}
+	stored.Username = identity.Username
+	stored.FirstName = identity.FirstName
+	stored.LastName = identity.LastName
+	stored.ChatID = identity.ChatID
+	r.st.identities[identity.ID] = stored
+	return nil
+}
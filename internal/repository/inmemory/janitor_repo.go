@@ -0,0 +1,127 @@
+package inmemory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+// TryLock — аналог advisory lock в пределах процесса: реплика одна, мешать может только
+// предыдущий ещё не закончившийся запуск того же job-а.
+func (r *Repo) TryLock(_ context.Context, name string) (unlock func(), acquired bool, err error) {
+	defer r.lock()()
+
+	if r.st.locks[name] {
+		return nil, false, nil
+	}
+	r.st.locks[name] = true
+	return func() {
+		defer r.lock()()
+		delete(r.st.locks, name)
+	}, true, nil
+}
+
+// ClaimRun — как scheduler_job_runs в psql, по часам процесса.
+func (r *Repo) ClaimRun(_ context.Context, name string, minGap time.Duration) (claimed bool, err error) {
+	defer r.lock()()
+
+	now := time.Now()
+	if last, ok := r.st.jobRuns[name]; ok && now.Sub(last) < minGap {
+		return false, nil
+	}
+	r.st.jobRuns[name] = now
+	return true, nil
+}
+
+func (r *Repo) PurgeExpiredLinkCodes(_ context.Context, before time.Time, batch int32) (int64, error) {
+	defer r.lock()()
+
+	var n int64
+	for code, lc := range r.st.linkCodes {
+		if n == int64(batch) {
+			break
+		}
+		if lc.ExpiresAt.Before(before) {
+			delete(r.st.linkCodes, code)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *Repo) PurgeExpiredSessions(_ context.Context, before time.Time, batch int32) (int64, error) {
+	defer r.lock()()
+
+	var n int64
+	for id, s := range r.st.sessions {
+		if n == int64(batch) {
+			break
+		}
+		if s.ExpiresAt.Before(before) {
+			delete(r.st.sessions, id)
+			delete(r.st.prevRefreshHashes, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *Repo) PurgePublishedOutbox(_ context.Context, before time.Time, batch int32) (int64, error) {
+	defer r.lock()()
+
+	var n int64
+	r.st.outbox = slices.DeleteFunc(r.st.outbox, func(o outboxRow) bool {
+		if n < int64(batch) && o.published && o.publishedAt < before.UnixNano() {
+			n++
+			return true
+		}
+		return false
+	})
+	return n, nil
+}
+
+func (r *Repo) PurgeDeliveredBotMessages(_ context.Context, before time.Time, batch int32) (int64, error) {
+	defer r.lock()()
+
+	var n int64
+	r.st.botMessages = slices.DeleteFunc(r.st.botMessages, func(m botMessageRow) bool {
+		if n < int64(batch) && m.delivered && m.deliveredAt.Before(before) {
+			n++
+			return true
+		}
+		return false
+	})
+	return n, nil
+}
+
+func (r *Repo) PurgeDeliveredWebhookDeliveries(_ context.Context, before time.Time, batch int32) (int64, error) {
+	defer r.lock()()
+
+	var n int64
+	r.st.deliveries = slices.DeleteFunc(r.st.deliveries, func(d deliveryRow) bool {
+		if n < int64(batch) && d.Status == models.WebhookDeliveryDelivered && d.DeliveredAt.Before(before) {
+			n++
+			return true
+		}
+		return false
+	})
+	return n, nil
+}
+
+func (r *Repo) PurgeExpiredBotNonces(_ context.Context, before time.Time, batch int32) (int64, error) {
+	defer r.lock()()
+
+	var n int64
+	for key, expiresAt := range r.st.botNonces {
+		if n == int64(batch) {
+			break
+		}
+		if expiresAt.Before(before) {
+			delete(r.st.botNonces, key)
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,49 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+func (r *Repo) CreateLinkCode(_ context.Context, code models.LinkCode) error {
+	defer r.lock()()
+
+	if _, ok := r.st.linkCodes[code.Code]; ok {
+		return fmt.Errorf("inmemory: link code %q already exists", code.Code)
+	}
+	if _, ok := r.st.users[code.UserID]; !ok {
+		return fmt.Errorf("inmemory: link code references missing user %d", code.UserID)
+	}
+	code.UsedAt = time.Time{}
+	r.st.linkCodes[code.Code] = code
+	return nil
+}
+
+func (r *Repo) GetLinkCode(_ context.Context, code string) (models.LinkCode, error) {
+	defer r.lock()()
+
+	lc, ok := r.st.linkCodes[code]
+	if !ok {
+		return models.LinkCode{}, modelerrors.ErrNoRows
+	}
+	return lc, nil
+}
+
+// UseLinkCode помечает код использованным.
+// ErrNoRows — кода нет, он истёк или уже использован.
+func (r *Repo) UseLinkCode(_ context.Context, code string) (int32, error) {
+	defer r.lock()()
+
+	lc, ok := r.st.linkCodes[code]
+	now := time.Now()
+	if !ok || !lc.UsedAt.IsZero() || !lc.ExpiresAt.After(now) {
+		return 0, modelerrors.ErrNoRows
+	}
+	lc.UsedAt = now
+	r.st.linkCodes[code] = lc
+	return lc.UserID, nil
+}
@@ -0,0 +1,65 @@
+package inmemory
+
+import (
+	"context"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+func (r *Repo) RecordLogin(_ context.Context, record models.LoginRecord) error {
+	defer r.lock()()
+
+	r.st.lastLoginID++
+	record.ID = r.st.lastLoginID
+	record.CreatedAt = time.Now()
+	r.st.logins = append(r.st.logins, record)
+	return nil
+}
+
+func (r *Repo) GetLoginSeen(_ context.Context, userID int32, deviceFingerprint, ip string) (models.LoginSeen, error) {
+	defer r.lock()()
+
+	var seen models.LoginSeen
+	for _, l := range r.st.logins {
+		if l.UserID != userID {
+			continue
+		}
+		seen.HasHistory = true
+		if l.DeviceFingerprint == deviceFingerprint {
+			seen.DeviceSeen = true
+		}
+		// пустой IP хранится как NULL и ни с чем не совпадает
+		if ip != "" && l.IP == ip {
+			seen.IPSeen = true
+		}
+	}
+	return seen, nil
+}
+
+func (r *Repo) ListLoginHistory(_ context.Context, userID int32, beforeID int64, limit int32) ([]models.LoginRecord, error) {
+	defer r.lock()()
+
+	records := make([]models.LoginRecord, 0, limit)
+	for i := len(r.st.logins) - 1; i >= 0 && len(records) < int(limit); i-- {
+		l := r.st.logins[i]
+		if l.UserID != userID || (beforeID != 0 && l.ID >= beforeID) {
+			continue
+		}
+		records = append(records, l)
+	}
+	return records, nil
+}
+
+func (r *Repo) MoveLoginHistory(_ context.Context, fromUserID, toUserID int32) (int64, error) {
+	defer r.lock()()
+
+	var n int64
+	for i := range r.st.logins {
+		if r.st.logins[i].UserID == fromUserID {
+			r.st.logins[i].UserID = toUserID
+			n++
+		}
+	}
+	return n, nil
+}
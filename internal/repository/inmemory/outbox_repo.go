@@ -0,0 +1,100 @@
+package inmemory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+func (r *Repo) AddOutboxEvent(_ context.Context, eventType string, event models.UserEvent) error {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal %s event: %w", eventType, err)
+	}
+
+	defer r.lock()()
+
+	r.st.lastOutboxID++
+	now := time.Now()
+	r.st.outbox = append(r.st.outbox, outboxRow{
+		OutboxEvent: models.OutboxEvent{
+			ID:          r.st.lastOutboxID,
+			Type:        eventType,
+			AggregateID: strconv.FormatInt(int64(event.UserID), 10),
+			Payload:     raw,
+			CreatedAt:   now,
+		},
+		nextAttemptAt: now.UnixNano(),
+	})
+	return nil
+}
+
+func (r *Repo) ClaimOutboxEvents(_ context.Context, limit int32, lease time.Duration) ([]models.OutboxEvent, error) {
+	defer r.lock()()
+
+	now := time.Now().UnixNano()
+	events := make([]models.OutboxEvent, 0, limit)
+	for i := range r.st.outbox {
+		if len(events) == int(limit) {
+			break
+		}
+		o := &r.st.outbox[i]
+		if o.published || o.dead || o.nextAttemptAt > now || o.lockedUntil >= now {
+			continue
+		}
+		o.lockedUntil = now + int64(lease)
+		o.Attempts++
+		events = append(events, o.OutboxEvent)
+	}
+	return events, nil
+}
+
+func (r *Repo) MarkOutboxPublished(_ context.Context, id int64) error {
+	defer r.lock()()
+
+	if o := r.outboxRow(id); o != nil {
+		o.published = true
+		o.publishedAt = time.Now().UnixNano()
+		o.lockedUntil = 0
+		o.lastError = ""
+	}
+	return nil
+}
+
+func (r *Repo) MarkOutboxFailed(_ context.Context, id int64, lastErr string, nextAttemptAt time.Time) error {
+	defer r.lock()()
+
+	if o := r.outboxRow(id); o != nil {
+		o.lockedUntil = 0
+		o.lastError = lastErr
+		o.nextAttemptAt = nextAttemptAt.UnixNano()
+	}
+	return nil
+}
+
+func (r *Repo) MarkOutboxDead(_ context.Context, id int64, lastErr string) error {
+	defer r.lock()()
+
+	if o := r.outboxRow(id); o != nil {
+		o.dead = true
+		o.lockedUntil = 0
+		o.lastError = lastErr
+	}
+	return nil
+}
+
+func (r *Repo) outboxRow(id int64) *outboxRow {
+	for i := range r.st.outbox {
+		if r.st.outbox[i].ID == id {
+			return &r.st.outbox[i]
+		}
+	}
+	return nil
+}
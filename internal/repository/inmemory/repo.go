@@ -0,0 +1,164 @@
+package inmemory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+// Repo — хранилище в памяти с той же семантикой, что и psql.Repo: ErrNoRows,
+// ErrEmailTaken, ErrTelegramAlreadyLinked, каскадное удаление по пользователю.
+// Для тестов и локального запуска без Postgres; данные живут до рестарта.
+type Repo struct {
+	mu *sync.Mutex
+	st *state
+
+	inTx bool // внутри WithTx мьютекс уже взят
+}
+
+func New() *Repo {
+	return &Repo{
+		mu: &sync.Mutex{},
+		st: newState(),
+	}
+}
+
+// WithTx выполняет fn над копией состояния и подменяет состояние только при успехе,
+// поэтому ошибка в fn откатывает все изменения. Транзакции выполняются по одной.
+func (r *Repo) WithTx(_ context.Context, fn func(tx *Repo) error) error {
+	if r.inTx {
+		return fn(r)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	work := r.st.clone()
+	if err := fn(&Repo{mu: r.mu, st: work, inTx: true}); err != nil {
+		return err
+	}
+	*r.st = *work
+	return nil
+}
+
+func (r *Repo) lock() func() {
+	if r.inTx {
+		return func() {}
+	}
+	r.mu.Lock()
+	return r.mu.Unlock
+}
+
+type botMessageRow struct {
+	models.BotMessage
+	delivered   bool
+	deliveredAt time.Time
+}
+
+type outboxRow struct {
+	models.OutboxEvent
+	nextAttemptAt int64 // unix nano
+	lockedUntil   int64
+	lastError     string
+	published     bool
+	publishedAt   int64
+	dead          bool
+}
+
+type deliveryRow struct {
+	models.WebhookDelivery
+	lockedUntil int64 // unix nano
+}
+
+type state struct {
+	users        map[int32]models.User
+	usersByEmail map[string]int32 // по EmailNormalized
+	mfaSecrets   map[int32]string
+
+	identities   map[int64]models.Identity
+	identityKeys map[identityKey]int64
+	linkCodes    map[string]models.LinkCode
+	sessions     map[string]models.Session
+
+	prevRefreshHashes map[string][]byte // session id → prev_refresh_token_hash
+	botNonces         map[botNonceKey]time.Time
+
+	logins      []models.LoginRecord
+	botMessages []botMessageRow
+	audit       []models.AuditEvent
+	outbox      []outboxRow
+	webhooks    map[int64]models.Webhook
+	deliveries  []deliveryRow
+
+	locks   map[string]bool      // TryLock; в clone не копируется — lock-и не транзакционны
+	jobRuns map[string]time.Time // ClaimRun; как и locks, не транзакционны
+
+	lastUserID     int32
+	lastIdentityID int64
+	lastLoginID    int64
+	lastBotMsgID   int64
+	lastAuditID    int64
+	lastOutboxID   int64
+	lastWebhookID  int64
+	lastDeliveryID int64
+}
+
+// botNonceKey — первичный ключ bot_nonces.
+type botNonceKey struct {
+	botID string
+	nonce string
+}
+
+// identityKey — аналог uq_user_identities_provider.
+type identityKey struct {
+	provider       string
+	providerUserID string
+}
+
+func newState() *state {
+	return &state{
+		users:        map[int32]models.User{},
+		usersByEmail: map[string]int32{},
+		mfaSecrets:   map[int32]string{},
+		identities:   map[int64]models.Identity{},
+		identityKeys: map[identityKey]int64{},
+		linkCodes:    map[string]models.LinkCode{},
+		sessions:     map[string]models.Session{},
+		webhooks:     map[int64]models.Webhook{},
+		locks:        map[string]bool{},
+		jobRuns:      map[string]time.Time{},
+
+		prevRefreshHashes: map[string][]byte{},
+		botNonces:         map[botNonceKey]time.Time{},
+	}
+}
+
+// clone копирует контейнеры; сами записи не изменяются на месте, только заменяются.
+func (s *state) clone() *state {
+	c := *s
+	c.users = maps.Clone(s.users)
+	c.usersByEmail = maps.Clone(s.usersByEmail)
+	c.mfaSecrets = maps.Clone(s.mfaSecrets)
+	c.identities = maps.Clone(s.identities)
+	c.identityKeys = maps.Clone(s.identityKeys)
+	c.linkCodes = maps.Clone(s.linkCodes)
+	c.sessions = maps.Clone(s.sessions)
+	c.prevRefreshHashes = maps.Clone(s.prevRefreshHashes)
+	c.botNonces = maps.Clone(s.botNonces)
+	c.logins = slices.Clone(s.logins)
+	c.botMessages = slices.Clone(s.botMessages)
+	c.audit = slices.Clone(s.audit)
+	c.outbox = slices.Clone(s.outbox)
+	c.webhooks = maps.Clone(s.webhooks)
+	c.deliveries = slices.Clone(s.deliveries)
+	return &c
+}
+
+// sortedKeys — аналог ORDER BY id для map-таблиц.
+func sortedKeys[K int32 | int64, V any](m map[K]V) []K {
+	return slices.Sorted(maps.Keys(m))
+}
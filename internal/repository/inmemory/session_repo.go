@@ -0,0 +1,144 @@
+package inmemory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+func (r *Repo) CreateSession(_ context.Context, session models.Session) error {
+	defer r.lock()()
+
+	if _, ok := r.st.sessions[session.ID]; ok {
+		return fmt.Errorf("inmemory: session %q already exists", session.ID)
+	}
+	if _, ok := r.st.users[session.UserID]; !ok {
+		return fmt.Errorf("inmemory: session references missing user %d", session.UserID)
+	}
+	session.RevokedAt = time.Time{}
+	session.CreatedAt = time.Now()
+	r.st.sessions[session.ID] = session
+	return nil
+}
+
+func (r *Repo) GetSessionState(_ context.Context, sessionID string) (models.SessionState, error) {
+	defer r.lock()()
+
+	s, ok := r.st.sessions[sessionID]
+	if !ok {
+		return models.SessionState{}, modelerrors.ErrNoRows
+	}
+	u, ok := r.st.users[s.UserID]
+	if !ok {
+		return models.SessionState{}, modelerrors.ErrNoRows
+	}
+	return models.SessionState{
+		UserID:      s.UserID,
+		ExpiresAt:   s.ExpiresAt,
+		Revoked:     !s.RevokedAt.IsZero(),
+		UserBlocked: u.Blocked(),
+	}, nil
+}
+
+func (r *Repo) GetSessionByRefreshHash(_ context.Context, hash []byte) (models.RefreshSessionState, error) {
+	defer r.lock()()
+
+	for _, s := range r.st.sessions {
+		current := bytes.Equal(s.RefreshTokenHash, hash)
+		if len(hash) == 0 || !current && !bytes.Equal(r.st.prevRefreshHashes[s.ID], hash) {
+			continue
+		}
+		u, ok := r.st.users[s.UserID]
+		if !ok {
+			return models.RefreshSessionState{}, modelerrors.ErrNoRows
+		}
+		return models.RefreshSessionState{
+			SessionID:   s.ID,
+			UserID:      s.UserID,
+			ExpiresAt:   s.ExpiresAt,
+			Revoked:     !s.RevokedAt.IsZero(),
+			UserBlocked: u.Blocked(),
+			Current:     current,
+		}, nil
+	}
+	return models.RefreshSessionState{}, modelerrors.ErrNoRows
+}
+
+func (r *Repo) RotateSessionRefresh(_ context.Context, sessionID string, oldHash, newHash []byte) (bool, error) {
+	defer r.lock()()
+
+	s, ok := r.st.sessions[sessionID]
+	if !ok || !s.RevokedAt.IsZero() || !bytes.Equal(s.RefreshTokenHash, oldHash) {
+		return false, nil
+	}
+	r.st.prevRefreshHashes[sessionID] = s.RefreshTokenHash
+	s.RefreshTokenHash = newHash
+	r.st.sessions[sessionID] = s
+	return true, nil
+}
+
+func (r *Repo) RevokeSession(_ context.Context, sessionID string) (bool, error) {
+	defer r.lock()()
+
+	s, ok := r.st.sessions[sessionID]
+	if !ok || !s.RevokedAt.IsZero() {
+		return false, nil
+	}
+	s.RevokedAt = time.Now()
+	r.st.sessions[sessionID] = s
+	return true, nil
+}
+
+func (r *Repo) ListSessionsByUser(_ context.Context, userID int32, limit int32) ([]models.Session, error) {
+	defer r.lock()()
+
+	var sessions []models.Session
+	for s := range maps.Values(r.st.sessions) {
+		if s.UserID == userID {
+			sessions = append(sessions, s)
+		}
+	}
+	// ORDER BY created_at DESC
+	slices.SortFunc(sessions, func(a, b models.Session) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	if len(sessions) > int(limit) {
+		sessions = sessions[:limit]
+	}
+	return sessions, nil
+}
+
+func (r *Repo) RevokeUserSessions(_ context.Context, userID int32) (int64, error) {
+	defer r.lock()()
+
+	var revoked int64
+	now := time.Now()
+	for id, s := range r.st.sessions {
+		if s.UserID == userID && s.RevokedAt.IsZero() {
+			s.RevokedAt = now
+			r.st.sessions[id] = s
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (r *Repo) MoveSessions(_ context.Context, fromUserID, toUserID int32) (int64, error) {
+	defer r.lock()()
+
+	var n int64
+	for id, s := range r.st.sessions {
+		if s.UserID == fromUserID {
+			s.UserID = toUserID
+			r.st.sessions[id] = s
+			n++
+		}
+	}
+	return n, nil
+}
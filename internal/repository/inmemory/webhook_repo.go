@@ -0,0 +1,160 @@
+package inmemory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+func (r *Repo) CreateWebhook(_ context.Context, webhook models.Webhook) (models.Webhook, error) {
+	defer r.lock()()
+
+	r.st.lastWebhookID++
+	webhook.ID = r.st.lastWebhookID
+	webhook.EventTypes = slices.Clone(webhook.EventTypes)
+	webhook.CreatedAt = time.Now()
+	r.st.webhooks[webhook.ID] = webhook
+	return webhook, nil
+}
+
+func (r *Repo) ListWebhooks(_ context.Context) ([]models.Webhook, error) {
+	defer r.lock()()
+
+	webhooks := make([]models.Webhook, 0, len(r.st.webhooks))
+	for _, id := range sortedKeys(r.st.webhooks) {
+		webhooks = append(webhooks, r.st.webhooks[id])
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook удаляет подписку вместе с журналом доставок (ON DELETE CASCADE).
+func (r *Repo) DeleteWebhook(_ context.Context, id int64) error {
+	defer r.lock()()
+
+	if _, ok := r.st.webhooks[id]; !ok {
+		return modelerrors.ErrNoRows
+	}
+	delete(r.st.webhooks, id)
+	r.st.deliveries = slices.DeleteFunc(r.st.deliveries, func(d deliveryRow) bool {
+		return d.WebhookID == id
+	})
+	return nil
+}
+
+// EnqueueWebhookDelivery идемпотентна: повтор того же события outbox не создаёт вторую доставку.
+func (r *Repo) EnqueueWebhookDelivery(_ context.Context, webhookID int64, event models.OutboxEvent) error {
+	defer r.lock()()
+
+	for _, d := range r.st.deliveries {
+		if d.WebhookID == webhookID && d.EventID == event.ID {
+			return nil
+		}
+	}
+	r.st.lastDeliveryID++
+	now := time.Now()
+	r.st.deliveries = append(r.st.deliveries, deliveryRow{
+		WebhookDelivery: models.WebhookDelivery{
+			ID:            r.st.lastDeliveryID,
+			WebhookID:     webhookID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       event.Payload,
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		},
+	})
+	return nil
+}
+
+func (r *Repo) ClaimWebhookDeliveries(_ context.Context, limit int32, lease time.Duration) ([]models.WebhookJob, error) {
+	defer r.lock()()
+
+	now := time.Now()
+	jobs := make([]models.WebhookJob, 0, limit)
+	for i := range r.st.deliveries {
+		if len(jobs) == int(limit) {
+			break
+		}
+		d := &r.st.deliveries[i]
+		if d.Status != models.WebhookDeliveryPending || d.NextAttemptAt.After(now) || d.lockedUntil >= now.UnixNano() {
+			continue
+		}
+		w, ok := r.st.webhooks[d.WebhookID]
+		if !ok {
+			continue
+		}
+		d.lockedUntil = now.Add(lease).UnixNano()
+		d.Attempts++
+		jobs = append(jobs, models.WebhookJob{
+			Delivery: d.WebhookDelivery,
+			URL:      w.URL,
+			Secret:   w.Secret,
+		})
+	}
+	return jobs, nil
+}
+
+func (r *Repo) MarkWebhookDelivered(_ context.Context, id int64, statusCode int32) error {
+	defer r.lock()()
+
+	if d := r.deliveryRow(id); d != nil {
+		d.Status = models.WebhookDeliveryDelivered
+		d.DeliveredAt = time.Now()
+		d.lockedUntil = 0
+		d.LastStatusCode = statusCode
+		d.LastError = ""
+	}
+	return nil
+}
+
+func (r *Repo) MarkWebhookDeliveryFailed(_ context.Context, id int64, statusCode int32, lastErr string, nextAttemptAt time.Time) error {
+	defer r.lock()()
+
+	if d := r.deliveryRow(id); d != nil {
+		d.lockedUntil = 0
+		d.LastStatusCode = statusCode
+		d.LastError = lastErr
+		d.NextAttemptAt = nextAttemptAt
+	}
+	return nil
+}
+
+func (r *Repo) MarkWebhookDeliveryDead(_ context.Context, id int64, statusCode int32, lastErr string) error {
+	defer r.lock()()
+
+	if d := r.deliveryRow(id); d != nil {
+		d.Status = models.WebhookDeliveryFailed
+		d.lockedUntil = 0
+		d.LastStatusCode = statusCode
+		d.LastError = lastErr
+	}
+	return nil
+}
+
+func (r *Repo) ListWebhookDeliveries(_ context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	defer r.lock()()
+
+	deliveries := make([]models.WebhookDelivery, 0, filter.Limit)
+	for i := len(r.st.deliveries) - 1; i >= 0 && len(deliveries) < int(filter.Limit); i-- {
+		d := r.st.deliveries[i].WebhookDelivery
+		if (filter.WebhookID != 0 && d.WebhookID != filter.WebhookID) || (filter.BeforeID != 0 && d.ID >= filter.BeforeID) {
+			continue
+		}
+		d.Payload = nil // как в psql: журнал отдаётся без тела
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, nil
+}
+
+func (r *Repo) deliveryRow(id int64) *deliveryRow {
+	for i := range r.st.deliveries {
+		if r.st.deliveries[i].ID == id {
+			return &r.st.deliveries[i]
+		}
+	}
+	return nil
+}
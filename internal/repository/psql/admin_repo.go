@@ -0,0 +1,69 @@
+package psql
+
+import (
+	"context"
+	"strings"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/psql/query"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func (r *Repo) ListUsers(ctx context.Context, filter models.UserFilter) ([]models.User, error) {
+	params := query.ListUsersParams{
+		AfterID:  filter.AfterID,
+		PageSize: filter.Limit,
+	}
+	if filter.EmailQuery != "" {
+		params.EmailPattern = pgtype.Text{String: "%" + escapeLike(filter.EmailQuery) + "%", Valid: true}
+	}
+
+	rows, err := r.queries.ListUsers(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]models.User, 0, len(rows))
+	for _, row := range rows {
+		users = append(users, models.User{
+			ID:        row.ID,
+			Email:     row.Email,
+			Role:      row.Role,
+			BlockedAt: timeFromPg(row.BlockedAt),
+			CreatedAt: timeFromPg(row.CreatedAt),
+		})
+	}
+	return users, nil
+}
+
+func (r *Repo) BlockUser(ctx context.Context, userID int32) error {
+	return affectedOrNoRows(r.queries.BlockUser(ctx, userID))
+}
+
+func (r *Repo) UnblockUser(ctx context.Context, userID int32) error {
+	return affectedOrNoRows(r.queries.UnblockUser(ctx, userID))
+}
+
+func (r *Repo) ResetUserMfa(ctx context.Context, userID int32) error {
+	return affectedOrNoRows(r.queries.ResetUserMfa(ctx, userID))
+}
+
+func (r *Repo) DeleteUser(ctx context.Context, userID int32) error {
+	return affectedOrNoRows(r.queries.DeleteUser(ctx, userID))
+}
+
+func affectedOrNoRows(n int64, err error) error {
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return modelerrors.ErrNoRows
+	}
+	return nil
+}
+
+// escapeLike экранирует спецсимволы LIKE, чтобы поиск шёл по подстроке как есть.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
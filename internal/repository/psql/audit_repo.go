@@ -0,0 +1,66 @@
+package psql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/psql/query"
+)
+
+func (r *Repo) RecordAuditEvent(ctx context.Context, event models.AuditEvent) error {
+	details := event.Details
+	if details == nil {
+		details = map[string]string{}
+	}
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("marshal audit details: %w", err)
+	}
+
+	return r.queries.InsertAuditEvent(ctx, query.InsertAuditEventParams{
+		EventType:    event.Type,
+		ActorUserID:  int4ToPg(event.ActorUserID),
+		ActorBotID:   textToPg(event.ActorBotID),
+		TargetUserID: int4ToPg(event.TargetUserID),
+		Ip:           textToPg(event.IP),
+		UserAgent:    textToPg(event.UserAgent),
+		RequestID:    textToPg(event.RequestID),
+		Details:      raw,
+	})
+}
+
+func (r *Repo) ListAuditEvents(ctx context.Context, filter models.AuditFilter) ([]models.AuditEvent, error) {
+	rows, err := r.queries.ListAuditEvents(ctx, query.ListAuditEventsParams{
+		UserID:   int4ToPg(filter.UserID),
+		FromTime: timeToPg(filter.From),
+		ToTime:   timeToPg(filter.To),
+		BeforeID: filter.BeforeID,
+		PageSize: filter.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]models.AuditEvent, 0, len(rows))
+	for _, row := range rows {
+		var details map[string]string
+		if err := json.Unmarshal(row.Details, &details); err != nil {
+			return nil, fmt.Errorf("unmarshal audit details of event %d: %w", row.ID, err)
+		}
+		events = append(events, models.AuditEvent{
+			ID:           row.ID,
+			Type:         row.EventType,
+			ActorUserID:  int4FromPg(row.ActorUserID),
+			ActorBotID:   textFromPg(row.ActorBotID),
+			TargetUserID: int4FromPg(row.TargetUserID),
+			IP:           textFromPg(row.Ip),
+			UserAgent:    textFromPg(row.UserAgent),
+			RequestID:    textFromPg(row.RequestID),
+			Details:      details,
+			OccurredAt:   timeFromPg(row.OccurredAt),
+		})
+	}
+	return events, nil
+}
@@ -13,8 +13,9 @@ import (
 
 func (r *Repo) CreateUser(ctx context.Context, user models.User) (int32, error) {
 	createUserParams := query.CreateUserParams{
-		Email:        user.Email,
-		HashPassword: user.HashPassword,
+		Email:           user.Email,
+		EmailNormalized: user.EmailNormalized,
+		HashPassword:    user.HashPassword,
 	}
 
 	userId, err := r.queries.CreateUser(ctx, createUserParams)
@@ -28,8 +29,26 @@ func (r *Repo) CreateUser(ctx context.Context, user models.User) (int32, error)
 	return userId, err
 }
 
-func (r *Repo) GetByEmail(ctx context.Context, email string) (models.User, error) {
-	user, err := r.queries.GetUserByEmail(ctx, email)
+func (r *Repo) GetByEmail(ctx context.Context, normalizedEmail string) (models.User, error) {
+	user, err := r.queries.GetUserByEmail(ctx, normalizedEmail)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.User{}, modelerrors.ErrNoRows
+		}
+		return models.User{}, err
+	}
+	return models.User{
+		ID:              user.ID,
+		Email:           user.Email,
+		EmailNormalized: normalizedEmail,
+		HashPassword:    user.HashPassword,
+		Role:            user.Role,
+		BlockedAt:       timeFromPg(user.BlockedAt),
+	}, nil
+}
+
+func (r *Repo) GetUserByID(ctx context.Context, userID int32) (models.User, error) {
+	user, err := r.queries.GetUserByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return models.User{}, modelerrors.ErrNoRows
@@ -38,6 +57,17 @@ func (r *Repo) GetByEmail(ctx context.Context, email string) (models.User, error
 	}
 	return models.User{
 		ID:           user.ID,
+		Email:        user.Email,
 		HashPassword: user.HashPassword,
+		Role:         user.Role,
+		BlockedAt:    timeFromPg(user.BlockedAt),
+		CreatedAt:    timeFromPg(user.CreatedAt),
 	}, nil
 }
+
+func (r *Repo) UpdatePassword(ctx context.Context, userID int32, hashPassword string) error {
+	return affectedOrNoRows(r.queries.UpdateUserPassword(ctx, query.UpdateUserPasswordParams{
+		ID:           userID,
+		HashPassword: hashPassword,
+	}))
+}
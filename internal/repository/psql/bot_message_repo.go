@@ -0,0 +1,34 @@
+package psql
+
+import (
+	"context"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/psql/query"
+)
+
+func (r *Repo) EnqueueBotMessage(ctx context.Context, chatID int64, text string) error {
+	return r.queries.EnqueueBotMessage(ctx, query.EnqueueBotMessageParams{
+		ChatID: chatID,
+		Text:   text,
+	})
+}
+
+// ClaimBotMessages отдаёт до limit недоставленных сообщений и сразу помечает их доставленными.
+func (r *Repo) ClaimBotMessages(ctx context.Context, limit int32) ([]models.BotMessage, error) {
+	rows, err := r.queries.ClaimBotMessages(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]models.BotMessage, 0, len(rows))
+	for _, row := range rows {
+		messages = append(messages, models.BotMessage{
+			ID:        row.ID,
+			ChatID:    row.ChatID,
+			Text:      row.Text,
+			CreatedAt: timeFromPg(row.CreatedAt),
+		})
+	}
+	return messages, nil
+}
@@ -0,0 +1,25 @@
+package psql
+
+import (
+	"context"
+	"time"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/psql/query"
+)
+
+// UseBotNonce запоминает nonce бота до expiresAt. ErrReplay — такой nonce уже был.
+func (r *Repo) UseBotNonce(ctx context.Context, botID, nonce string, expiresAt time.Time) error {
+	n, err := r.queries.UseBotNonce(ctx, query.UseBotNonceParams{
+		BotID:     botID,
+		Nonce:     nonce,
+		ExpiresAt: timeToPg(expiresAt),
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return modelerrors.ErrReplay
+	}
+	return nil
+}
@@ -9,7 +9,13 @@ import (
 )
 
 func Connect(dsn string) (*pgxpool.Pool, error) {
-	db, err := pgxpool.New(context.Background(), dsn)
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ConnConfig.Tracer = newQueryTracer()
+
+	db, err := pgxpool.NewWithConfig(context.Background(), cfg)
 	if err != nil {
 		return nil, err
 	}
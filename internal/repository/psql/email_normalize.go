@@ -0,0 +1,98 @@
+package psql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/psql/query"
+	"github.com/jackc/pgx/v5"
+)
+
+// MigrateNormalizeEmails — команда migrate, пересчёт users.email_normalized.
+const MigrateNormalizeEmails = "normalize-emails"
+
+// ErrEmailCollisions — после нормализации у нескольких аккаунтов один ключ.
+var ErrEmailCollisions = errors.New("email collisions after normalization")
+
+// NormalizeEmails пересчитывает users.email_normalized текущими правилами: SQL-миграция умеет
+// только lower(), IDNA и provider_rules считаются здесь. При коллизиях ничего не меняет —
+// печатает их в w и возвращает ErrEmailCollisions; аккаунты сливают или правят вручную.
+func NormalizeEmails(ctx context.Context, dsn string, normalize func(string) (string, error), w io.Writer) error {
+	if err := CheckSchemaVersion(ctx, dsn); err != nil {
+		return err
+	}
+	db, err := Connect(dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	users, err := query.New(db).ListUserEmails(ctx)
+	if err != nil {
+		return err
+	}
+
+	byKey := make(map[string][]query.ListUserEmailsRow, len(users))
+	var changed []query.ListUserEmailsRow
+	for _, u := range users {
+		key, err := normalize(u.Email)
+		if err != nil {
+			// такой адрес не пройдёт и логин; оставляем старый ключ
+			fmt.Fprintf(w, "skip user %d (%q): %s\n", u.ID, u.Email, err)
+			key = u.EmailNormalized
+		}
+		byKey[key] = append(byKey[key], u)
+		if key != u.EmailNormalized {
+			u.EmailNormalized = key
+			changed = append(changed, u)
+		}
+	}
+
+	collisions := 0
+	for key, group := range byKey {
+		if len(group) < 2 {
+			continue
+		}
+		collisions++
+		fmt.Fprintf(w, "collision %s:\n", key)
+		for _, u := range group {
+			fmt.Fprintf(w, "  user %d %q\n", u.ID, u.Email)
+		}
+	}
+	if collisions > 0 {
+		return fmt.Errorf("%w: %d keys, nothing updated", ErrEmailCollisions, collisions)
+	}
+
+	// UNIQUE проверяется построчно: если ключи меняются "по кругу", прямой UPDATE упрётся
+	// в ещё не обновлённую строку. Поэтому сначала временные ключи (без '@' — не совпадут
+	// с настоящими), потом итоговые.
+	err = pgx.BeginFunc(ctx, db, func(tx pgx.Tx) error {
+		q := query.New(tx)
+		for _, u := range changed {
+			if err := q.SetUserEmailNormalized(ctx, query.SetUserEmailNormalizedParams{
+				ID:              u.ID,
+				EmailNormalized: "#" + strconv.Itoa(int(u.ID)),
+			}); err != nil {
+				return err
+			}
+		}
+		for _, u := range changed {
+			if err := q.SetUserEmailNormalized(ctx, query.SetUserEmailNormalizedParams{
+				ID:              u.ID,
+				EmailNormalized: u.EmailNormalized,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "updated %d of %d users\n", len(changed), len(users))
+	return nil
+}
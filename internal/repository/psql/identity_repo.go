@@ -0,0 +1,90 @@
+package psql
+
+import (
+	"context"
+	"errors"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/psql/query"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func (r *Repo) GetIdentity(ctx context.Context, provider, providerUserID string) (models.Identity, error) {
+	row, err := r.queries.GetIdentityByProvider(ctx, query.GetIdentityByProviderParams{
+		Provider:       provider,
+		ProviderUserID: providerUserID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Identity{}, modelerrors.ErrNoRows
+		}
+		return models.Identity{}, err
+	}
+	return identityFromRow(row), nil
+}
+
+func (r *Repo) ListIdentitiesByUser(ctx context.Context, userID int32) ([]models.Identity, error) {
+	rows, err := r.queries.ListIdentitiesByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	identities := make([]models.Identity, 0, len(rows))
+	for _, row := range rows {
+		identities = append(identities, identityFromRow(row))
+	}
+	return identities, nil
+}
+
+func (r *Repo) CreateIdentity(ctx context.Context, identity models.Identity) (int64, error) {
+	id, err := r.queries.CreateIdentity(ctx, query.CreateIdentityParams{
+		UserID:         identity.UserID,
+		Provider:       identity.Provider,
+		ProviderUserID: identity.ProviderUserID,
+		Username:       textToPg(identity.Username),
+		FirstName:      textToPg(identity.FirstName),
+		LastName:       textToPg(identity.LastName),
+		ChatID:         int8ToPg(identity.ChatID),
+	})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return 0, modelerrors.ErrTelegramAlreadyLinked
+	}
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func identityFromRow(row query.UserIdentity) models.Identity {
+	return models.Identity{
+		ID:             row.ID,
+		UserID:         row.UserID,
+		Provider:       row.Provider,
+		ProviderUserID: row.ProviderUserID,
+		Username:       textFromPg(row.Username),
+		FirstName:      textFromPg(row.FirstName),
+		LastName:       textFromPg(row.LastName),
+		ChatID:         int8FromPg(row.ChatID),
+		CreatedAt:      timeFromPg(row.CreatedAt),
+	}
+}
+
+func (r *Repo) MoveIdentities(ctx context.Context, fromUserID, toUserID int32) (int64, error) {
+	return r.queries.MoveIdentities(ctx, query.MoveIdentitiesParams{
+		FromUserID: fromUserID,
+		ToUserID:   toUserID,
+	})
+}
+
+func (r *Repo) UpdateIdentityProfile(ctx context.Context, identity models.Identity) error {
+	return r.queries.UpdateIdentityProfile(ctx, query.UpdateIdentityProfileParams{
+		ID:        identity.ID,
+		Username:  textToPg(identity.Username),
+		FirstName: textToPg(identity.FirstName),
+		LastName:  textToPg(identity.LastName),
+		ChatID:    int8ToPg(identity.ChatID),
+	})
+}
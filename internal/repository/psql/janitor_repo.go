@@ -0,0 +1,103 @@
+package psql
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/logger"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/psql/query"
+)
+
+// TryLock берёт session-level advisory lock по имени на отдельном соединении.
+// Lock держится, пока соединение не отдано в пул, поэтому unlock обязателен.
+// acquired = false — lock у другой реплики.
+func (r *Repo) TryLock(ctx context.Context, name string) (unlock func(), acquired bool, err error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	key := advisoryKey(name)
+	q := query.New(conn)
+	acquired, err = q.TryAdvisoryLock(ctx, key)
+	if err != nil || !acquired {
+		conn.Release()
+		return nil, false, err
+	}
+
+	return func() {
+		// ctx job-а может быть уже отменён, а lock нужно снять в любом случае
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := q.AdvisoryUnlock(unlockCtx, key); err != nil {
+			// соединение с зависшим lock-ом в пул возвращать нельзя
+			logger.Log.Errorf("psql: advisory unlock %q: %s", name, err)
+			conn.Conn().Close(unlockCtx)
+		}
+		conn.Release()
+	}, true, nil
+}
+
+// ClaimRun отмечает запуск job-а в scheduler_job_runs; claimed = false — другая реплика
+// запускала его меньше minGap назад.
+func (r *Repo) ClaimRun(ctx context.Context, name string, minGap time.Duration) (claimed bool, err error) {
+	n, err := r.queries.ClaimJobRun(ctx, query.ClaimJobRunParams{
+		Name:     name,
+		MinGapMs: minGap.Milliseconds(),
+	})
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// advisoryKey — стабильный int64 по имени; префикс отделяет ключи сервиса от чужих.
+func advisoryKey(name string) int64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "bottrade.auth:%s", name)
+	return int64(h.Sum64())
+}
+
+func (r *Repo) PurgeExpiredLinkCodes(ctx context.Context, before time.Time, batch int32) (int64, error) {
+	return r.queries.PurgeExpiredLinkCodes(ctx, query.PurgeExpiredLinkCodesParams{
+		Before:    timeToPg(before),
+		BatchSize: batch,
+	})
+}
+
+func (r *Repo) PurgeExpiredSessions(ctx context.Context, before time.Time, batch int32) (int64, error) {
+	return r.queries.PurgeExpiredSessions(ctx, query.PurgeExpiredSessionsParams{
+		Before:    timeToPg(before),
+		BatchSize: batch,
+	})
+}
+
+func (r *Repo) PurgePublishedOutbox(ctx context.Context, before time.Time, batch int32) (int64, error) {
+	return r.queries.PurgePublishedOutbox(ctx, query.PurgePublishedOutboxParams{
+		Before:    timeToPg(before),
+		BatchSize: batch,
+	})
+}
+
+func (r *Repo) PurgeDeliveredBotMessages(ctx context.Context, before time.Time, batch int32) (int64, error) {
+	return r.queries.PurgeDeliveredBotMessages(ctx, query.PurgeDeliveredBotMessagesParams{
+		Before:    timeToPg(before),
+		BatchSize: batch,
+	})
+}
+
+func (r *Repo) PurgeDeliveredWebhookDeliveries(ctx context.Context, before time.Time, batch int32) (int64, error) {
+	return r.queries.PurgeDeliveredWebhookDeliveries(ctx, query.PurgeDeliveredWebhookDeliveriesParams{
+		Before:    timeToPg(before),
+		BatchSize: batch,
+	})
+}
+
+func (r *Repo) PurgeExpiredBotNonces(ctx context.Context, before time.Time, batch int32) (int64, error) {
+	return r.queries.PurgeExpiredBotNonces(ctx, query.PurgeExpiredBotNoncesParams{
+		Before:    timeToPg(before),
+		BatchSize: batch,
+	})
+}
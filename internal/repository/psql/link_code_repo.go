@@ -0,0 +1,48 @@
+package psql
+
+import (
+	"context"
+	"errors"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/psql/query"
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *Repo) CreateLinkCode(ctx context.Context, code models.LinkCode) error {
+	return r.queries.CreateLinkCode(ctx, query.CreateLinkCodeParams{
+		Code:      code.Code,
+		UserID:    code.UserID,
+		ExpiresAt: timeToPg(code.ExpiresAt),
+	})
+}
+
+func (r *Repo) GetLinkCode(ctx context.Context, code string) (models.LinkCode, error) {
+	row, err := r.queries.GetLinkCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.LinkCode{}, modelerrors.ErrNoRows
+		}
+		return models.LinkCode{}, err
+	}
+	return models.LinkCode{
+		Code:      row.Code,
+		UserID:    row.UserID,
+		ExpiresAt: timeFromPg(row.ExpiresAt),
+		UsedAt:    timeFromPg(row.UsedAt),
+	}, nil
+}
+
+// UseLinkCode атомарно помечает код использованным.
+// ErrNoRows — кода нет, он истёк или уже использован.
+func (r *Repo) UseLinkCode(ctx context.Context, code string) (userID int32, err error) {
+	userID, err = r.queries.UseLinkCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, modelerrors.ErrNoRows
+		}
+		return 0, err
+	}
+	return userID, nil
+}
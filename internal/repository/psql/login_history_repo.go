@@ -0,0 +1,68 @@
+package psql
+
+import (
+	"context"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/psql/query"
+)
+
+func (r *Repo) RecordLogin(ctx context.Context, record models.LoginRecord) error {
+	return r.queries.InsertLoginHistory(ctx, query.InsertLoginHistoryParams{
+		UserID:            record.UserID,
+		Method:            record.Method,
+		Ip:                textToPg(record.IP),
+		UserAgent:         textToPg(record.UserAgent),
+		DeviceName:        record.DeviceName,
+		DeviceFingerprint: record.DeviceFingerprint,
+	})
+}
+
+func (r *Repo) GetLoginSeen(ctx context.Context, userID int32, deviceFingerprint, ip string) (models.LoginSeen, error) {
+	row, err := r.queries.GetLoginSeen(ctx, query.GetLoginSeenParams{
+		UserID:            userID,
+		DeviceFingerprint: deviceFingerprint,
+		Ip:                textToPg(ip),
+	})
+	if err != nil {
+		return models.LoginSeen{}, err
+	}
+	return models.LoginSeen{
+		HasHistory: row.HasHistory,
+		DeviceSeen: row.DeviceSeen,
+		IPSeen:     row.IpSeen,
+	}, nil
+}
+
+func (r *Repo) ListLoginHistory(ctx context.Context, userID int32, beforeID int64, limit int32) ([]models.LoginRecord, error) {
+	rows, err := r.queries.ListLoginHistory(ctx, query.ListLoginHistoryParams{
+		UserID:   userID,
+		BeforeID: beforeID,
+		PageSize: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]models.LoginRecord, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, models.LoginRecord{
+			ID:                row.ID,
+			UserID:            row.UserID,
+			Method:            row.Method,
+			IP:                textFromPg(row.Ip),
+			UserAgent:         textFromPg(row.UserAgent),
+			DeviceName:        row.DeviceName,
+			DeviceFingerprint: row.DeviceFingerprint,
+			CreatedAt:         timeFromPg(row.CreatedAt),
+		})
+	}
+	return records, nil
+}
+
+func (r *Repo) MoveLoginHistory(ctx context.Context, fromUserID, toUserID int32) (int64, error) {
+	return r.queries.MoveLoginHistory(ctx, query.MoveLoginHistoryParams{
+		FromUserID: fromUserID,
+		ToUserID:   toUserID,
+	})
+}
@@ -0,0 +1,133 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"text/tabwriter"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/logger"
+	"github.com/IvanOplesnin/BotTradeService.git/migrations"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+// Команды migrate.
+const (
+	MigrateUp     = "up"
+	MigrateDown   = "down"
+	MigrateStatus = "status"
+	MigrateRedo   = "redo"
+)
+
+// ErrSchemaBehind — база отстаёт от миграций, встроенных в бинарник.
+var ErrSchemaBehind = errors.New("database schema is behind the binary")
+
+// Migrate выполняет команду над встроенными миграциями. status пишет таблицу в w.
+// Параллельные запуски (несколько реплик с auto_migrate) сериализуются advisory lock-ом goose.
+func Migrate(ctx context.Context, dsn, command string, w io.Writer) error {
+	provider, closeDB, err := newMigrator(dsn)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	switch command {
+	case MigrateUp:
+		results, err := provider.Up(ctx)
+		logMigrations(results)
+		return err
+	case MigrateDown:
+		result, err := provider.Down(ctx)
+		logMigrations([]*goose.MigrationResult{result})
+		return err
+	case MigrateRedo:
+		result, err := provider.Down(ctx)
+		logMigrations([]*goose.MigrationResult{result})
+		if err != nil {
+			return err
+		}
+		result, err = provider.UpByOne(ctx)
+		logMigrations([]*goose.MigrationResult{result})
+		return err
+	case MigrateStatus:
+		statuses, err := provider.Status(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tSTATE\tAPPLIED AT\tFILE")
+		for _, s := range statuses {
+			appliedAt := "-"
+			if !s.AppliedAt.IsZero() {
+				appliedAt = s.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", s.Source.Version, s.State, appliedAt, s.Source.Path)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command %q (want up, down, status or redo)", command)
+	}
+}
+
+// CheckSchemaVersion не даёт стартовать на базе, где применены не все встроенные миграции.
+// База новее бинарника допустима: так выглядит откат версии или rolling deploy.
+func CheckSchemaVersion(ctx context.Context, dsn string) error {
+	provider, closeDB, err := newMigrator(dsn)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	current, target, err := provider.GetVersions(ctx)
+	if err != nil {
+		return fmt.Errorf("get schema version: %w", err)
+	}
+	if current < target {
+		return fmt.Errorf("%w: database at version %d, binary expects %d; run `migrate up` or enable app.auto_migrate",
+			ErrSchemaBehind, current, target)
+	}
+	if current > target {
+		logger.Log.Warnf("database schema version %d is newer than binary's %d", current, target)
+	}
+	return nil
+}
+
+func newMigrator(dsn string) (*goose.Provider, func(), error) {
+	schema, err := fs.Sub(migrations.FS, migrations.Dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// goose работает через database/sql; драйвер pgx зарегистрирован импортом pgx/v5/stdlib
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, schema, goose.WithSessionLocker(locker))
+	if err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("init migrations: %w", err)
+	}
+	return provider, func() { provider.Close() }, nil
+}
+
+func logMigrations(results []*goose.MigrationResult) {
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		if r.Error != nil {
+			logger.Log.Errorf("migration %s %d (%s) failed: %s", r.Direction, r.Source.Version, r.Source.Path, r.Error)
+			continue
+		}
+		logger.Log.Infof("migration %s %d (%s) done in %s", r.Direction, r.Source.Version, r.Source.Path, r.Duration)
+	}
+}
@@ -0,0 +1,73 @@
+package psql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/psql/query"
+)
+
+// AddOutboxEvent пишет доменное событие. Вызывается на tx-репозитории из WithTx,
+// чтобы событие закоммитилось вместе с изменением состояния.
+func (r *Repo) AddOutboxEvent(ctx context.Context, eventType string, event models.UserEvent) error {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal %s event: %w", eventType, err)
+	}
+	return r.queries.InsertOutboxEvent(ctx, query.InsertOutboxEventParams{
+		EventType:   eventType,
+		AggregateID: strconv.FormatInt(int64(event.UserID), 10),
+		Payload:     raw,
+	})
+}
+
+// ClaimOutboxEvents берёт в аренду до limit готовых к отправке событий.
+// Если воркер упадёт, по истечении lease события заберёт следующий.
+func (r *Repo) ClaimOutboxEvents(ctx context.Context, limit int32, lease time.Duration) ([]models.OutboxEvent, error) {
+	rows, err := r.queries.ClaimOutboxEvents(ctx, query.ClaimOutboxEventsParams{
+		LeaseSec:  int32(lease / time.Second),
+		BatchSize: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]models.OutboxEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, models.OutboxEvent{
+			ID:          row.ID,
+			Type:        row.EventType,
+			AggregateID: row.AggregateID,
+			Payload:     row.Payload,
+			Attempts:    row.Attempts,
+			CreatedAt:   timeFromPg(row.CreatedAt),
+		})
+	}
+	return events, nil
+}
+
+func (r *Repo) MarkOutboxPublished(ctx context.Context, id int64) error {
+	return r.queries.MarkOutboxPublished(ctx, id)
+}
+
+func (r *Repo) MarkOutboxFailed(ctx context.Context, id int64, lastErr string, nextAttemptAt time.Time) error {
+	return r.queries.MarkOutboxFailed(ctx, query.MarkOutboxFailedParams{
+		ID:            id,
+		LastError:     textToPg(lastErr),
+		NextAttemptAt: timeToPg(nextAttemptAt),
+	})
+}
+
+func (r *Repo) MarkOutboxDead(ctx context.Context, id int64, lastErr string) error {
+	return r.queries.MarkOutboxDead(ctx, query.MarkOutboxDeadParams{
+		ID:        id,
+		LastError: textToPg(lastErr),
+	})
+}
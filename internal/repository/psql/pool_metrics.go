@@ -0,0 +1,74 @@
+package psql
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolCollector отдаёт pgxpool.Stat() при каждом scrape: состояние соединений и ожидание Acquire.
+type PoolCollector struct {
+	pool *pgxpool.Pool
+
+	acquiredConns    *prometheus.Desc
+	idleConns        *prometheus.Desc
+	constructingConn *prometheus.Desc
+	totalConns       *prometheus.Desc
+	maxConns         *prometheus.Desc
+	acquireCount     *prometheus.Desc
+	acquireSeconds   *prometheus.Desc
+	emptyAcquire     *prometheus.Desc
+	canceledAcquire  *prometheus.Desc
+	newConns         *prometheus.Desc
+}
+
+func NewPoolCollector(pool *pgxpool.Pool) *PoolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc("bottrade_pgxpool_"+name, help, nil, nil)
+	}
+	return &PoolCollector{
+		pool:             pool,
+		acquiredConns:    desc("acquired_conns", "Connections currently in use."),
+		idleConns:        desc("idle_conns", "Idle connections in the pool."),
+		constructingConn: desc("constructing_conns", "Connections being established."),
+		totalConns:       desc("total_conns", "All connections in the pool."),
+		maxConns:         desc("max_conns", "Maximum pool size."),
+		acquireCount:     desc("acquire_total", "Successful connection acquires."),
+		acquireSeconds:   desc("acquire_duration_seconds_total", "Total time spent waiting in Acquire."),
+		emptyAcquire:     desc("empty_acquire_total", "Acquires that had to wait for a connection."),
+		canceledAcquire:  desc("canceled_acquire_total", "Acquires canceled by context."),
+		newConns:         desc("new_conns_total", "Connections opened."),
+	}
+}
+
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.constructingConn
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquireCount
+	ch <- c.acquireSeconds
+	ch <- c.emptyAcquire
+	ch <- c.canceledAcquire
+	ch <- c.newConns
+}
+
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	st := c.pool.Stat()
+	gauge := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v)
+	}
+	counter := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, v)
+	}
+	gauge(c.acquiredConns, float64(st.AcquiredConns()))
+	gauge(c.idleConns, float64(st.IdleConns()))
+	gauge(c.constructingConn, float64(st.ConstructingConns()))
+	gauge(c.totalConns, float64(st.TotalConns()))
+	gauge(c.maxConns, float64(st.MaxConns()))
+	counter(c.acquireCount, float64(st.AcquireCount()))
+	counter(c.acquireSeconds, st.AcquireDuration().Seconds())
+	counter(c.emptyAcquire, float64(st.EmptyAcquireCount()))
+	counter(c.canceledAcquire, float64(st.CanceledAcquireCount()))
+	counter(c.newConns, float64(st.NewConnsCount()))
+}
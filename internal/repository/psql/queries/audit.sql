@@ -0,0 +1,25 @@
+-- name: InsertAuditEvent :exec
+INSERT INTO audit_events (
+    event_type,
+    actor_user_id,
+    actor_bot_id,
+    target_user_id,
+    ip,
+    user_agent,
+    request_id,
+    details
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+);
+
+-- name: ListAuditEvents :many
+SELECT id, event_type, actor_user_id, actor_bot_id, target_user_id, ip, user_agent, request_id, details, occurred_at
+FROM audit_events
+WHERE (sqlc.narg(user_id)::integer IS NULL
+       OR actor_user_id = sqlc.narg(user_id)
+       OR target_user_id = sqlc.narg(user_id))
+  AND (sqlc.narg(from_time)::timestamptz IS NULL OR occurred_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(to_time)::timestamptz IS NULL OR occurred_at < sqlc.narg(to_time))
+  AND (sqlc.arg(before_id)::bigint = 0 OR id < sqlc.arg(before_id))
+ORDER BY id DESC
+LIMIT sqlc.arg(page_size);
@@ -0,0 +1,25 @@
+-- name: EnqueueBotMessage :exec
+INSERT INTO bot_messages (
+    chat_id,
+    text
+) VALUES (
+    $1, $2
+);
+
+-- name: ClaimBotMessages :many
+WITH claimed AS (
+    UPDATE bot_messages
+    SET delivered_at = now()
+    WHERE id IN (
+        SELECT m.id
+        FROM bot_messages m
+        WHERE m.delivered_at IS NULL
+        ORDER BY m.id
+        LIMIT sqlc.arg(page_size)
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING id, chat_id, text, created_at
+)
+SELECT id, chat_id, text, created_at
+FROM claimed
+ORDER BY id;
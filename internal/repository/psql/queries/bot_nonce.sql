@@ -0,0 +1,9 @@
+-- name: UseBotNonce :execrows
+INSERT INTO bot_nonces (
+    bot_id,
+    nonce,
+    expires_at
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (bot_id, nonce) DO NOTHING;
@@ -0,0 +1,40 @@
+-- name: GetIdentityByProvider :one
+SELECT id, user_id, provider, provider_user_id, username, first_name, last_name, chat_id, created_at, updated_at
+FROM user_identities
+WHERE provider = $1
+  AND provider_user_id = $2
+LIMIT 1;
+
+-- name: ListIdentitiesByUser :many
+SELECT id, user_id, provider, provider_user_id, username, first_name, last_name, chat_id, created_at, updated_at
+FROM user_identities
+WHERE user_id = $1
+ORDER BY id;
+
+-- name: CreateIdentity :one
+INSERT INTO user_identities (
+    user_id,
+    provider,
+    provider_user_id,
+    username,
+    first_name,
+    last_name,
+    chat_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING id;
+
+-- name: MoveIdentities :execrows
+UPDATE user_identities
+SET user_id = sqlc.arg(to_user_id),
+    updated_at = now()
+WHERE user_id = sqlc.arg(from_user_id);
+
+-- name: UpdateIdentityProfile :exec
+UPDATE user_identities
+SET username = $2,
+    first_name = $3,
+    last_name = $4,
+    chat_id = $5,
+    updated_at = now()
+WHERE id = $1;
@@ -0,0 +1,73 @@
+-- name: TryAdvisoryLock :one
+SELECT pg_try_advisory_lock(sqlc.arg(key)::bigint)::boolean AS acquired;
+
+-- name: AdvisoryUnlock :one
+SELECT pg_advisory_unlock(sqlc.arg(key)::bigint)::boolean AS released;
+
+-- name: ClaimJobRun :execrows
+-- 0 строк — job уже запускался позже, чем min_gap назад (время — часы базы, общие для реплик).
+INSERT INTO scheduler_job_runs (name, last_run_at)
+VALUES (sqlc.arg(name), now())
+ON CONFLICT (name) DO UPDATE SET last_run_at = EXCLUDED.last_run_at
+WHERE scheduler_job_runs.last_run_at <= now() - sqlc.arg(min_gap_ms)::bigint * interval '1 millisecond';
+
+-- name: PurgeExpiredLinkCodes :execrows
+DELETE FROM telegram_link_codes
+WHERE code IN (
+    SELECT c.code
+    FROM telegram_link_codes c
+    WHERE c.expires_at < sqlc.arg(before)
+    ORDER BY c.expires_at
+    LIMIT sqlc.arg(batch_size)
+);
+
+-- name: PurgeExpiredSessions :execrows
+DELETE FROM user_sessions
+WHERE id IN (
+    SELECT s.id
+    FROM user_sessions s
+    WHERE s.expires_at < sqlc.arg(before)
+    ORDER BY s.expires_at
+    LIMIT sqlc.arg(batch_size)
+);
+
+-- name: PurgePublishedOutbox :execrows
+DELETE FROM outbox
+WHERE id IN (
+    SELECT o.id
+    FROM outbox o
+    WHERE o.published_at < sqlc.arg(before)
+    ORDER BY o.id
+    LIMIT sqlc.arg(batch_size)
+);
+
+-- name: PurgeDeliveredBotMessages :execrows
+DELETE FROM bot_messages
+WHERE id IN (
+    SELECT m.id
+    FROM bot_messages m
+    WHERE m.delivered_at < sqlc.arg(before)
+    ORDER BY m.id
+    LIMIT sqlc.arg(batch_size)
+);
+
+-- name: PurgeDeliveredWebhookDeliveries :execrows
+DELETE FROM webhook_deliveries
+WHERE id IN (
+    SELECT d.id
+    FROM webhook_deliveries d
+    WHERE d.status = 'delivered'
+      AND d.delivered_at < sqlc.arg(before)
+    ORDER BY d.id
+    LIMIT sqlc.arg(batch_size)
+);
+
+-- name: PurgeExpiredBotNonces :execrows
+DELETE FROM bot_nonces
+WHERE (bot_id, nonce) IN (
+    SELECT n.bot_id, n.nonce
+    FROM bot_nonces n
+    WHERE n.expires_at < sqlc.arg(before)
+    ORDER BY n.expires_at
+    LIMIT sqlc.arg(batch_size)
+);
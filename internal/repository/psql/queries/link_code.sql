@@ -0,0 +1,22 @@
+-- name: CreateLinkCode :exec
+INSERT INTO telegram_link_codes (
+    code,
+    user_id,
+    expires_at
+) VALUES (
+    $1, $2, $3
+);
+
+-- name: GetLinkCode :one
+SELECT code, user_id, expires_at, used_at, created_at
+FROM telegram_link_codes
+WHERE code = $1
+LIMIT 1;
+
+-- name: UseLinkCode :one
+UPDATE telegram_link_codes
+SET used_at = now()
+WHERE code = $1
+  AND used_at IS NULL
+  AND expires_at > now()
+RETURNING user_id;
@@ -0,0 +1,30 @@
+-- name: InsertLoginHistory :exec
+INSERT INTO login_history (
+    user_id,
+    "method",
+    ip,
+    user_agent,
+    device_name,
+    device_fingerprint
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+);
+
+-- name: GetLoginSeen :one
+SELECT
+    EXISTS (SELECT 1 FROM login_history h WHERE h.user_id = $1) AS has_history,
+    EXISTS (SELECT 1 FROM login_history h WHERE h.user_id = $1 AND h.device_fingerprint = $2) AS device_seen,
+    EXISTS (SELECT 1 FROM login_history h WHERE h.user_id = $1 AND h.ip = $3) AS ip_seen;
+
+-- name: ListLoginHistory :many
+SELECT id, user_id, "method", ip, user_agent, device_name, device_fingerprint, created_at
+FROM login_history
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.arg(before_id)::bigint = 0 OR id < sqlc.arg(before_id))
+ORDER BY id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: MoveLoginHistory :execrows
+UPDATE login_history
+SET user_id = sqlc.arg(to_user_id)
+WHERE user_id = sqlc.arg(from_user_id);
@@ -0,0 +1,51 @@
+-- name: InsertOutboxEvent :exec
+INSERT INTO outbox (
+    event_type,
+    aggregate_id,
+    payload
+) VALUES (
+    $1, $2, $3
+);
+
+-- name: ClaimOutboxEvents :many
+WITH claimed AS (
+    UPDATE outbox
+    SET locked_until = now() + make_interval(secs => sqlc.arg(lease_sec)::int),
+        attempts = attempts + 1
+    WHERE id IN (
+        SELECT o.id
+        FROM outbox o
+        WHERE o.published_at IS NULL
+          AND o.dead_at IS NULL
+          AND o.next_attempt_at <= now()
+          AND (o.locked_until IS NULL OR o.locked_until < now())
+        ORDER BY o.id
+        LIMIT sqlc.arg(batch_size)
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING id, event_type, aggregate_id, payload, attempts, created_at
+)
+SELECT id, event_type, aggregate_id, payload, attempts, created_at
+FROM claimed
+ORDER BY id;
+
+-- name: MarkOutboxPublished :exec
+UPDATE outbox
+SET published_at = now(),
+    locked_until = NULL,
+    last_error = NULL
+WHERE id = $1;
+
+-- name: MarkOutboxFailed :exec
+UPDATE outbox
+SET locked_until = NULL,
+    last_error = sqlc.arg(last_error),
+    next_attempt_at = sqlc.arg(next_attempt_at)
+WHERE id = sqlc.arg(id);
+
+-- name: MarkOutboxDead :exec
+UPDATE outbox
+SET locked_until = NULL,
+    last_error = sqlc.arg(last_error),
+    dead_at = now()
+WHERE id = sqlc.arg(id);
@@ -0,0 +1,58 @@
+-- name: CreateSession :exec
+INSERT INTO user_sessions (
+    id,
+    user_id,
+    expires_at,
+    refresh_token_hash
+) VALUES (
+    $1, $2, $3, $4
+);
+
+-- name: GetSessionState :one
+SELECT s.user_id, s.expires_at, s.revoked_at, u.blocked_at
+FROM user_sessions s
+JOIN users u ON u.id = s.user_id
+WHERE s.id = $1
+LIMIT 1;
+
+-- name: ListSessionsByUser :many
+SELECT id, user_id, expires_at, revoked_at, created_at
+FROM user_sessions
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: RevokeUserSessions :execrows
+UPDATE user_sessions
+SET revoked_at = now()
+WHERE user_id = $1
+  AND revoked_at IS NULL;
+
+-- name: MoveSessions :execrows
+UPDATE user_sessions
+SET user_id = sqlc.arg(to_user_id)
+WHERE user_id = sqlc.arg(from_user_id);
+
+-- name: GetSessionByRefreshHash :one
+-- is_current = false: предъявлен уже ротированный refresh token.
+SELECT s.id, s.user_id, s.expires_at, s.revoked_at, u.blocked_at,
+       (s.refresh_token_hash = sqlc.arg(hash)::bytea)::boolean AS is_current
+FROM user_sessions s
+JOIN users u ON u.id = s.user_id
+WHERE s.refresh_token_hash = sqlc.arg(hash)
+   OR s.prev_refresh_token_hash = sqlc.arg(hash)
+LIMIT 1;
+
+-- name: RotateSessionRefresh :execrows
+UPDATE user_sessions
+SET prev_refresh_token_hash = refresh_token_hash,
+    refresh_token_hash = sqlc.arg(new_hash)
+WHERE id = sqlc.arg(id)
+  AND refresh_token_hash = sqlc.arg(old_hash)
+  AND revoked_at IS NULL;
+
+-- name: RevokeSession :execrows
+UPDATE user_sessions
+SET revoked_at = now()
+WHERE id = $1
+  AND revoked_at IS NULL;
@@ -1,13 +1,67 @@
 -- name: GetUserByEmail :one
-SELECT id, hash_password
+SELECT id, email, hash_password, role, blocked_at
 FROM users
-WHERE email = $1
+WHERE email_normalized = $1
 LIMIT 1;
 
 -- name: CreateUser :one
 INSERT INTO users (
     email,
+    email_normalized,
     hash_password
 ) VALUES (
-    $1, $2
+    $1, $2, $3
 ) RETURNING id;
+
+-- name: GetUserByID :one
+SELECT id, email, hash_password, role, blocked_at, created_at
+FROM users
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListUsers :many
+SELECT id, email, role, blocked_at, created_at
+FROM users
+WHERE id > sqlc.arg(after_id)
+  AND (sqlc.narg(email_pattern)::text IS NULL OR email ILIKE sqlc.narg(email_pattern))
+ORDER BY id
+LIMIT sqlc.arg(page_size);
+
+-- name: BlockUser :execrows
+UPDATE users
+SET blocked_at = COALESCE(blocked_at, now()),
+    updated_at = now()
+WHERE id = $1;
+
+-- name: UnblockUser :execrows
+UPDATE users
+SET blocked_at = NULL,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: ResetUserMfa :execrows
+UPDATE users
+SET mfa_secret = NULL,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: DeleteUser :execrows
+DELETE FROM users
+WHERE id = $1;
+
+-- name: UpdateUserPassword :execrows
+UPDATE users
+SET hash_password = $2,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: ListUserEmails :many
+SELECT id, email, email_normalized
+FROM users
+ORDER BY id;
+
+-- name: SetUserEmailNormalized :exec
+UPDATE users
+SET email_normalized = $2,
+    updated_at = now()
+WHERE id = $1;
@@ -0,0 +1,85 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (
+    url,
+    event_types,
+    secret
+) VALUES (
+    $1, $2, $3
+) RETURNING id, created_at;
+
+-- name: ListWebhooks :many
+SELECT id, url, event_types, secret, created_at
+FROM webhooks
+ORDER BY id;
+
+-- name: DeleteWebhook :execrows
+DELETE FROM webhooks
+WHERE id = $1;
+
+-- name: EnqueueWebhookDelivery :exec
+INSERT INTO webhook_deliveries (
+    webhook_id,
+    event_id,
+    event_type,
+    payload
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (webhook_id, event_id) DO NOTHING;
+
+-- name: ClaimWebhookDeliveries :many
+WITH claimed AS (
+    UPDATE webhook_deliveries
+    SET locked_until = now() + make_interval(secs => sqlc.arg(lease_sec)::int),
+        attempts = attempts + 1
+    WHERE id IN (
+        SELECT d.id
+        FROM webhook_deliveries d
+        WHERE d.status = 'pending'
+          AND d.next_attempt_at <= now()
+          AND (d.locked_until IS NULL OR d.locked_until < now())
+        ORDER BY d.id
+        LIMIT sqlc.arg(batch_size)
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING id, webhook_id, event_id, event_type, payload, attempts, created_at
+)
+SELECT c.id, c.webhook_id, c.event_id, c.event_type, c.payload, c.attempts, c.created_at,
+       w.url, w.secret
+FROM claimed c
+JOIN webhooks w ON w.id = c.webhook_id
+ORDER BY c.id;
+
+-- name: MarkWebhookDelivered :exec
+UPDATE webhook_deliveries
+SET status = 'delivered',
+    delivered_at = now(),
+    locked_until = NULL,
+    last_status_code = sqlc.arg(status_code),
+    last_error = NULL
+WHERE id = sqlc.arg(id);
+
+-- name: MarkWebhookDeliveryFailed :exec
+UPDATE webhook_deliveries
+SET locked_until = NULL,
+    last_status_code = sqlc.narg(status_code),
+    last_error = sqlc.arg(last_error),
+    next_attempt_at = sqlc.arg(next_attempt_at)
+WHERE id = sqlc.arg(id);
+
+-- name: MarkWebhookDeliveryDead :exec
+UPDATE webhook_deliveries
+SET status = 'failed',
+    locked_until = NULL,
+    last_status_code = sqlc.narg(status_code),
+    last_error = sqlc.arg(last_error)
+WHERE id = sqlc.arg(id);
+
+-- name: ListWebhookDeliveries :many
+SELECT id, webhook_id, event_id, event_type, status, attempts, next_attempt_at,
+       last_status_code, last_error, delivered_at, created_at
+FROM webhook_deliveries
+WHERE (sqlc.arg(webhook_id)::bigint = 0 OR webhook_id = sqlc.arg(webhook_id))
+  AND (sqlc.arg(before_id)::bigint = 0 OR id < sqlc.arg(before_id))
+ORDER BY id DESC
+LIMIT sqlc.arg(page_size);
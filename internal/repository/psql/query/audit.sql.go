@@ -0,0 +1,110 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: audit.sql
+
+package query
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const insertAuditEvent = `-- name: InsertAuditEvent :exec
+INSERT INTO audit_events (
+    event_type,
+    actor_user_id,
+    actor_bot_id,
+    target_user_id,
+    ip,
+    user_agent,
+    request_id,
+    details
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+`
+
+type InsertAuditEventParams struct {
+	EventType    string
+	ActorUserID  pgtype.Int4
+	ActorBotID   pgtype.Text
+	TargetUserID pgtype.Int4
+	Ip           pgtype.Text
+	UserAgent    pgtype.Text
+	RequestID    pgtype.Text
+	Details      []byte
+}
+
+func (q *Queries) InsertAuditEvent(ctx context.Context, arg InsertAuditEventParams) error {
+	_, err := q.db.Exec(ctx, insertAuditEvent,
+		arg.EventType,
+		arg.ActorUserID,
+		arg.ActorBotID,
+		arg.TargetUserID,
+		arg.Ip,
+		arg.UserAgent,
+		arg.RequestID,
+		arg.Details,
+	)
+	return err
+}
+
+const listAuditEvents = `-- name: ListAuditEvents :many
+SELECT id, event_type, actor_user_id, actor_bot_id, target_user_id, ip, user_agent, request_id, details, occurred_at
+FROM audit_events
+WHERE ($1::integer IS NULL
+       OR actor_user_id = $1
+       OR target_user_id = $1)
+  AND ($2::timestamptz IS NULL OR occurred_at >= $2)
+  AND ($3::timestamptz IS NULL OR occurred_at < $3)
+  AND ($4::bigint = 0 OR id < $4)
+ORDER BY id DESC
+LIMIT $5
+`
+
+type ListAuditEventsParams struct {
+	UserID   pgtype.Int4
+	FromTime pgtype.Timestamptz
+	ToTime   pgtype.Timestamptz
+	BeforeID int64
+	PageSize int32
+}
+
+func (q *Queries) ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error) {
+	rows, err := q.db.Query(ctx, listAuditEvents,
+		arg.UserID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.BeforeID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditEvent
+	for rows.Next() {
+		var i AuditEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventType,
+			&i.ActorUserID,
+			&i.ActorBotID,
+			&i.TargetUserID,
+			&i.Ip,
+			&i.UserAgent,
+			&i.RequestID,
+			&i.Details,
+			&i.OccurredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
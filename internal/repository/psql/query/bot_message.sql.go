@@ -0,0 +1,82 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: bot_message.sql
+
+package query
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimBotMessages = `-- name: ClaimBotMessages :many
+WITH claimed AS (
+    UPDATE bot_messages
+    SET delivered_at = now()
+    WHERE id IN (
+        SELECT m.id
+        FROM bot_messages m
+        WHERE m.delivered_at IS NULL
+        ORDER BY m.id
+        LIMIT $1
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING id, chat_id, text, created_at
+)
+SELECT id, chat_id, text, created_at
+FROM claimed
+ORDER BY id
+`
+
+type ClaimBotMessagesRow struct {
+	ID        int64
+	ChatID    int64
+	Text      string
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) ClaimBotMessages(ctx context.Context, pageSize int32) ([]ClaimBotMessagesRow, error) {
+	rows, err := q.db.Query(ctx, claimBotMessages, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimBotMessagesRow
+	for rows.Next() {
+		var i ClaimBotMessagesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ChatID,
+			&i.Text,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const enqueueBotMessage = `-- name: EnqueueBotMessage :exec
+INSERT INTO bot_messages (
+    chat_id,
+    text
+) VALUES (
+    $1, $2
+)
+`
+
+type EnqueueBotMessageParams struct {
+	ChatID int64
+	Text   string
+}
+
+func (q *Queries) EnqueueBotMessage(ctx context.Context, arg EnqueueBotMessageParams) error {
+	_, err := q.db.Exec(ctx, enqueueBotMessage, arg.ChatID, arg.Text)
+	return err
+}
@@ -0,0 +1,37 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: bot_nonce.sql
+
+package query
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const useBotNonce = `-- name: UseBotNonce :execrows
+INSERT INTO bot_nonces (
+    bot_id,
+    nonce,
+    expires_at
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (bot_id, nonce) DO NOTHING
+`
+
+type UseBotNonceParams struct {
+	BotID     string
+	Nonce     string
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) UseBotNonce(ctx context.Context, arg UseBotNonceParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useBotNonce, arg.BotID, arg.Nonce, arg.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
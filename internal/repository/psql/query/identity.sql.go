@@ -0,0 +1,169 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: identity.sql
+
+package query
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createIdentity = `-- name: CreateIdentity :one
+INSERT INTO user_identities (
+    user_id,
+    provider,
+    provider_user_id,
+    username,
+    first_name,
+    last_name,
+    chat_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING id
+`
+
+type CreateIdentityParams struct {
+	UserID         int32
+	Provider       string
+	ProviderUserID string
+	Username       pgtype.Text
+	FirstName      pgtype.Text
+	LastName       pgtype.Text
+	ChatID         pgtype.Int8
+}
+
+func (q *Queries) CreateIdentity(ctx context.Context, arg CreateIdentityParams) (int64, error) {
+	row := q.db.QueryRow(ctx, createIdentity,
+		arg.UserID,
+		arg.Provider,
+		arg.ProviderUserID,
+		arg.Username,
+		arg.FirstName,
+		arg.LastName,
+		arg.ChatID,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getIdentityByProvider = `-- name: GetIdentityByProvider :one
+SELECT id, user_id, provider, provider_user_id, username, first_name, last_name, chat_id, created_at, updated_at
+FROM user_identities
+WHERE provider = $1
+  AND provider_user_id = $2
+LIMIT 1
+`
+
+type GetIdentityByProviderParams struct {
+	Provider       string
+	ProviderUserID string
+}
+
+func (q *Queries) GetIdentityByProvider(ctx context.Context, arg GetIdentityByProviderParams) (UserIdentity, error) {
+	row := q.db.QueryRow(ctx, getIdentityByProvider, arg.Provider, arg.ProviderUserID)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Provider,
+		&i.ProviderUserID,
+		&i.Username,
+		&i.FirstName,
+		&i.LastName,
+		&i.ChatID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listIdentitiesByUser = `-- name: ListIdentitiesByUser :many
+SELECT id, user_id, provider, provider_user_id, username, first_name, last_name, chat_id, created_at, updated_at
+FROM user_identities
+WHERE user_id = $1
+ORDER BY id
+`
+
+func (q *Queries) ListIdentitiesByUser(ctx context.Context, userID int32) ([]UserIdentity, error) {
+	rows, err := q.db.Query(ctx, listIdentitiesByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserIdentity
+	for rows.Next() {
+		var i UserIdentity
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Provider,
+			&i.ProviderUserID,
+			&i.Username,
+			&i.FirstName,
+			&i.LastName,
+			&i.ChatID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveIdentities = `-- name: MoveIdentities :execrows
+UPDATE user_identities
+SET user_id = $1,
+    updated_at = now()
+WHERE user_id = $2
+`
+
+type MoveIdentitiesParams struct {
+	ToUserID   int32
+	FromUserID int32
+}
+
+func (q *Queries) MoveIdentities(ctx context.Context, arg MoveIdentitiesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, moveIdentities, arg.ToUserID, arg.FromUserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateIdentityProfile = `-- name: UpdateIdentityProfile :exec
+UPDATE user_identities
+SET username = $2,
+    first_name = $3,
+    last_name = $4,
+    chat_id = $5,
+    updated_at = now()
+WHERE id = $1
+`
+
+type UpdateIdentityProfileParams struct {
+	ID        int64
+	Username  pgtype.Text
+	FirstName pgtype.Text
+	LastName  pgtype.Text
+	ChatID    pgtype.Int8
+}
+
+func (q *Queries) UpdateIdentityProfile(ctx context.Context, arg UpdateIdentityProfileParams) error {
+	_, err := q.db.Exec(ctx, updateIdentityProfile,
+		arg.ID,
+		arg.Username,
+		arg.FirstName,
+		arg.LastName,
+		arg.ChatID,
+	)
+	return err
+}
@@ -0,0 +1,200 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: janitor.sql
+
+package query
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const advisoryUnlock = `-- name: AdvisoryUnlock :one
+SELECT pg_advisory_unlock($1::bigint)::boolean AS released
+`
+
+func (q *Queries) AdvisoryUnlock(ctx context.Context, key int64) (bool, error) {
+	row := q.db.QueryRow(ctx, advisoryUnlock, key)
+	var released bool
+	err := row.Scan(&released)
+	return released, err
+}
+
+const claimJobRun = `-- name: ClaimJobRun :execrows
+INSERT INTO scheduler_job_runs (name, last_run_at)
+VALUES ($1, now())
+ON CONFLICT (name) DO UPDATE SET last_run_at = EXCLUDED.last_run_at
+WHERE scheduler_job_runs.last_run_at <= now() - $2::bigint * interval '1 millisecond'
+`
+
+type ClaimJobRunParams struct {
+	Name     string
+	MinGapMs int64
+}
+
+// 0 строк — job уже запускался позже, чем min_gap назад (время — часы базы, общие для реплик).
+func (q *Queries) ClaimJobRun(ctx context.Context, arg ClaimJobRunParams) (int64, error) {
+	result, err := q.db.Exec(ctx, claimJobRun, arg.Name, arg.MinGapMs)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeDeliveredBotMessages = `-- name: PurgeDeliveredBotMessages :execrows
+DELETE FROM bot_messages
+WHERE id IN (
+    SELECT m.id
+    FROM bot_messages m
+    WHERE m.delivered_at < $1
+    ORDER BY m.id
+    LIMIT $2
+)
+`
+
+type PurgeDeliveredBotMessagesParams struct {
+	Before    pgtype.Timestamptz
+	BatchSize int32
+}
+
+func (q *Queries) PurgeDeliveredBotMessages(ctx context.Context, arg PurgeDeliveredBotMessagesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeliveredBotMessages, arg.Before, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeDeliveredWebhookDeliveries = `-- name: PurgeDeliveredWebhookDeliveries :execrows
+DELETE FROM webhook_deliveries
+WHERE id IN (
+    SELECT d.id
+    FROM webhook_deliveries d
+    WHERE d.status = 'delivered'
+      AND d.delivered_at < $1
+    ORDER BY d.id
+    LIMIT $2
+)
+`
+
+type PurgeDeliveredWebhookDeliveriesParams struct {
+	Before    pgtype.Timestamptz
+	BatchSize int32
+}
+
+func (q *Queries) PurgeDeliveredWebhookDeliveries(ctx context.Context, arg PurgeDeliveredWebhookDeliveriesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeliveredWebhookDeliveries, arg.Before, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeExpiredBotNonces = `-- name: PurgeExpiredBotNonces :execrows
+DELETE FROM bot_nonces
+WHERE (bot_id, nonce) IN (
+    SELECT n.bot_id, n.nonce
+    FROM bot_nonces n
+    WHERE n.expires_at < $1
+    ORDER BY n.expires_at
+    LIMIT $2
+)
+`
+
+type PurgeExpiredBotNoncesParams struct {
+	Before    pgtype.Timestamptz
+	BatchSize int32
+}
+
+func (q *Queries) PurgeExpiredBotNonces(ctx context.Context, arg PurgeExpiredBotNoncesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeExpiredBotNonces, arg.Before, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeExpiredLinkCodes = `-- name: PurgeExpiredLinkCodes :execrows
+DELETE FROM telegram_link_codes
+WHERE code IN (
+    SELECT c.code
+    FROM telegram_link_codes c
+    WHERE c.expires_at < $1
+    ORDER BY c.expires_at
+    LIMIT $2
+)
+`
+
+type PurgeExpiredLinkCodesParams struct {
+	Before    pgtype.Timestamptz
+	BatchSize int32
+}
+
+func (q *Queries) PurgeExpiredLinkCodes(ctx context.Context, arg PurgeExpiredLinkCodesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeExpiredLinkCodes, arg.Before, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeExpiredSessions = `-- name: PurgeExpiredSessions :execrows
+DELETE FROM user_sessions
+WHERE id IN (
+    SELECT s.id
+    FROM user_sessions s
+    WHERE s.expires_at < $1
+    ORDER BY s.expires_at
+    LIMIT $2
+)
+`
+
+type PurgeExpiredSessionsParams struct {
+	Before    pgtype.Timestamptz
+	BatchSize int32
+}
+
+func (q *Queries) PurgeExpiredSessions(ctx context.Context, arg PurgeExpiredSessionsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeExpiredSessions, arg.Before, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgePublishedOutbox = `-- name: PurgePublishedOutbox :execrows
+DELETE FROM outbox
+WHERE id IN (
+    SELECT o.id
+    FROM outbox o
+    WHERE o.published_at < $1
+    ORDER BY o.id
+    LIMIT $2
+)
+`
+
+type PurgePublishedOutboxParams struct {
+	Before    pgtype.Timestamptz
+	BatchSize int32
+}
+
+func (q *Queries) PurgePublishedOutbox(ctx context.Context, arg PurgePublishedOutboxParams) (int64, error) {
+	result, err := q.db.Exec(ctx, purgePublishedOutbox, arg.Before, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const tryAdvisoryLock = `-- name: TryAdvisoryLock :one
+SELECT pg_try_advisory_lock($1::bigint)::boolean AS acquired
+`
+
+func (q *Queries) TryAdvisoryLock(ctx context.Context, key int64) (bool, error) {
+	row := q.db.QueryRow(ctx, tryAdvisoryLock, key)
+	var acquired bool
+	err := row.Scan(&acquired)
+	return acquired, err
+}
@@ -0,0 +1,69 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: link_code.sql
+
+package query
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createLinkCode = `-- name: CreateLinkCode :exec
+INSERT INTO telegram_link_codes (
+    code,
+    user_id,
+    expires_at
+) VALUES (
+    $1, $2, $3
+)
+`
+
+type CreateLinkCodeParams struct {
+	Code      string
+	UserID    int32
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateLinkCode(ctx context.Context, arg CreateLinkCodeParams) error {
+	_, err := q.db.Exec(ctx, createLinkCode, arg.Code, arg.UserID, arg.ExpiresAt)
+	return err
+}
+
+const getLinkCode = `-- name: GetLinkCode :one
+SELECT code, user_id, expires_at, used_at, created_at
+FROM telegram_link_codes
+WHERE code = $1
+LIMIT 1
+`
+
+func (q *Queries) GetLinkCode(ctx context.Context, code string) (TelegramLinkCode, error) {
+	row := q.db.QueryRow(ctx, getLinkCode, code)
+	var i TelegramLinkCode
+	err := row.Scan(
+		&i.Code,
+		&i.UserID,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const useLinkCode = `-- name: UseLinkCode :one
+UPDATE telegram_link_codes
+SET used_at = now()
+WHERE code = $1
+  AND used_at IS NULL
+  AND expires_at > now()
+RETURNING user_id
+`
+
+func (q *Queries) UseLinkCode(ctx context.Context, code string) (int32, error) {
+	row := q.db.QueryRow(ctx, useLinkCode, code)
+	var user_id int32
+	err := row.Scan(&user_id)
+	return user_id, err
+}
@@ -0,0 +1,135 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: login_history.sql
+
+package query
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const getLoginSeen = `-- name: GetLoginSeen :one
+SELECT
+    EXISTS (SELECT 1 FROM login_history h WHERE h.user_id = $1) AS has_history,
+    EXISTS (SELECT 1 FROM login_history h WHERE h.user_id = $1 AND h.device_fingerprint = $2) AS device_seen,
+    EXISTS (SELECT 1 FROM login_history h WHERE h.user_id = $1 AND h.ip = $3) AS ip_seen
+`
+
+type GetLoginSeenParams struct {
+	UserID            int32
+	DeviceFingerprint string
+	Ip                pgtype.Text
+}
+
+type GetLoginSeenRow struct {
+	HasHistory bool
+	DeviceSeen bool
+	IpSeen     bool
+}
+
+func (q *Queries) GetLoginSeen(ctx context.Context, arg GetLoginSeenParams) (GetLoginSeenRow, error) {
+	row := q.db.QueryRow(ctx, getLoginSeen, arg.UserID, arg.DeviceFingerprint, arg.Ip)
+	var i GetLoginSeenRow
+	err := row.Scan(&i.HasHistory, &i.DeviceSeen, &i.IpSeen)
+	return i, err
+}
+
+const insertLoginHistory = `-- name: InsertLoginHistory :exec
+INSERT INTO login_history (
+    user_id,
+    "method",
+    ip,
+    user_agent,
+    device_name,
+    device_fingerprint
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+`
+
+type InsertLoginHistoryParams struct {
+	UserID            int32
+	Method            string
+	Ip                pgtype.Text
+	UserAgent         pgtype.Text
+	DeviceName        string
+	DeviceFingerprint string
+}
+
+func (q *Queries) InsertLoginHistory(ctx context.Context, arg InsertLoginHistoryParams) error {
+	_, err := q.db.Exec(ctx, insertLoginHistory,
+		arg.UserID,
+		arg.Method,
+		arg.Ip,
+		arg.UserAgent,
+		arg.DeviceName,
+		arg.DeviceFingerprint,
+	)
+	return err
+}
+
+const listLoginHistory = `-- name: ListLoginHistory :many
+SELECT id, user_id, "method", ip, user_agent, device_name, device_fingerprint, created_at
+FROM login_history
+WHERE user_id = $1
+  AND ($2::bigint = 0 OR id < $2)
+ORDER BY id DESC
+LIMIT $3
+`
+
+type ListLoginHistoryParams struct {
+	UserID   int32
+	BeforeID int64
+	PageSize int32
+}
+
+func (q *Queries) ListLoginHistory(ctx context.Context, arg ListLoginHistoryParams) ([]LoginHistory, error) {
+	rows, err := q.db.Query(ctx, listLoginHistory, arg.UserID, arg.BeforeID, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LoginHistory
+	for rows.Next() {
+		var i LoginHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Method,
+			&i.Ip,
+			&i.UserAgent,
+			&i.DeviceName,
+			&i.DeviceFingerprint,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveLoginHistory = `-- name: MoveLoginHistory :execrows
+UPDATE login_history
+SET user_id = $1
+WHERE user_id = $2
+`
+
+type MoveLoginHistoryParams struct {
+	ToUserID   int32
+	FromUserID int32
+}
+
+func (q *Queries) MoveLoginHistory(ctx context.Context, arg MoveLoginHistoryParams) (int64, error) {
+	result, err := q.db.Exec(ctx, moveLoginHistory, arg.ToUserID, arg.FromUserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
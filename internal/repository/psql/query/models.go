@@ -8,6 +8,63 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AuditEvent struct {
+	ID           int64
+	EventType    string
+	ActorUserID  pgtype.Int4
+	ActorBotID   pgtype.Text
+	TargetUserID pgtype.Int4
+	Ip           pgtype.Text
+	UserAgent    pgtype.Text
+	RequestID    pgtype.Text
+	Details      []byte
+	OccurredAt   pgtype.Timestamptz
+}
+
+type BotMessage struct {
+	ID          int64
+	ChatID      int64
+	Text        string
+	CreatedAt   pgtype.Timestamptz
+	DeliveredAt pgtype.Timestamptz
+}
+
+type BotNonce struct {
+	BotID     string
+	Nonce     string
+	ExpiresAt pgtype.Timestamptz
+}
+
+type LoginHistory struct {
+	ID                int64
+	UserID            int32
+	Method            string
+	Ip                pgtype.Text
+	UserAgent         pgtype.Text
+	DeviceName        string
+	DeviceFingerprint string
+	CreatedAt         pgtype.Timestamptz
+}
+
+type Outbox struct {
+	ID            int64
+	EventType     string
+	AggregateID   string
+	Payload       []byte
+	Attempts      int32
+	NextAttemptAt pgtype.Timestamptz
+	LockedUntil   pgtype.Timestamptz
+	LastError     pgtype.Text
+	PublishedAt   pgtype.Timestamptz
+	DeadAt        pgtype.Timestamptz
+	CreatedAt     pgtype.Timestamptz
+}
+
+type SchedulerJobRun struct {
+	Name      string
+	LastRunAt pgtype.Timestamptz
+}
+
 type TelegramLinkCode struct {
 	Code      string
 	UserID    int32
@@ -17,11 +74,15 @@ type TelegramLinkCode struct {
 }
 
 type User struct {
-	ID           int32
-	Email        string
-	HashPassword string
-	CreatedAt    pgtype.Timestamptz
-	UpdatedAt    pgtype.Timestamptz
+	ID              int32
+	Email           string
+	HashPassword    string
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+	Role            string
+	BlockedAt       pgtype.Timestamptz
+	MfaSecret       pgtype.Text
+	EmailNormalized string
 }
 
 type UserIdentity struct {
@@ -36,3 +97,37 @@ type UserIdentity struct {
 	CreatedAt      pgtype.Timestamptz
 	UpdatedAt      pgtype.Timestamptz
 }
+
+type UserSession struct {
+	ID                   string
+	UserID               int32
+	ExpiresAt            pgtype.Timestamptz
+	RevokedAt            pgtype.Timestamptz
+	CreatedAt            pgtype.Timestamptz
+	RefreshTokenHash     []byte
+	PrevRefreshTokenHash []byte
+}
+
+type Webhook struct {
+	ID         int64
+	Url        string
+	EventTypes []string
+	Secret     string
+	CreatedAt  pgtype.Timestamptz
+}
+
+type WebhookDelivery struct {
+	ID             int64
+	WebhookID      int64
+	EventID        int64
+	EventType      string
+	Payload        []byte
+	Status         string
+	Attempts       int32
+	NextAttemptAt  pgtype.Timestamptz
+	LockedUntil    pgtype.Timestamptz
+	LastStatusCode pgtype.Int4
+	LastError      pgtype.Text
+	DeliveredAt    pgtype.Timestamptz
+	CreatedAt      pgtype.Timestamptz
+}
@@ -0,0 +1,147 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: outbox.sql
+
+package query
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimOutboxEvents = `-- name: ClaimOutboxEvents :many
+WITH claimed AS (
+    UPDATE outbox
+    SET locked_until = now() + make_interval(secs => $1::int),
+        attempts = attempts + 1
+    WHERE id IN (
+        SELECT o.id
+        FROM outbox o
+        WHERE o.published_at IS NULL
+          AND o.dead_at IS NULL
+          AND o.next_attempt_at <= now()
+          AND (o.locked_until IS NULL OR o.locked_until < now())
+        ORDER BY o.id
+        LIMIT $2
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING id, event_type, aggregate_id, payload, attempts, created_at
+)
+SELECT id, event_type, aggregate_id, payload, attempts, created_at
+FROM claimed
+ORDER BY id
+`
+
+type ClaimOutboxEventsParams struct {
+	LeaseSec  int32
+	BatchSize int32
+}
+
+type ClaimOutboxEventsRow struct {
+	ID          int64
+	EventType   string
+	AggregateID string
+	Payload     []byte
+	Attempts    int32
+	CreatedAt   pgtype.Timestamptz
+}
+
+func (q *Queries) ClaimOutboxEvents(ctx context.Context, arg ClaimOutboxEventsParams) ([]ClaimOutboxEventsRow, error) {
+	rows, err := q.db.Query(ctx, claimOutboxEvents, arg.LeaseSec, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimOutboxEventsRow
+	for rows.Next() {
+		var i ClaimOutboxEventsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventType,
+			&i.AggregateID,
+			&i.Payload,
+			&i.Attempts,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertOutboxEvent = `-- name: InsertOutboxEvent :exec
+INSERT INTO outbox (
+    event_type,
+    aggregate_id,
+    payload
+) VALUES (
+    $1, $2, $3
+)
+`
+
+type InsertOutboxEventParams struct {
+	EventType   string
+	AggregateID string
+	Payload     []byte
+}
+
+func (q *Queries) InsertOutboxEvent(ctx context.Context, arg InsertOutboxEventParams) error {
+	_, err := q.db.Exec(ctx, insertOutboxEvent, arg.EventType, arg.AggregateID, arg.Payload)
+	return err
+}
+
+const markOutboxDead = `-- name: MarkOutboxDead :exec
+UPDATE outbox
+SET locked_until = NULL,
+    last_error = $1,
+    dead_at = now()
+WHERE id = $2
+`
+
+type MarkOutboxDeadParams struct {
+	LastError pgtype.Text
+	ID        int64
+}
+
+func (q *Queries) MarkOutboxDead(ctx context.Context, arg MarkOutboxDeadParams) error {
+	_, err := q.db.Exec(ctx, markOutboxDead, arg.LastError, arg.ID)
+	return err
+}
+
+const markOutboxFailed = `-- name: MarkOutboxFailed :exec
+UPDATE outbox
+SET locked_until = NULL,
+    last_error = $1,
+    next_attempt_at = $2
+WHERE id = $3
+`
+
+type MarkOutboxFailedParams struct {
+	LastError     pgtype.Text
+	NextAttemptAt pgtype.Timestamptz
+	ID            int64
+}
+
+func (q *Queries) MarkOutboxFailed(ctx context.Context, arg MarkOutboxFailedParams) error {
+	_, err := q.db.Exec(ctx, markOutboxFailed, arg.LastError, arg.NextAttemptAt, arg.ID)
+	return err
+}
+
+const markOutboxPublished = `-- name: MarkOutboxPublished :exec
+UPDATE outbox
+SET published_at = now(),
+    locked_until = NULL,
+    last_error = NULL
+WHERE id = $1
+`
+
+func (q *Queries) MarkOutboxPublished(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, markOutboxPublished, id)
+	return err
+}
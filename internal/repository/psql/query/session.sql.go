@@ -0,0 +1,220 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: session.sql
+
+package query
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createSession = `-- name: CreateSession :exec
+INSERT INTO user_sessions (
+    id,
+    user_id,
+    expires_at,
+    refresh_token_hash
+) VALUES (
+    $1, $2, $3, $4
+)
+`
+
+type CreateSessionParams struct {
+	ID               string
+	UserID           int32
+	ExpiresAt        pgtype.Timestamptz
+	RefreshTokenHash []byte
+}
+
+func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) error {
+	_, err := q.db.Exec(ctx, createSession,
+		arg.ID,
+		arg.UserID,
+		arg.ExpiresAt,
+		arg.RefreshTokenHash,
+	)
+	return err
+}
+
+const getSessionByRefreshHash = `-- name: GetSessionByRefreshHash :one
+SELECT s.id, s.user_id, s.expires_at, s.revoked_at, u.blocked_at,
+       (s.refresh_token_hash = $1::bytea)::boolean AS is_current
+FROM user_sessions s
+JOIN users u ON u.id = s.user_id
+WHERE s.refresh_token_hash = $1
+   OR s.prev_refresh_token_hash = $1
+LIMIT 1
+`
+
+type GetSessionByRefreshHashRow struct {
+	ID        string
+	UserID    int32
+	ExpiresAt pgtype.Timestamptz
+	RevokedAt pgtype.Timestamptz
+	BlockedAt pgtype.Timestamptz
+	IsCurrent bool
+}
+
+// is_current = false: предъявлен уже ротированный refresh token.
+func (q *Queries) GetSessionByRefreshHash(ctx context.Context, hash []byte) (GetSessionByRefreshHashRow, error) {
+	row := q.db.QueryRow(ctx, getSessionByRefreshHash, hash)
+	var i GetSessionByRefreshHashRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.BlockedAt,
+		&i.IsCurrent,
+	)
+	return i, err
+}
+
+const getSessionState = `-- name: GetSessionState :one
+SELECT s.user_id, s.expires_at, s.revoked_at, u.blocked_at
+FROM user_sessions s
+JOIN users u ON u.id = s.user_id
+WHERE s.id = $1
+LIMIT 1
+`
+
+type GetSessionStateRow struct {
+	UserID    int32
+	ExpiresAt pgtype.Timestamptz
+	RevokedAt pgtype.Timestamptz
+	BlockedAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetSessionState(ctx context.Context, id string) (GetSessionStateRow, error) {
+	row := q.db.QueryRow(ctx, getSessionState, id)
+	var i GetSessionStateRow
+	err := row.Scan(
+		&i.UserID,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.BlockedAt,
+	)
+	return i, err
+}
+
+const listSessionsByUser = `-- name: ListSessionsByUser :many
+SELECT id, user_id, expires_at, revoked_at, created_at
+FROM user_sessions
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2
+`
+
+type ListSessionsByUserParams struct {
+	UserID   int32
+	PageSize int32
+}
+
+type ListSessionsByUserRow struct {
+	ID        string
+	UserID    int32
+	ExpiresAt pgtype.Timestamptz
+	RevokedAt pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) ListSessionsByUser(ctx context.Context, arg ListSessionsByUserParams) ([]ListSessionsByUserRow, error) {
+	rows, err := q.db.Query(ctx, listSessionsByUser, arg.UserID, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSessionsByUserRow
+	for rows.Next() {
+		var i ListSessionsByUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ExpiresAt,
+			&i.RevokedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveSessions = `-- name: MoveSessions :execrows
+UPDATE user_sessions
+SET user_id = $1
+WHERE user_id = $2
+`
+
+type MoveSessionsParams struct {
+	ToUserID   int32
+	FromUserID int32
+}
+
+func (q *Queries) MoveSessions(ctx context.Context, arg MoveSessionsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, moveSessions, arg.ToUserID, arg.FromUserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeSession = `-- name: RevokeSession :execrows
+UPDATE user_sessions
+SET revoked_at = now()
+WHERE id = $1
+  AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeSession(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeSession, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeUserSessions = `-- name: RevokeUserSessions :execrows
+UPDATE user_sessions
+SET revoked_at = now()
+WHERE user_id = $1
+  AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeUserSessions(ctx context.Context, userID int32) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeUserSessions, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const rotateSessionRefresh = `-- name: RotateSessionRefresh :execrows
+UPDATE user_sessions
+SET prev_refresh_token_hash = refresh_token_hash,
+    refresh_token_hash = $1
+WHERE id = $2
+  AND refresh_token_hash = $3
+  AND revoked_at IS NULL
+`
+
+type RotateSessionRefreshParams struct {
+	NewHash []byte
+	ID      string
+	OldHash []byte
+}
+
+func (q *Queries) RotateSessionRefresh(ctx context.Context, arg RotateSessionRefreshParams) (int64, error) {
+	result, err := q.db.Exec(ctx, rotateSessionRefresh, arg.NewHash, arg.ID, arg.OldHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
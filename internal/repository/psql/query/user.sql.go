@@ -7,44 +7,263 @@ package query
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const blockUser = `-- name: BlockUser :execrows
+UPDATE users
+SET blocked_at = COALESCE(blocked_at, now()),
+    updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) BlockUser(ctx context.Context, id int32) (int64, error) {
+	result, err := q.db.Exec(ctx, blockUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
     email,
+    email_normalized,
     hash_password
 ) VALUES (
-    $1, $2
+    $1, $2, $3
 ) RETURNING id
 `
 
 type CreateUserParams struct {
-	Email        string
-	HashPassword string
+	Email           string
+	EmailNormalized string
+	HashPassword    string
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (int32, error) {
-	row := q.db.QueryRow(ctx, createUser, arg.Email, arg.HashPassword)
+	row := q.db.QueryRow(ctx, createUser, arg.Email, arg.EmailNormalized, arg.HashPassword)
 	var id int32
 	err := row.Scan(&id)
 	return id, err
 }
 
+const deleteUser = `-- name: DeleteUser :execrows
+DELETE FROM users
+WHERE id = $1
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id int32) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, hash_password
+SELECT id, email, hash_password, role, blocked_at
 FROM users
-WHERE email = $1
+WHERE email_normalized = $1
 LIMIT 1
 `
 
 type GetUserByEmailRow struct {
 	ID           int32
+	Email        string
 	HashPassword string
+	Role         string
+	BlockedAt    pgtype.Timestamptz
 }
 
-func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEmailRow, error) {
-	row := q.db.QueryRow(ctx, getUserByEmail, email)
+func (q *Queries) GetUserByEmail(ctx context.Context, emailNormalized string) (GetUserByEmailRow, error) {
+	row := q.db.QueryRow(ctx, getUserByEmail, emailNormalized)
 	var i GetUserByEmailRow
-	err := row.Scan(&i.ID, &i.HashPassword)
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.HashPassword,
+		&i.Role,
+		&i.BlockedAt,
+	)
 	return i, err
 }
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT id, email, hash_password, role, blocked_at, created_at
+FROM users
+WHERE id = $1
+LIMIT 1
+`
+
+type GetUserByIDRow struct {
+	ID           int32
+	Email        string
+	HashPassword string
+	Role         string
+	BlockedAt    pgtype.Timestamptz
+	CreatedAt    pgtype.Timestamptz
+}
+
+func (q *Queries) GetUserByID(ctx context.Context, id int32) (GetUserByIDRow, error) {
+	row := q.db.QueryRow(ctx, getUserByID, id)
+	var i GetUserByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.HashPassword,
+		&i.Role,
+		&i.BlockedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listUserEmails = `-- name: ListUserEmails :many
+SELECT id, email, email_normalized
+FROM users
+ORDER BY id
+`
+
+type ListUserEmailsRow struct {
+	ID              int32
+	Email           string
+	EmailNormalized string
+}
+
+func (q *Queries) ListUserEmails(ctx context.Context) ([]ListUserEmailsRow, error) {
+	rows, err := q.db.Query(ctx, listUserEmails)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUserEmailsRow
+	for rows.Next() {
+		var i ListUserEmailsRow
+		if err := rows.Scan(&i.ID, &i.Email, &i.EmailNormalized); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsers = `-- name: ListUsers :many
+SELECT id, email, role, blocked_at, created_at
+FROM users
+WHERE id > $1
+  AND ($2::text IS NULL OR email ILIKE $2)
+ORDER BY id
+LIMIT $3
+`
+
+type ListUsersParams struct {
+	AfterID      int32
+	EmailPattern pgtype.Text
+	PageSize     int32
+}
+
+type ListUsersRow struct {
+	ID        int32
+	Email     string
+	Role      string
+	BlockedAt pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]ListUsersRow, error) {
+	rows, err := q.db.Query(ctx, listUsers, arg.AfterID, arg.EmailPattern, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUsersRow
+	for rows.Next() {
+		var i ListUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Role,
+			&i.BlockedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const resetUserMfa = `-- name: ResetUserMfa :execrows
+UPDATE users
+SET mfa_secret = NULL,
+    updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) ResetUserMfa(ctx context.Context, id int32) (int64, error) {
+	result, err := q.db.Exec(ctx, resetUserMfa, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setUserEmailNormalized = `-- name: SetUserEmailNormalized :exec
+UPDATE users
+SET email_normalized = $2,
+    updated_at = now()
+WHERE id = $1
+`
+
+type SetUserEmailNormalizedParams struct {
+	ID              int32
+	EmailNormalized string
+}
+
+func (q *Queries) SetUserEmailNormalized(ctx context.Context, arg SetUserEmailNormalizedParams) error {
+	_, err := q.db.Exec(ctx, setUserEmailNormalized, arg.ID, arg.EmailNormalized)
+	return err
+}
+
+const unblockUser = `-- name: UnblockUser :execrows
+UPDATE users
+SET blocked_at = NULL,
+    updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) UnblockUser(ctx context.Context, id int32) (int64, error) {
+	result, err := q.db.Exec(ctx, unblockUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :execrows
+UPDATE users
+SET hash_password = $2,
+    updated_at = now()
+WHERE id = $1
+`
+
+type UpdateUserPasswordParams struct {
+	ID           int32
+	HashPassword string
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateUserPassword, arg.ID, arg.HashPassword)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,313 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: webhook.sql
+
+package query
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimWebhookDeliveries = `-- name: ClaimWebhookDeliveries :many
+WITH claimed AS (
+    UPDATE webhook_deliveries
+    SET locked_until = now() + make_interval(secs => $1::int),
+        attempts = attempts + 1
+    WHERE id IN (
+        SELECT d.id
+        FROM webhook_deliveries d
+        WHERE d.status = 'pending'
+          AND d.next_attempt_at <= now()
+          AND (d.locked_until IS NULL OR d.locked_until < now())
+        ORDER BY d.id
+        LIMIT $2
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING id, webhook_id, event_id, event_type, payload, attempts, created_at
+)
+SELECT c.id, c.webhook_id, c.event_id, c.event_type, c.payload, c.attempts, c.created_at,
+       w.url, w.secret
+FROM claimed c
+JOIN webhooks w ON w.id = c.webhook_id
+ORDER BY c.id
+`
+
+type ClaimWebhookDeliveriesParams struct {
+	LeaseSec  int32
+	BatchSize int32
+}
+
+type ClaimWebhookDeliveriesRow struct {
+	ID        int64
+	WebhookID int64
+	EventID   int64
+	EventType string
+	Payload   []byte
+	Attempts  int32
+	CreatedAt pgtype.Timestamptz
+	Url       string
+	Secret    string
+}
+
+func (q *Queries) ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]ClaimWebhookDeliveriesRow, error) {
+	rows, err := q.db.Query(ctx, claimWebhookDeliveries, arg.LeaseSec, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimWebhookDeliveriesRow
+	for rows.Next() {
+		var i ClaimWebhookDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.EventID,
+			&i.EventType,
+			&i.Payload,
+			&i.Attempts,
+			&i.CreatedAt,
+			&i.Url,
+			&i.Secret,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createWebhook = `-- name: CreateWebhook :one
+INSERT INTO webhooks (
+    url,
+    event_types,
+    secret
+) VALUES (
+    $1, $2, $3
+) RETURNING id, created_at
+`
+
+type CreateWebhookParams struct {
+	Url        string
+	EventTypes []string
+	Secret     string
+}
+
+type CreateWebhookRow struct {
+	ID        int64
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateWebhook(ctx context.Context, arg CreateWebhookParams) (CreateWebhookRow, error) {
+	row := q.db.QueryRow(ctx, createWebhook, arg.Url, arg.EventTypes, arg.Secret)
+	var i CreateWebhookRow
+	err := row.Scan(&i.ID, &i.CreatedAt)
+	return i, err
+}
+
+const deleteWebhook = `-- name: DeleteWebhook :execrows
+DELETE FROM webhooks
+WHERE id = $1
+`
+
+func (q *Queries) DeleteWebhook(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteWebhook, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const enqueueWebhookDelivery = `-- name: EnqueueWebhookDelivery :exec
+INSERT INTO webhook_deliveries (
+    webhook_id,
+    event_id,
+    event_type,
+    payload
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (webhook_id, event_id) DO NOTHING
+`
+
+type EnqueueWebhookDeliveryParams struct {
+	WebhookID int64
+	EventID   int64
+	EventType string
+	Payload   []byte
+}
+
+func (q *Queries) EnqueueWebhookDelivery(ctx context.Context, arg EnqueueWebhookDeliveryParams) error {
+	_, err := q.db.Exec(ctx, enqueueWebhookDelivery,
+		arg.WebhookID,
+		arg.EventID,
+		arg.EventType,
+		arg.Payload,
+	)
+	return err
+}
+
+const listWebhookDeliveries = `-- name: ListWebhookDeliveries :many
+SELECT id, webhook_id, event_id, event_type, status, attempts, next_attempt_at,
+       last_status_code, last_error, delivered_at, created_at
+FROM webhook_deliveries
+WHERE ($1::bigint = 0 OR webhook_id = $1)
+  AND ($2::bigint = 0 OR id < $2)
+ORDER BY id DESC
+LIMIT $3
+`
+
+type ListWebhookDeliveriesParams struct {
+	WebhookID int64
+	BeforeID  int64
+	PageSize  int32
+}
+
+type ListWebhookDeliveriesRow struct {
+	ID             int64
+	WebhookID      int64
+	EventID        int64
+	EventType      string
+	Status         string
+	Attempts       int32
+	NextAttemptAt  pgtype.Timestamptz
+	LastStatusCode pgtype.Int4
+	LastError      pgtype.Text
+	DeliveredAt    pgtype.Timestamptz
+	CreatedAt      pgtype.Timestamptz
+}
+
+func (q *Queries) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]ListWebhookDeliveriesRow, error) {
+	rows, err := q.db.Query(ctx, listWebhookDeliveries, arg.WebhookID, arg.BeforeID, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListWebhookDeliveriesRow
+	for rows.Next() {
+		var i ListWebhookDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.EventID,
+			&i.EventType,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastStatusCode,
+			&i.LastError,
+			&i.DeliveredAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhooks = `-- name: ListWebhooks :many
+SELECT id, url, event_types, secret, created_at
+FROM webhooks
+ORDER BY id
+`
+
+func (q *Queries) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	rows, err := q.db.Query(ctx, listWebhooks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webhook
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.EventTypes,
+			&i.Secret,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markWebhookDelivered = `-- name: MarkWebhookDelivered :exec
+UPDATE webhook_deliveries
+SET status = 'delivered',
+    delivered_at = now(),
+    locked_until = NULL,
+    last_status_code = $1,
+    last_error = NULL
+WHERE id = $2
+`
+
+type MarkWebhookDeliveredParams struct {
+	StatusCode pgtype.Int4
+	ID         int64
+}
+
+func (q *Queries) MarkWebhookDelivered(ctx context.Context, arg MarkWebhookDeliveredParams) error {
+	_, err := q.db.Exec(ctx, markWebhookDelivered, arg.StatusCode, arg.ID)
+	return err
+}
+
+const markWebhookDeliveryDead = `-- name: MarkWebhookDeliveryDead :exec
+UPDATE webhook_deliveries
+SET status = 'failed',
+    locked_until = NULL,
+    last_status_code = $1,
+    last_error = $2
+WHERE id = $3
+`
+
+type MarkWebhookDeliveryDeadParams struct {
+	StatusCode pgtype.Int4
+	LastError  pgtype.Text
+	ID         int64
+}
+
+func (q *Queries) MarkWebhookDeliveryDead(ctx context.Context, arg MarkWebhookDeliveryDeadParams) error {
+	_, err := q.db.Exec(ctx, markWebhookDeliveryDead, arg.StatusCode, arg.LastError, arg.ID)
+	return err
+}
+
+const markWebhookDeliveryFailed = `-- name: MarkWebhookDeliveryFailed :exec
+UPDATE webhook_deliveries
+SET locked_until = NULL,
+    last_status_code = $1,
+    last_error = $2,
+    next_attempt_at = $3
+WHERE id = $4
+`
+
+type MarkWebhookDeliveryFailedParams struct {
+	StatusCode    pgtype.Int4
+	LastError     pgtype.Text
+	NextAttemptAt pgtype.Timestamptz
+	ID            int64
+}
+
+func (q *Queries) MarkWebhookDeliveryFailed(ctx context.Context, arg MarkWebhookDeliveryFailedParams) error {
+	_, err := q.db.Exec(ctx, markWebhookDeliveryFailed,
+		arg.StatusCode,
+		arg.LastError,
+		arg.NextAttemptAt,
+		arg.ID,
+	)
+	return err
+}
@@ -2,6 +2,7 @@ package psql
 
 import (
 	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/psql/query"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -10,11 +11,15 @@ const pgUniqueViolation = "23505"
 type Repo struct {
 	db      *pgxpool.Pool
 	queries *query.Queries
+
+	tx     pgx.Tx // не nil внутри WithTx
+	txOpts TxOptions
 }
 
-func NewPsqlRepo(db *pgxpool.Pool) *Repo {
+func NewPsqlRepo(db *pgxpool.Pool, txOpts TxOptions) *Repo {
 	return &Repo{
 		db:      db,
 		queries: query.New(db),
+		txOpts:  txOpts,
 	}
 }
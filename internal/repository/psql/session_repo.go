@@ -0,0 +1,103 @@
+package psql
+
+import (
+	"context"
+	"errors"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/psql/query"
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *Repo) CreateSession(ctx context.Context, session models.Session) error {
+	return r.queries.CreateSession(ctx, query.CreateSessionParams{
+		ID:               session.ID,
+		UserID:           session.UserID,
+		ExpiresAt:        timeToPg(session.ExpiresAt),
+		RefreshTokenHash: session.RefreshTokenHash,
+	})
+}
+
+func (r *Repo) GetSessionState(ctx context.Context, sessionID string) (models.SessionState, error) {
+	row, err := r.queries.GetSessionState(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.SessionState{}, modelerrors.ErrNoRows
+		}
+		return models.SessionState{}, err
+	}
+	return models.SessionState{
+		UserID:      row.UserID,
+		ExpiresAt:   timeFromPg(row.ExpiresAt),
+		Revoked:     row.RevokedAt.Valid,
+		UserBlocked: row.BlockedAt.Valid,
+	}, nil
+}
+
+func (r *Repo) GetSessionByRefreshHash(ctx context.Context, hash []byte) (models.RefreshSessionState, error) {
+	row, err := r.queries.GetSessionByRefreshHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.RefreshSessionState{}, modelerrors.ErrNoRows
+		}
+		return models.RefreshSessionState{}, err
+	}
+	return models.RefreshSessionState{
+		SessionID:   row.ID,
+		UserID:      row.UserID,
+		ExpiresAt:   timeFromPg(row.ExpiresAt),
+		Revoked:     row.RevokedAt.Valid,
+		UserBlocked: row.BlockedAt.Valid,
+		Current:     row.IsCurrent,
+	}, nil
+}
+
+// RotateSessionRefresh меняет refresh token сессии, только если текущий — oldHash;
+// false — токен уже ротирован параллельным запросом или сессия отозвана.
+func (r *Repo) RotateSessionRefresh(ctx context.Context, sessionID string, oldHash, newHash []byte) (bool, error) {
+	n, err := r.queries.RotateSessionRefresh(ctx, query.RotateSessionRefreshParams{
+		ID:      sessionID,
+		OldHash: oldHash,
+		NewHash: newHash,
+	})
+	return n > 0, err
+}
+
+func (r *Repo) RevokeSession(ctx context.Context, sessionID string) (bool, error) {
+	n, err := r.queries.RevokeSession(ctx, sessionID)
+	return n > 0, err
+}
+
+func (r *Repo) ListSessionsByUser(ctx context.Context, userID int32, limit int32) ([]models.Session, error) {
+	rows, err := r.queries.ListSessionsByUser(ctx, query.ListSessionsByUserParams{
+		UserID:   userID,
+		PageSize: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]models.Session, 0, len(rows))
+	for _, row := range rows {
+		sessions = append(sessions, models.Session{
+			ID:        row.ID,
+			UserID:    row.UserID,
+			ExpiresAt: timeFromPg(row.ExpiresAt),
+			RevokedAt: timeFromPg(row.RevokedAt),
+			CreatedAt: timeFromPg(row.CreatedAt),
+		})
+	}
+	return sessions, nil
+}
+
+func (r *Repo) RevokeUserSessions(ctx context.Context, userID int32) (int64, error) {
+	return r.queries.RevokeUserSessions(ctx, userID)
+}
+
+func (r *Repo) MoveSessions(ctx context.Context, fromUserID, toUserID int32) (int64, error) {
+	return r.queries.MoveSessions(ctx, query.MoveSessionsParams{
+		FromUserID: fromUserID,
+		ToUserID:   toUserID,
+	})
+}
@@ -0,0 +1,31 @@
+package psql
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/exaring/otelpgx"
+)
+
+// newQueryTracer — span на каждый запрос внутри уже идущего trace-а. Имя span-а — имя
+// запроса sqlc (-- name: GetUserByEmail :one), значения параметров в span не попадают.
+func newQueryTracer() *otelpgx.Tracer {
+	return otelpgx.NewTracer(
+		otelpgx.WithTrimSQLInSpanName(),
+		otelpgx.WithSpanNameFunc(queryName),
+		otelpgx.WithDisableSQLStatementInAttributes(),
+	)
+}
+
+var sqlcNameRe = regexp.MustCompile(`^--\s*name:\s*(\w+)`)
+
+func queryName(stmt string) string {
+	stmt = strings.TrimSpace(stmt)
+	if m := sqlcNameRe.FindStringSubmatch(stmt); m != nil {
+		return m[1]
+	}
+	if i := strings.IndexAny(stmt, " \n\t"); i > 0 {
+		return strings.ToUpper(stmt[:i])
+	}
+	return stmt
+}
@@ -0,0 +1,105 @@
+package psql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+
+	defaultTxMaxRetries = 3
+	txRetryBaseDelay    = 20 * time.Millisecond
+)
+
+// TxOptions — уровень изоляции и число повторов транзакций WithTx.
+type TxOptions struct {
+	Isolation  pgx.TxIsoLevel // пусто — read committed (умолчание Postgres)
+	MaxRetries int            // повторы при 40001/40P01, 0 — по умолчанию 3, < 0 — без повторов
+}
+
+// ParseIsolation переводит значение из конфига (read_committed, repeatable_read, serializable).
+func ParseIsolation(s string) (pgx.TxIsoLevel, error) {
+	switch s {
+	case "", "read_committed":
+		return pgx.ReadCommitted, nil
+	case "repeatable_read":
+		return pgx.RepeatableRead, nil
+	case "serializable":
+		return pgx.Serializable, nil
+	default:
+		return "", fmt.Errorf("unknown isolation level %q", s)
+	}
+}
+
+// WithTx выполняет fn в транзакции: tx — тот же репозиторий, но все запросы идут через неё.
+// При serialization failure и deadlock транзакция повторяется целиком, поэтому fn
+// должна только работать с tx — письма, аудит и прочие побочные эффекты делаются после.
+// Вызов на tx-репозитории не открывает новую транзакцию, а выполняет fn в текущей.
+func (r *Repo) WithTx(ctx context.Context, fn func(tx *Repo) error) error {
+	if r.tx != nil {
+		return fn(r)
+	}
+
+	retries := r.txOpts.MaxRetries
+	switch {
+	case retries == 0:
+		retries = defaultTxMaxRetries
+	case retries < 0:
+		retries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := r.runTx(ctx, fn)
+		if err == nil || !isRetryableTxErr(err) {
+			return err
+		}
+		if attempt >= retries {
+			return fmt.Errorf("%w: %w", modelerrors.ErrTxConflict, err)
+		}
+
+		delay := txRetryBaseDelay<<attempt + rand.N(txRetryBaseDelay)
+		logger.FromContext(ctx).Debugf("psql: retry transaction (attempt %d) in %s: %s", attempt+1, delay, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (r *Repo) runTx(ctx context.Context, fn func(tx *Repo) error) error {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: r.txOpts.Isolation})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) // после Commit — no-op
+
+	txRepo := &Repo{
+		db:      r.db,
+		queries: r.queries.WithTx(tx),
+		tx:      tx,
+		txOpts:  r.txOpts,
+	}
+	if err := fn(txRepo); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func isRetryableTxErr(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected
+}
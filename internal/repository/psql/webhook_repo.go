@@ -0,0 +1,142 @@
+package psql
+
+import (
+	"context"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/psql/query"
+)
+
+func (r *Repo) CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	eventTypes := webhook.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	row, err := r.queries.CreateWebhook(ctx, query.CreateWebhookParams{
+		Url:        webhook.URL,
+		EventTypes: eventTypes,
+		Secret:     webhook.Secret,
+	})
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	webhook.ID = row.ID
+	webhook.CreatedAt = timeFromPg(row.CreatedAt)
+	return webhook, nil
+}
+
+func (r *Repo) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	rows, err := r.queries.ListWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := make([]models.Webhook, 0, len(rows))
+	for _, row := range rows {
+		webhooks = append(webhooks, models.Webhook{
+			ID:         row.ID,
+			URL:        row.Url,
+			EventTypes: row.EventTypes,
+			Secret:     row.Secret,
+			CreatedAt:  timeFromPg(row.CreatedAt),
+		})
+	}
+	return webhooks, nil
+}
+
+func (r *Repo) DeleteWebhook(ctx context.Context, id int64) error {
+	return affectedOrNoRows(r.queries.DeleteWebhook(ctx, id))
+}
+
+// EnqueueWebhookDelivery идемпотентна: повтор того же события outbox не создаёт вторую доставку.
+func (r *Repo) EnqueueWebhookDelivery(ctx context.Context, webhookID int64, event models.OutboxEvent) error {
+	return r.queries.EnqueueWebhookDelivery(ctx, query.EnqueueWebhookDeliveryParams{
+		WebhookID: webhookID,
+		EventID:   event.ID,
+		EventType: event.Type,
+		Payload:   event.Payload,
+	})
+}
+
+func (r *Repo) ClaimWebhookDeliveries(ctx context.Context, limit int32, lease time.Duration) ([]models.WebhookJob, error) {
+	rows, err := r.queries.ClaimWebhookDeliveries(ctx, query.ClaimWebhookDeliveriesParams{
+		LeaseSec:  int32(lease / time.Second),
+		BatchSize: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]models.WebhookJob, 0, len(rows))
+	for _, row := range rows {
+		jobs = append(jobs, models.WebhookJob{
+			Delivery: models.WebhookDelivery{
+				ID:        row.ID,
+				WebhookID: row.WebhookID,
+				EventID:   row.EventID,
+				EventType: row.EventType,
+				Payload:   row.Payload,
+				Status:    models.WebhookDeliveryPending,
+				Attempts:  row.Attempts,
+				CreatedAt: timeFromPg(row.CreatedAt),
+			},
+			URL:    row.Url,
+			Secret: row.Secret,
+		})
+	}
+	return jobs, nil
+}
+
+func (r *Repo) MarkWebhookDelivered(ctx context.Context, id int64, statusCode int32) error {
+	return r.queries.MarkWebhookDelivered(ctx, query.MarkWebhookDeliveredParams{
+		ID:         id,
+		StatusCode: int4ToPg(statusCode),
+	})
+}
+
+func (r *Repo) MarkWebhookDeliveryFailed(ctx context.Context, id int64, statusCode int32, lastErr string, nextAttemptAt time.Time) error {
+	return r.queries.MarkWebhookDeliveryFailed(ctx, query.MarkWebhookDeliveryFailedParams{
+		ID:            id,
+		StatusCode:    int4ToPg(statusCode),
+		LastError:     textToPg(lastErr),
+		NextAttemptAt: timeToPg(nextAttemptAt),
+	})
+}
+
+func (r *Repo) MarkWebhookDeliveryDead(ctx context.Context, id int64, statusCode int32, lastErr string) error {
+	return r.queries.MarkWebhookDeliveryDead(ctx, query.MarkWebhookDeliveryDeadParams{
+		ID:         id,
+		StatusCode: int4ToPg(statusCode),
+		LastError:  textToPg(lastErr),
+	})
+}
+
+func (r *Repo) ListWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	rows, err := r.queries.ListWebhookDeliveries(ctx, query.ListWebhookDeliveriesParams{
+		WebhookID: filter.WebhookID,
+		BeforeID:  filter.BeforeID,
+		PageSize:  filter.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]models.WebhookDelivery, 0, len(rows))
+	for _, row := range rows {
+		deliveries = append(deliveries, models.WebhookDelivery{
+			ID:             row.ID,
+			WebhookID:      row.WebhookID,
+			EventID:        row.EventID,
+			EventType:      row.EventType,
+			Status:         row.Status,
+			Attempts:       row.Attempts,
+			NextAttemptAt:  timeFromPg(row.NextAttemptAt),
+			LastStatusCode: int4FromPg(row.LastStatusCode),
+			LastError:      textFromPg(row.LastError),
+			DeliveredAt:    timeFromPg(row.DeliveredAt),
+			CreatedAt:      timeFromPg(row.CreatedAt),
+		})
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,232 @@
+// Package repotest — общий контракт репозиториев: inmemory должен вести себя как psql,
+// иначе сервисы, проверенные на памяти, сломаются на Postgres.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+// Repo — часть репозитория, на которую опираются сервисы в контракте ошибок и транзакций.
+type Repo[R any] interface {
+	WithTx(ctx context.Context, fn func(tx R) error) error
+
+	CreateUser(ctx context.Context, user models.User) (int32, error)
+	GetByEmail(ctx context.Context, normalizedEmail string) (models.User, error)
+	GetUserByID(ctx context.Context, userID int32) (models.User, error)
+	BlockUser(ctx context.Context, userID int32) error
+	DeleteUser(ctx context.Context, userID int32) error
+
+	GetIdentity(ctx context.Context, provider, providerUserID string) (models.Identity, error)
+	CreateIdentity(ctx context.Context, identity models.Identity) (int64, error)
+
+	CreateLinkCode(ctx context.Context, code models.LinkCode) error
+	GetLinkCode(ctx context.Context, code string) (models.LinkCode, error)
+	UseLinkCode(ctx context.Context, code string) (int32, error)
+
+	CreateSession(ctx context.Context, session models.Session) error
+	GetSessionState(ctx context.Context, sessionID string) (models.SessionState, error)
+	GetSessionByRefreshHash(ctx context.Context, hash []byte) (models.RefreshSessionState, error)
+}
+
+// Run прогоняет контракт; newRepo отдаёт пустое хранилище на каждый подтест.
+func Run[R Repo[R]](t *testing.T, newRepo func(t *testing.T) R) {
+	t.Run("UniqueViolations", func(t *testing.T) { testUniqueViolations(t, newRepo(t)) })
+	t.Run("NoRows", func(t *testing.T) { testNoRows(t, newRepo(t)) })
+	t.Run("LinkCodeSingleUse", func(t *testing.T) { testLinkCodeSingleUse(t, newRepo(t)) })
+	t.Run("WithTxRollback", func(t *testing.T) { testWithTxRollback(t, newRepo(t)) })
+	t.Run("WithTxCommit", func(t *testing.T) { testWithTxCommit(t, newRepo(t)) })
+}
+
+func createUser[R Repo[R]](t *testing.T, r R, email string) int32 {
+	t.Helper()
+	id, err := r.CreateUser(context.Background(), models.User{
+		Email:           email,
+		EmailNormalized: email,
+		HashPassword:    "hash",
+		Role:            models.RoleUser,
+	})
+	if err != nil {
+		t.Fatalf("CreateUser(%s): %v", email, err)
+	}
+	return id
+}
+
+func telegramIdentity(userID int32, tgID string) models.Identity {
+	return models.Identity{UserID: userID, Provider: models.ProviderTelegram, ProviderUserID: tgID, ChatID: 1}
+}
+
+func testUniqueViolations[R Repo[R]](t *testing.T, r R) {
+	ctx := context.Background()
+	first := createUser(t, r, "ivan@example.com")
+
+	// email хранится как ввели, уникален ключ
+	_, err := r.CreateUser(ctx, models.User{Email: "Ivan@Example.com", EmailNormalized: "ivan@example.com", HashPassword: "hash", Role: models.RoleUser})
+	if !errors.Is(err, modelerrors.ErrEmailTaken) {
+		t.Fatalf("duplicate email: err = %v, want ErrEmailTaken", err)
+	}
+
+	second := createUser(t, r, "petr@example.com")
+	if _, err := r.CreateIdentity(ctx, telegramIdentity(first, "42")); err != nil {
+		t.Fatalf("CreateIdentity: %v", err)
+	}
+	if _, err := r.CreateIdentity(ctx, telegramIdentity(second, "42")); !errors.Is(err, modelerrors.ErrTelegramAlreadyLinked) {
+		t.Fatalf("duplicate identity: err = %v, want ErrTelegramAlreadyLinked", err)
+	}
+}
+
+func testNoRows[R Repo[R]](t *testing.T, r R) {
+	ctx := context.Background()
+	missing := int32(1_000_000)
+
+	checks := map[string]error{}
+	_, checks["GetByEmail"] = r.GetByEmail(ctx, "nobody@example.com")
+	_, checks["GetUserByID"] = r.GetUserByID(ctx, missing)
+	checks["BlockUser"] = r.BlockUser(ctx, missing)
+	checks["DeleteUser"] = r.DeleteUser(ctx, missing)
+	_, checks["GetIdentity"] = r.GetIdentity(ctx, models.ProviderTelegram, "404")
+	_, checks["GetLinkCode"] = r.GetLinkCode(ctx, "NOSUCHCODE")
+	_, checks["UseLinkCode"] = r.UseLinkCode(ctx, "NOSUCHCODE")
+	_, checks["GetSessionState"] = r.GetSessionState(ctx, "00000000000000000000000000000000")
+	_, checks["GetSessionByRefreshHash"] = r.GetSessionByRefreshHash(ctx, make([]byte, 32))
+	for name, err := range checks {
+		if !errors.Is(err, modelerrors.ErrNoRows) {
+			t.Errorf("%s: err = %v, want ErrNoRows", name, err)
+		}
+	}
+
+	// удалённый пользователь — тоже ErrNoRows, а не пустая запись
+	id := createUser(t, r, "gone@example.com")
+	if err := r.DeleteUser(ctx, id); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if _, err := r.GetUserByID(ctx, id); !errors.Is(err, modelerrors.ErrNoRows) {
+		t.Errorf("GetUserByID after delete: err = %v, want ErrNoRows", err)
+	}
+	if _, err := r.GetByEmail(ctx, "gone@example.com"); !errors.Is(err, modelerrors.ErrNoRows) {
+		t.Errorf("GetByEmail after delete: err = %v, want ErrNoRows", err)
+	}
+}
+
+func testLinkCodeSingleUse[R Repo[R]](t *testing.T, r R) {
+	ctx := context.Background()
+	id := createUser(t, r, "ivan@example.com")
+	for code, expires := range map[string]time.Time{
+		"FRESH1": time.Now().Add(time.Hour),
+		"STALE1": time.Now().Add(-time.Minute),
+	} {
+		if err := r.CreateLinkCode(ctx, models.LinkCode{Code: code, UserID: id, ExpiresAt: expires}); err != nil {
+			t.Fatalf("CreateLinkCode(%s): %v", code, err)
+		}
+	}
+
+	if got, err := r.UseLinkCode(ctx, "FRESH1"); err != nil || got != id {
+		t.Fatalf("UseLinkCode = %d, %v; want %d", got, err, id)
+	}
+	if _, err := r.UseLinkCode(ctx, "FRESH1"); !errors.Is(err, modelerrors.ErrNoRows) {
+		t.Fatalf("second use: err = %v, want ErrNoRows", err)
+	}
+	if _, err := r.UseLinkCode(ctx, "STALE1"); !errors.Is(err, modelerrors.ErrNoRows) {
+		t.Fatalf("expired code: err = %v, want ErrNoRows", err)
+	}
+	// сам код остаётся читаемым: по нему объясняют причину отказа
+	if lc, err := r.GetLinkCode(ctx, "FRESH1"); err != nil || lc.UsedAt.IsZero() {
+		t.Fatalf("GetLinkCode after use = %+v, %v", lc, err)
+	}
+}
+
+var errAbort = errors.New("abort")
+
+func testWithTxRollback[R Repo[R]](t *testing.T, r R) {
+	ctx := context.Background()
+	owner := createUser(t, r, "owner@example.com")
+	if err := r.CreateLinkCode(ctx, models.LinkCode{Code: "TXCODE", UserID: owner, ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := r.WithTx(ctx, func(tx R) error {
+		id, err := tx.CreateUser(ctx, models.User{Email: "tx@example.com", EmailNormalized: "tx@example.com", HashPassword: "hash", Role: models.RoleUser})
+		if err != nil {
+			return err
+		}
+		if _, err := tx.CreateIdentity(ctx, telegramIdentity(id, "7")); err != nil {
+			return err
+		}
+		if _, err := tx.UseLinkCode(ctx, "TXCODE"); err != nil {
+			return err
+		}
+		if err := tx.BlockUser(ctx, owner); err != nil {
+			return err
+		}
+		// вложенный WithTx — часть внешней транзакции и откатывается вместе с ней
+		return tx.WithTx(ctx, func(inner R) error {
+			if _, err := inner.CreateUser(ctx, models.User{Email: "inner@example.com", EmailNormalized: "inner@example.com", HashPassword: "hash", Role: models.RoleUser}); err != nil {
+				return err
+			}
+			return errAbort
+		})
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("WithTx err = %v, want the callback error", err)
+	}
+
+	for _, email := range []string{"tx@example.com", "inner@example.com"} {
+		if _, err := r.GetByEmail(ctx, email); !errors.Is(err, modelerrors.ErrNoRows) {
+			t.Errorf("%s survived rollback: err = %v", email, err)
+		}
+	}
+	if _, err := r.GetIdentity(ctx, models.ProviderTelegram, "7"); !errors.Is(err, modelerrors.ErrNoRows) {
+		t.Errorf("identity survived rollback: err = %v", err)
+	}
+	if u, err := r.GetUserByID(ctx, owner); err != nil || u.Blocked() {
+		t.Errorf("block survived rollback: %+v, %v", u, err)
+	}
+	if _, err := r.UseLinkCode(ctx, "TXCODE"); err != nil {
+		t.Errorf("link code use survived rollback: %v", err)
+	}
+	// ключ из откатившейся транзакции свободен
+	createUser(t, r, "tx@example.com")
+}
+
+func testWithTxCommit[R Repo[R]](t *testing.T, r R) {
+	ctx := context.Background()
+	var id int32
+	err := r.WithTx(ctx, func(tx R) error {
+		var err error
+		if id, err = tx.CreateUser(ctx, models.User{Email: "tx@example.com", EmailNormalized: "tx@example.com", HashPassword: "hash", Role: models.RoleUser}); err != nil {
+			return err
+		}
+		// свои записи транзакция видит до коммита
+		if _, err := tx.GetUserByID(ctx, id); err != nil {
+			return err
+		}
+		_, err = tx.CreateIdentity(ctx, telegramIdentity(id, "7"))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+	if identity, err := r.GetIdentity(ctx, models.ProviderTelegram, "7"); err != nil || identity.UserID != id {
+		t.Fatalf("committed identity = %+v, %v", identity, err)
+	}
+
+	// ошибка уникальности внутри транзакции отдаётся как есть и откатывает остальное
+	err = r.WithTx(ctx, func(tx R) error {
+		if err := tx.BlockUser(ctx, id); err != nil {
+			return err
+		}
+		_, err := tx.CreateUser(ctx, models.User{Email: "tx@example.com", EmailNormalized: "tx@example.com", HashPassword: "hash", Role: models.RoleUser})
+		return err
+	})
+	if !errors.Is(err, modelerrors.ErrEmailTaken) {
+		t.Fatalf("WithTx duplicate: err = %v, want ErrEmailTaken", err)
+	}
+	if u, err := r.GetUserByID(ctx, id); err != nil || u.Blocked() {
+		t.Fatalf("block survived failed tx: %+v, %v", u, err)
+	}
+}
@@ -0,0 +1,41 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/reqmeta"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/logger"
+)
+
+type Store interface {
+	RecordAuditEvent(ctx context.Context, event models.AuditEvent) error
+}
+
+// Recorder дополняет событие сведениями о запросе (actor, IP, user agent, request id)
+// и пишет его в Store. Ошибка записи не прерывает бизнес-операцию — только логируется.
+type Recorder struct {
+	store Store
+}
+
+func NewRecorder(store Store) *Recorder {
+	return &Recorder{store: store}
+}
+
+func (r *Recorder) Record(ctx context.Context, event models.AuditEvent) {
+	m := reqmeta.From(ctx)
+	if event.ActorUserID == 0 {
+		event.ActorUserID = m.UserID
+	}
+	if event.ActorBotID == "" {
+		event.ActorBotID = m.BotID
+	}
+	event.IP = m.IP
+	event.UserAgent = m.UserAgent
+	event.RequestID = m.RequestID
+
+	// клиент мог уже отменить запрос, а событие всё равно нужно записать
+	if err := r.store.RecordAuditEvent(context.WithoutCancel(ctx), event); err != nil {
+		logger.FromContext(ctx).Errorf("audit: record %s: %s", event.Type, err)
+	}
+}
@@ -0,0 +1,56 @@
+package emailnorm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/config"
+	"golang.org/x/net/idna"
+)
+
+var ErrInvalidEmail = errors.New("invalid email address")
+
+// gmailDomains — ящики Google: точки в local part не значат ничего, +tag — метка.
+var gmailDomains = map[string]bool{
+	"gmail.com":      true,
+	"googlemail.com": true,
+}
+
+// Normalizer приводит email к ключу уникальности: один ящик — один аккаунт.
+// Сам адрес хранится как его ввели, ключ нужен только для поиска и UNIQUE.
+type Normalizer struct {
+	providerRules bool
+}
+
+func New(cfg config.Email) *Normalizer {
+	return &Normalizer{providerRules: cfg.ProviderRules}
+}
+
+// Normalize: lower-case всего адреса, домен — в ASCII через IDNA (bücher.de -> xn--bcher-kva.de),
+// с provider_rules — ещё правила Gmail (f.o.o+tag@googlemail.com -> foo@gmail.com).
+func (n *Normalizer) Normalize(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	at := strings.LastIndexByte(email, '@')
+	if at <= 0 || at == len(email)-1 {
+		return "", ErrInvalidEmail
+	}
+
+	local := strings.ToLower(email[:at])
+	domain, err := idna.Lookup.ToASCII(strings.TrimSuffix(email[at+1:], "."))
+	if err != nil {
+		return "", fmt.Errorf("%w: domain: %s", ErrInvalidEmail, err)
+	}
+	domain = strings.ToLower(domain)
+
+	if n.providerRules && gmailDomains[domain] {
+		local, _, _ = strings.Cut(local, "+")
+		local = strings.ReplaceAll(local, ".", "")
+		if local == "" {
+			return "", ErrInvalidEmail
+		}
+		domain = "gmail.com"
+	}
+
+	return local + "@" + domain, nil
+}
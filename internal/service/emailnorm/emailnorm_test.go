@@ -0,0 +1,94 @@
+package emailnorm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/config"
+)
+
+// migrationKey — ключ, который ставит миграция 00007: lower(btrim(email)).
+// btrim без аргументов срезает только пробелы; lower в ASCII совпадает с strings.ToLower.
+func migrationKey(email string) string {
+	return strings.ToLower(strings.Trim(email, " "))
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		email    string
+		provider bool
+		want     string
+		// совпадает с ключом 00007: такой аккаунт находится сразу после миграции,
+		// иначе ключ досчитывает `migrate normalize-emails`
+		sameAsMigration bool
+	}{
+		{name: "lowercase", email: "Ivan.Petrov@Example.COM", want: "ivan.petrov@example.com", sameAsMigration: true},
+		{name: "already normalized", email: "ivan@example.com", want: "ivan@example.com", sameAsMigration: true},
+		{name: "surrounding spaces", email: "  Ivan@Example.com ", want: "ivan@example.com", sameAsMigration: true},
+		{name: "plus tag kept without provider rules", email: "ivan+shop@example.com", want: "ivan+shop@example.com", sameAsMigration: true},
+		{name: "gmail without provider rules", email: "I.van+x@GMail.com", want: "i.van+x@gmail.com", sameAsMigration: true},
+		{name: "last at separates domain", email: `"a@b"@example.com`, want: `"a@b"@example.com`, sameAsMigration: true},
+		{name: "punycode domain", email: "ivan@xn--bcher-kva.de", want: "ivan@xn--bcher-kva.de", sameAsMigration: true},
+
+		{name: "idna domain", email: "ivan@Bücher.de", want: "ivan@xn--bcher-kva.de"},
+		{name: "idna cyrillic domain", email: "Иван@Пример.РФ", want: "иван@xn--e1afmkfd.xn--p1ai"},
+		{name: "trailing dot in domain", email: "ivan@example.com.", want: "ivan@example.com"},
+		{name: "gmail dots and tag", email: "I.Van+shop@gmail.com", provider: true, want: "ivan@gmail.com"},
+		{name: "googlemail folds into gmail", email: "i.van@GoogleMail.com", provider: true, want: "ivan@gmail.com"},
+		{name: "gmail several tags", email: "ivan+a+b@gmail.com", provider: true, want: "ivan@gmail.com"},
+		{name: "provider rules only for gmail", email: "I.Van+shop@example.com", provider: true, want: "i.van+shop@example.com", sameAsMigration: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(config.Email{ProviderRules: tt.provider}).Normalize(tt.email)
+			if err != nil {
+				t.Fatalf("Normalize(%q): %v", tt.email, err)
+			}
+			if got != tt.want {
+				t.Fatalf("Normalize(%q) = %q, want %q", tt.email, got, tt.want)
+			}
+			if same := got == migrationKey(tt.email); same != tt.sameAsMigration {
+				t.Fatalf("key %q vs migration key %q: same = %v, want %v", got, migrationKey(tt.email), same, tt.sameAsMigration)
+			}
+		})
+	}
+}
+
+func TestNormalizeIdempotent(t *testing.T) {
+	for _, provider := range []bool{false, true} {
+		n := New(config.Email{ProviderRules: provider})
+		for _, email := range []string{"I.Van+shop@GoogleMail.com", "ivan@Bücher.de", "Ivan@Example.COM."} {
+			once, err := n.Normalize(email)
+			if err != nil {
+				t.Fatal(err)
+			}
+			twice, err := n.Normalize(once)
+			if err != nil || twice != once {
+				t.Fatalf("provider=%v: Normalize(%q) = %q, %v; want %q", provider, once, twice, err, once)
+			}
+		}
+	}
+}
+
+func TestNormalizeInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		email    string
+		provider bool
+	}{
+		{name: "no at", email: "ivan.example.com"},
+		{name: "empty local", email: "@example.com"},
+		{name: "empty domain", email: "ivan@"},
+		{name: "bad idna", email: "ivan@xn--a.com"},
+		{name: "gmail local only dots", email: "..+tag@gmail.com", provider: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := New(config.Email{ProviderRules: tt.provider}).Normalize(tt.email); !errors.Is(err, ErrInvalidEmail) {
+				t.Fatalf("Normalize(%q) = %q, %v; want ErrInvalidEmail", tt.email, got, err)
+			}
+		})
+	}
+}
@@ -7,8 +7,11 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/IvanOplesnin/BotTradeService.git/internal/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"golang.org/x/crypto/argon2"
 )
 
@@ -17,6 +20,13 @@ var (
 	ErrInvalidConfig = errors.New("invalid argon2 config")
 )
 
+// hashDuration — время argon2id: Hash при регистрации/смене пароля, compare при входе.
+var hashDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "bottrade_argon2_duration_seconds",
+	Help:    "Argon2id key derivation duration by operation (hash, compare).",
+	Buckets: prometheus.ExponentialBuckets(0.005, 2, 10),
+}, []string{"op"})
+
 type Hasher struct {
 	Memory      uint32 // KiB
 	Time        uint32 // iterations
@@ -126,7 +136,9 @@ func (h *Hasher) Hash(password string) (string, error) {
 		return "", fmt.Errorf("read salt: %w", err)
 	}
 
+	start := time.Now()
 	key := argon2.IDKey([]byte(password), salt, h.Time, h.Memory, h.Parallelism, h.KeyLen)
+	hashDuration.WithLabelValues("hash").Observe(time.Since(start).Seconds())
 
 	saltB64 := base64.RawStdEncoding.EncodeToString(salt)
 	keyB64 := base64.RawStdEncoding.EncodeToString(key)
@@ -144,7 +156,9 @@ func (h *Hasher) CompareHash(password, hash string) (bool, error) {
 		return false, err
 	}
 
+	start := time.Now()
 	newKey := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Parallelism, uint32(len(key)))
+	hashDuration.WithLabelValues("compare").Observe(time.Since(start).Seconds())
 
 	// constant-time compare
 	if subtle.ConstantTimeCompare(key, newKey) == 1 {
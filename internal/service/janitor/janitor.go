@@ -0,0 +1,127 @@
+package janitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/service/scheduler"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const defaultBatchSize = 1000
+
+// значения по умолчанию: link-коды и сессии храним сутки после истечения, чтобы
+// успеть объяснить пользователю "код истёк"; доставленные события — для разбора инцидентов.
+var (
+	defaultLinkCodes         = Schedule{Interval: 10 * time.Minute, Retention: 24 * time.Hour}
+	defaultSessions          = Schedule{Interval: time.Hour, Retention: 24 * time.Hour}
+	defaultOutbox            = Schedule{Interval: time.Hour, Retention: 7 * 24 * time.Hour}
+	defaultBotMessages       = Schedule{Interval: time.Hour, Retention: 7 * 24 * time.Hour}
+	defaultWebhookDeliveries = Schedule{Interval: time.Hour, Retention: 14 * 24 * time.Hour}
+	// nonce нужен только пока timestamp запроса в окне проверки — после него хранить незачем
+	defaultBotNonces = Schedule{Interval: 10 * time.Minute, Retention: time.Minute}
+)
+
+// Store — пакетное удаление устаревших строк: не больше batch за вызов, чтобы не держать
+// долгие блокировки. before — граница по времени истечения/доставки.
+type Store interface {
+	PurgeExpiredLinkCodes(ctx context.Context, before time.Time, batch int32) (int64, error)
+	PurgeExpiredSessions(ctx context.Context, before time.Time, batch int32) (int64, error)
+	PurgePublishedOutbox(ctx context.Context, before time.Time, batch int32) (int64, error)
+	PurgeDeliveredBotMessages(ctx context.Context, before time.Time, batch int32) (int64, error)
+	PurgeDeliveredWebhookDeliveries(ctx context.Context, before time.Time, batch int32) (int64, error)
+	PurgeExpiredBotNonces(ctx context.Context, before time.Time, batch int32) (int64, error)
+}
+
+type purgeFunc func(ctx context.Context, before time.Time, batch int32) (int64, error)
+
+var rowsPurged = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "bottrade_janitor_rows_purged_total",
+	Help: "Rows deleted by janitor jobs.",
+}, []string{"job"})
+
+// Schedule — интервал запуска и сколько хранить строки после истечения/доставки.
+// Нулевые поля берутся из значений по умолчанию.
+type Schedule struct {
+	Disabled  bool
+	Interval  time.Duration
+	Retention time.Duration
+}
+
+func (s Schedule) withDefaults(def Schedule) Schedule {
+	if s.Interval <= 0 {
+		s.Interval = def.Interval
+	}
+	if s.Retention <= 0 {
+		s.Retention = def.Retention
+	}
+	return s
+}
+
+type JobsDeps struct {
+	Store     Store
+	BatchSize int32
+
+	LinkCodes         Schedule
+	Sessions          Schedule
+	Outbox            Schedule
+	BotMessages       Schedule
+	WebhookDeliveries Schedule
+	BotNonces         Schedule
+}
+
+// Jobs собирает job-ы очистки для scheduler-а. Новые таблицы с TTL (reset-токены и т.п.)
+// добавляются сюда же: метод Purge* в Store и строка ниже.
+func Jobs(deps JobsDeps) []scheduler.Job {
+	batch := deps.BatchSize
+	if batch <= 0 {
+		batch = defaultBatchSize
+	}
+	all := []struct {
+		name  string
+		sched Schedule
+		purge purgeFunc
+	}{
+		{"janitor.link_codes", deps.LinkCodes.withDefaults(defaultLinkCodes), deps.Store.PurgeExpiredLinkCodes},
+		{"janitor.sessions", deps.Sessions.withDefaults(defaultSessions), deps.Store.PurgeExpiredSessions},
+		{"janitor.outbox", deps.Outbox.withDefaults(defaultOutbox), deps.Store.PurgePublishedOutbox},
+		{"janitor.bot_messages", deps.BotMessages.withDefaults(defaultBotMessages), deps.Store.PurgeDeliveredBotMessages},
+		{"janitor.webhook_deliveries", deps.WebhookDeliveries.withDefaults(defaultWebhookDeliveries), deps.Store.PurgeDeliveredWebhookDeliveries},
+		{"janitor.bot_nonces", deps.BotNonces.withDefaults(defaultBotNonces), deps.Store.PurgeExpiredBotNonces},
+	}
+
+	var jobs []scheduler.Job
+	for _, j := range all {
+		if j.sched.Disabled {
+			continue
+		}
+		jobs = append(jobs, newJob(j.name, j.sched, batch, j.purge))
+	}
+	return jobs
+}
+
+func newJob(name string, sched Schedule, batch int32, purge purgeFunc) scheduler.Job {
+	purged := rowsPurged.WithLabelValues(name)
+	return scheduler.Job{
+		Name:     name,
+		Interval: sched.Interval,
+		Run: func(ctx context.Context) (int64, error) {
+			before := time.Now().Add(-sched.Retention)
+
+			// батчами, пока не выгребем всё; неполный батч — больше нечего удалять
+			var total int64
+			for {
+				n, err := purge(ctx, before, batch)
+				total += n
+				purged.Add(float64(n))
+				if err != nil {
+					return total, err
+				}
+				if n < int64(batch) {
+					return total, nil
+				}
+			}
+		},
+	}
+}
@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/logger"
+)
+
+// sendTimeout — сколько ждём почту и очередь бота на одно уведомление.
+const sendTimeout = 15 * time.Second
+
+type Mailer interface {
+	SendMail(ctx context.Context, to, subject, body string) error
+}
+
+type BotQueue interface {
+	EnqueueBotMessage(ctx context.Context, chatID int64, text string) error
+}
+
+// Notifier рассылает уведомления в фоне, чтобы SMTP не задерживал логин.
+type Notifier struct {
+	mailer Mailer
+	queue  BotQueue
+
+	wg sync.WaitGroup
+}
+
+type NotifierDeps struct {
+	Mailer Mailer
+	Queue  BotQueue
+}
+
+func New(deps NotifierDeps) *Notifier {
+	return &Notifier{
+		mailer: deps.Mailer,
+		queue:  deps.Queue,
+	}
+}
+
+func (n *Notifier) NotifyNewLogin(ctx context.Context, notice models.LoginNotice) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sendTimeout)
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		defer cancel()
+
+		text := newLoginText(notice)
+		if notice.Email != "" {
+			if err := n.mailer.SendMail(ctx, notice.Email, "Вход в аккаунт BotTrade", text); err != nil {
+				logger.Log.Errorf("notify: new login mail for user %d: %s", notice.UserID, err)
+			}
+		}
+		for _, chatID := range notice.ChatIDs {
+			if err := n.queue.EnqueueBotMessage(ctx, chatID, text); err != nil {
+				logger.Log.Errorf("notify: new login bot message for user %d: %s", notice.UserID, err)
+			}
+		}
+	}()
+}
+
+// Close дожидается отправки уже начатых уведомлений.
+func (n *Notifier) Close() {
+	n.wg.Wait()
+}
+
+func newLoginText(notice models.LoginNotice) string {
+	var what []string
+	if notice.NewDevice {
+		what = append(what, "нового устройства")
+	}
+	if notice.NewIP {
+		what = append(what, "нового IP-адреса")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Выполнен вход в ваш аккаунт BotTrade с %s.\n\n", strings.Join(what, " и "))
+	fmt.Fprintf(&b, "Устройство: %s\n", notice.DeviceName)
+	if notice.IP != "" {
+		fmt.Fprintf(&b, "IP: %s\n", notice.IP)
+	}
+	fmt.Fprintf(&b, "Время: %s\n\n", notice.At.UTC().Format("02.01.2006 15:04 MST"))
+	b.WriteString("Если это были не вы — смените пароль и обратитесь в поддержку.")
+	return b.String()
+}
+
+// LogMailer пишет письма в лог — для локальной разработки без SMTP.
+type LogMailer struct{}
+
+func (LogMailer) SendMail(_ context.Context, to, subject, body string) error {
+	logger.Log.WithField("to", to).Infof("mail %q:\n%s", subject, body)
+	return nil
+}
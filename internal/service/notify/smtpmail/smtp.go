@@ -0,0 +1,91 @@
+package smtpmail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/config"
+)
+
+var ErrInvalidConfig = errors.New("invalid smtp config")
+
+type Mailer struct {
+	addr string
+	host string
+	from string
+	auth smtp.Auth
+}
+
+func New(cfg config.SMTP) (*Mailer, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("%w: host is required", ErrInvalidConfig)
+	}
+	if cfg.From == "" {
+		return nil, fmt.Errorf("%w: from is required", ErrInvalidConfig)
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+	}
+
+	m := &Mailer{
+		addr: net.JoinHostPort(cfg.Host, strconv.Itoa(port)),
+		host: cfg.Host,
+		from: cfg.From,
+	}
+	if cfg.Username != "" {
+		// PlainAuth сам откажется работать без TLS (кроме localhost)
+		m.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return m, nil
+}
+
+func (m *Mailer) SendMail(ctx context.Context, to, subject, body string) error {
+	if strings.ContainsAny(to, "\r\n") {
+		return fmt.Errorf("bad recipient address")
+	}
+
+	msg := buildMessage(m.from, to, subject, body)
+
+	// net/smtp не принимает ctx — ограничиваем отправку дедлайном в отдельной горутине
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- smtp.SendMail(m.addr, m.auth, m.from, []string{to}, msg)
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("smtp send: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("smtp send: %w", ctx.Err())
+	}
+}
+
+func buildMessage(from, to, subject, body string) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + to + "\r\n")
+	b.WriteString("Subject: " + mimeEncode(subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// mimeEncode кодирует заголовок по RFC 2047 — тема письма на кириллице.
+func mimeEncode(s string) string {
+	return mime.BEncoding.Encode("UTF-8", s)
+}
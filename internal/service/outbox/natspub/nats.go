@@ -0,0 +1,57 @@
+package natspub
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/config"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const defaultSubjectPrefix = "bottrade.auth"
+
+// Publisher публикует события в JetStream: subject = <prefix>.<event type>,
+// например bottrade.auth.user.registered. Stream на эти subjects создаётся заранее.
+// Nats-Msg-Id = id строки outbox, поэтому повтор после сбоя отсеивается окном дедупликации.
+type Publisher struct {
+	nc     *nats.Conn
+	js     jetstream.JetStream
+	prefix string
+}
+
+func New(cfg config.NATS) (*Publisher, error) {
+	nc, err := nats.Connect(cfg.URL, nats.Name("bottrade-auth-outbox"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("nats connect: %w", err)
+	}
+	js, err := jetstream.New(nc)
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("jetstream: %w", err)
+	}
+
+	prefix := cfg.SubjectPrefix
+	if prefix == "" {
+		prefix = defaultSubjectPrefix
+	}
+	return &Publisher{nc: nc, js: js, prefix: prefix}, nil
+}
+
+func (p *Publisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	msg := nats.NewMsg(p.prefix + "." + event.Type)
+	msg.Data = event.Payload
+	msg.Header.Set("Event-Type", event.Type)
+	msg.Header.Set("Aggregate-Id", event.AggregateID)
+
+	_, err := p.js.PublishMsg(ctx, msg, jetstream.WithMsgID(strconv.FormatInt(event.ID, 10)))
+	return err
+}
+
+func (p *Publisher) Close() {
+	if err := p.nc.Drain(); err != nil {
+		p.nc.Close()
+	}
+}
@@ -0,0 +1,195 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/logger"
+)
+
+const (
+	defaultPollInterval = 2 * time.Second
+	defaultBatchSize    = 100
+	defaultMaxAttempts  = 10
+
+	// publishTimeout ограничивает одну публикацию; lease берётся с запасом на весь батч.
+	publishTimeout = 10 * time.Second
+
+	baseBackoff = 5 * time.Second
+	maxBackoff  = 10 * time.Minute
+)
+
+type Store interface {
+	ClaimOutboxEvents(ctx context.Context, limit int32, lease time.Duration) ([]models.OutboxEvent, error)
+	MarkOutboxPublished(ctx context.Context, id int64) error
+	MarkOutboxFailed(ctx context.Context, id int64, lastErr string, nextAttemptAt time.Time) error
+	MarkOutboxDead(ctx context.Context, id int64, lastErr string) error
+}
+
+// Publisher доставляет событие в шину. nil-ошибка означает, что шина подтвердила приём.
+type Publisher interface {
+	Publish(ctx context.Context, event models.OutboxEvent) error
+}
+
+// Relay переносит события из outbox в Publisher: at-least-once,
+// с экспоненциальным backoff и переводом в dead letter после maxAttempts.
+type Relay struct {
+	store     Store
+	publisher Publisher
+
+	pollInterval time.Duration
+	batchSize    int32
+	maxAttempts  int32
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type RelayDeps struct {
+	Store     Store
+	Publisher Publisher
+
+	PollInterval time.Duration
+	BatchSize    int32
+	MaxAttempts  int32
+}
+
+func NewRelay(deps RelayDeps) *Relay {
+	r := &Relay{
+		store:        deps.Store,
+		publisher:    deps.Publisher,
+		pollInterval: deps.PollInterval,
+		batchSize:    deps.BatchSize,
+		maxAttempts:  deps.MaxAttempts,
+	}
+	if r.pollInterval <= 0 {
+		r.pollInterval = defaultPollInterval
+	}
+	if r.batchSize <= 0 {
+		r.batchSize = defaultBatchSize
+	}
+	if r.maxAttempts <= 0 {
+		r.maxAttempts = defaultMaxAttempts
+	}
+	return r
+}
+
+// Start запускает воркер в фоне; Stop останавливает его и дожидается текущего батча.
+func (r *Relay) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.run(ctx)
+	}()
+}
+
+func (r *Relay) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+}
+
+func (r *Relay) run(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// полный батч — сразу берём следующий, не дожидаясь тика
+		for {
+			n, err := r.relayBatch(ctx)
+			if err != nil {
+				logger.Log.Errorf("outbox: relay batch: %s", err)
+			}
+			if err != nil || n < int(r.batchSize) {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Relay) relayBatch(ctx context.Context) (int, error) {
+	if ctx.Err() != nil {
+		return 0, nil
+	}
+
+	lease := publishTimeout * time.Duration(r.batchSize)
+	events, err := r.store.ClaimOutboxEvents(ctx, r.batchSize, lease)
+	if err != nil {
+		return 0, fmt.Errorf("claim: %w", err)
+	}
+
+	// отметки пишем даже при остановке и после ошибки по одному событию: иначе остальные ждут конца lease
+	storeCtx := context.WithoutCancel(ctx)
+	var errs []error
+	for _, event := range events {
+		pubCtx, cancel := context.WithTimeout(ctx, publishTimeout)
+		pubErr := r.publisher.Publish(pubCtx, event)
+		cancel()
+
+		if err := r.settle(storeCtx, event, pubErr); err != nil {
+			errs = append(errs, fmt.Errorf("settle event %d: %w", event.ID, err))
+		}
+	}
+	return len(events), errors.Join(errs...)
+}
+
+func (r *Relay) settle(ctx context.Context, event models.OutboxEvent, pubErr error) error {
+	if pubErr == nil {
+		return r.store.MarkOutboxPublished(ctx, event.ID)
+	}
+
+	if event.Attempts >= r.maxAttempts {
+		logger.Log.Errorf("outbox: event %d (%s) moved to dead letter after %d attempts: %s",
+			event.ID, event.Type, event.Attempts, pubErr)
+		return r.store.MarkOutboxDead(ctx, event.ID, pubErr.Error())
+	}
+
+	delay := backoff(event.Attempts)
+	logger.Log.Warnf("outbox: publish event %d (%s), attempt %d, retry in %s: %s",
+		event.ID, event.Type, event.Attempts, delay, pubErr)
+	return r.store.MarkOutboxFailed(ctx, event.ID, pubErr.Error(), time.Now().Add(delay))
+}
+
+// backoff: 5s, 10s, 20s, ... но не больше maxBackoff.
+func backoff(attempt int32) time.Duration {
+	delay := baseBackoff
+	for i := int32(1); i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+// MultiPublisher публикует событие во все шины по очереди. Ошибка любой — повтор для всех,
+// поэтому каждая шина должна сама отсеивать дубли (NATS по Nats-Msg-Id, webhooks по event id).
+type MultiPublisher []Publisher
+
+func (m MultiPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	for _, p := range m {
+		if err := p.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LogPublisher пишет события в лог — для локальной разработки без брокера.
+type LogPublisher struct{}
+
+func (LogPublisher) Publish(_ context.Context, event models.OutboxEvent) error {
+	logger.Log.WithField("event_id", event.ID).Infof("event %s: %s", event.Type, event.Payload)
+	return nil
+}
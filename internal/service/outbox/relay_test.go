@@ -0,0 +1,110 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+var errStoreDown = errors.New("store down")
+
+// fakeStore отдаёт события один раз и запоминает, чем они закончились.
+type fakeStore struct {
+	mu      sync.Mutex
+	events  []models.OutboxEvent
+	results map[int64]string // published, failed, dead
+	failIDs map[int64]bool   // Mark* по этим событиям возвращают ошибку
+}
+
+func newFakeStore(events ...models.OutboxEvent) *fakeStore {
+	return &fakeStore{events: events, results: map[int64]string{}, failIDs: map[int64]bool{}}
+}
+
+func (s *fakeStore) ClaimOutboxEvents(_ context.Context, limit int32, _ time.Duration) ([]models.OutboxEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := min(int(limit), len(s.events))
+	events := s.events[:n]
+	s.events = s.events[n:]
+	return events, nil
+}
+
+func (s *fakeStore) MarkOutboxPublished(_ context.Context, id int64) error {
+	return s.mark(id, "published")
+}
+
+func (s *fakeStore) MarkOutboxFailed(_ context.Context, id int64, _ string, _ time.Time) error {
+	return s.mark(id, "failed")
+}
+
+func (s *fakeStore) MarkOutboxDead(_ context.Context, id int64, _ string) error {
+	return s.mark(id, "dead")
+}
+
+func (s *fakeStore) mark(id int64, result string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failIDs[id] {
+		return errStoreDown
+	}
+	s.results[id] = result
+	return nil
+}
+
+type publisherFunc func(ctx context.Context, event models.OutboxEvent) error
+
+func (f publisherFunc) Publish(ctx context.Context, event models.OutboxEvent) error {
+	return f(ctx, event)
+}
+
+func TestRelaySettlesEvents(t *testing.T) {
+	errBus := errors.New("bus down")
+	store := newFakeStore(
+		models.OutboxEvent{ID: 1, Attempts: 1},
+		models.OutboxEvent{ID: 2, Attempts: 1},
+		models.OutboxEvent{ID: 3, Attempts: 3},
+	)
+	r := NewRelay(RelayDeps{
+		Store:       store,
+		MaxAttempts: 3,
+		Publisher: publisherFunc(func(_ context.Context, event models.OutboxEvent) error {
+			if event.ID == 1 {
+				return nil
+			}
+			return errBus
+		}),
+	})
+
+	if _, err := r.relayBatch(context.Background()); err != nil {
+		t.Fatalf("relayBatch: %v", err)
+	}
+	want := map[int64]string{1: "published", 2: "failed", 3: "dead"}
+	for id, result := range want {
+		if got := store.results[id]; got != result {
+			t.Errorf("event %d: %q, want %q", id, got, result)
+		}
+	}
+}
+
+func TestRelaySettlesWholeBatchOnStoreError(t *testing.T) {
+	store := newFakeStore(models.OutboxEvent{ID: 1}, models.OutboxEvent{ID: 2}, models.OutboxEvent{ID: 3})
+	store.failIDs[1] = true
+	r := NewRelay(RelayDeps{Store: store, Publisher: LogPublisher{}})
+
+	n, err := r.relayBatch(context.Background())
+	if !errors.Is(err, errStoreDown) {
+		t.Fatalf("relayBatch error = %v, want %v", err, errStoreDown)
+	}
+	if n != 3 {
+		t.Errorf("relayBatch n = %d, want 3", n)
+	}
+	for _, id := range []int64{2, 3} {
+		if got := store.results[id]; got != "published" {
+			t.Errorf("event %d not settled after earlier store error: %q", id, got)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package passpolicy
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+)
+
+// Формат файла: magic, m (бит), k (хешей), n (записей), затем m/64 слов little-endian.
+const bloomMagic = "BTBLOOM1"
+
+var ErrBadBloomFile = errors.New("bad bloom filter file")
+
+// bloom — фильтр по SHA-1 паролей. Хеш уже равномерный, поэтому позиции берутся из его
+// байтов (double hashing), без повторного хеширования.
+type bloom struct {
+	bits []uint64
+	m    uint64
+	k    uint32
+	n    uint64
+}
+
+func newBloom(n uint64, fpRate float64) *bloom {
+	if n == 0 {
+		n = 1
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	m = (m + 63) / 64 * 64
+	k := uint32(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k == 0 {
+		k = 1
+	}
+	return &bloom{bits: make([]uint64, m/64), m: m, k: k}
+}
+
+func (b *bloom) add(sum [sha1.Size]byte) {
+	h1, h2 := bloomHashes(sum)
+	for i := uint64(0); i < uint64(b.k); i++ {
+		pos := (h1 + i*h2) % b.m
+		b.bits[pos/64] |= 1 << (pos % 64)
+	}
+	b.n++
+}
+
+func (b *bloom) has(sum [sha1.Size]byte) bool {
+	h1, h2 := bloomHashes(sum)
+	for i := uint64(0); i < uint64(b.k); i++ {
+		pos := (h1 + i*h2) % b.m
+		if b.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func bloomHashes(sum [sha1.Size]byte) (uint64, uint64) {
+	return binary.BigEndian.Uint64(sum[0:8]), binary.BigEndian.Uint64(sum[8:16]) | 1
+}
+
+func (b *bloom) writeTo(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	hdr := make([]byte, 0, len(bloomMagic)+20)
+	hdr = append(hdr, bloomMagic...)
+	hdr = binary.LittleEndian.AppendUint64(hdr, b.m)
+	hdr = binary.LittleEndian.AppendUint32(hdr, b.k)
+	hdr = binary.LittleEndian.AppendUint64(hdr, b.n)
+	if _, err := bw.Write(hdr); err != nil {
+		return err
+	}
+	word := make([]byte, 8)
+	for _, v := range b.bits {
+		binary.LittleEndian.PutUint64(word, v)
+		if _, err := bw.Write(word); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// loadBloom читает фильтр потоком прямо в bits: фильтр на полный дамп HIBP весит
+// гигабайты, и вторую копию файла в памяти держать нельзя.
+func loadBloom(path string) (*bloom, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	const hdrLen = len(bloomMagic) + 20
+	r := bufio.NewReader(f)
+	hdr := make([]byte, hdrLen)
+	if _, err := io.ReadFull(r, hdr); err != nil || string(hdr[:len(bloomMagic)]) != bloomMagic {
+		return nil, ErrBadBloomFile
+	}
+	b := &bloom{
+		m: binary.LittleEndian.Uint64(hdr[8:16]),
+		k: binary.LittleEndian.Uint32(hdr[16:20]),
+		n: binary.LittleEndian.Uint64(hdr[20:28]),
+	}
+	// размер проверяем до make: битый заголовок не должен заказать гигантский слайс
+	if b.m == 0 || b.m%64 != 0 || b.k == 0 || uint64(st.Size()-int64(hdrLen)) != b.m/8 {
+		return nil, ErrBadBloomFile
+	}
+
+	b.bits = make([]uint64, b.m/64)
+	word := make([]byte, 8)
+	for i := range b.bits {
+		if _, err := io.ReadFull(r, word); err != nil {
+			return nil, ErrBadBloomFile
+		}
+		b.bits[i] = binary.LittleEndian.Uint64(word)
+	}
+	return b, nil
+}
+
+// BuildBloomFile строит фильтр из дампа Have I Been Pwned в SHA-1 формате
+// ("<SHA1 hex>:<count>" по строке) и пишет его в out. Дамп читается дважды:
+// сначала считаются строки, чтобы подобрать размер под fpRate.
+func BuildBloomFile(dumpPath, outPath string, fpRate float64, log io.Writer) error {
+	if fpRate <= 0 || fpRate >= 1 {
+		return fmt.Errorf("fp rate must be in (0, 1), got %v", fpRate)
+	}
+
+	var n uint64
+	if err := scanDump(dumpPath, func([sha1.Size]byte) { n++ }); err != nil {
+		return err
+	}
+	b := newBloom(n, fpRate)
+	fmt.Fprintf(log, "%d hashes, %d MiB filter, %d hash functions\n", n, b.m/8/1024/1024, b.k)
+
+	if err := scanDump(dumpPath, b.add); err != nil {
+		return err
+	}
+
+	tmp := outPath + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := b.writeTo(f); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, outPath)
+}
+
+func scanDump(path string, fn func([sha1.Size]byte)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		hash, _, _ := strings.Cut(text, ":")
+		var sum [sha1.Size]byte
+		if len(hash) != hex.EncodedLen(sha1.Size) {
+			return fmt.Errorf("%s:%d: not a SHA-1 hash", path, line)
+		}
+		if _, err := hex.Decode(sum[:], []byte(hash)); err != nil {
+			return fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		fn(sum)
+	}
+	return sc.Err()
+}
@@ -0,0 +1,120 @@
+package passpolicy
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/config"
+)
+
+// writeDump пишет дамп в формате HIBP для паролей pw-0 … pw-(n-1).
+func writeDump(t *testing.T, dir string, n int) string {
+	t.Helper()
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		sum := sha1.Sum([]byte(fmt.Sprintf("pw-%d", i)))
+		fmt.Fprintf(&sb, "%s:%d\n", strings.ToUpper(hex.EncodeToString(sum[:])), i+1)
+	}
+	path := filepath.Join(dir, "dump.txt")
+	if err := os.WriteFile(path, []byte(sb.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func buildTestBloom(t *testing.T, n int, fpRate float64) string {
+	t.Helper()
+	dir := t.TempDir()
+	out := filepath.Join(dir, "breached.bloom")
+	if err := BuildBloomFile(writeDump(t, dir, n), out, fpRate, io.Discard); err != nil {
+		t.Fatalf("BuildBloomFile: %v", err)
+	}
+	return out
+}
+
+func TestBloomRoundTrip(t *testing.T) {
+	const n = 2000
+	path := buildTestBloom(t, n, 0.01)
+
+	b, err := loadBloom(path)
+	if err != nil {
+		t.Fatalf("loadBloom: %v", err)
+	}
+	if b.n != n || b.m == 0 || b.k == 0 {
+		t.Fatalf("header m=%d k=%d n=%d", b.m, b.k, b.n)
+	}
+	for i := 0; i < n; i++ {
+		if !b.has(sha1.Sum([]byte(fmt.Sprintf("pw-%d", i)))) {
+			t.Fatalf("pw-%d missing after load", i)
+		}
+	}
+
+	p, err := New(config.PasswordPolicy{BreachedBloomFile: path})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if got := violatedRules(t, p.Check("pw-1234-x", "")); got != nil {
+		t.Fatalf("clean password flagged: %v", got)
+	}
+	if got := violatedRules(t, p.Check("pw-1234", "")); len(got) != 2 || got[1] != RuleBreached {
+		t.Fatalf("breached password violations = %v", got) // pw-1234 ещё и короче min_length
+	}
+}
+
+func TestBloomFalsePositiveRate(t *testing.T) {
+	const (
+		n      = 10000
+		probes = 100000
+		fpRate = 0.01
+	)
+	b := newBloom(n, fpRate)
+	for i := 0; i < n; i++ {
+		b.add(sha1.Sum([]byte(fmt.Sprintf("in-%d", i))))
+	}
+
+	hits := 0
+	for i := 0; i < probes; i++ {
+		if b.has(sha1.Sum([]byte(fmt.Sprintf("out-%d", i)))) {
+			hits++
+		}
+	}
+	// запас в 2 раза: фильтр считается по формуле, а не подгоняется под выборку
+	if got := float64(hits) / probes; got > 2*fpRate {
+		t.Fatalf("false positive rate %.4f, want <= %.4f", got, 2*fpRate)
+	}
+}
+
+func TestLoadBloomRejectsBadFiles(t *testing.T) {
+	good, err := os.ReadFile(buildTestBloom(t, 100, 0.01))
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrupt := func(f func([]byte) []byte) []byte { return f(append([]byte(nil), good...)) }
+
+	tests := map[string][]byte{
+		"empty":     nil,
+		"bad magic": corrupt(func(b []byte) []byte { b[0] = 'X'; return b }),
+		"truncated": good[:len(good)-8],
+		"trailing":  append(append([]byte(nil), good...), 0),
+		"zero k":    corrupt(func(b []byte) []byte { copy(b[16:20], []byte{0, 0, 0, 0}); return b }),
+		"huge m":    corrupt(func(b []byte) []byte { copy(b[8:16], []byte{0, 0, 0, 0, 0, 0, 0, 0x40}); return b }),
+	}
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "bad.bloom")
+			if err := os.WriteFile(path, raw, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := loadBloom(path); !errors.Is(err, ErrBadBloomFile) {
+				t.Fatalf("loadBloom err = %v, want ErrBadBloomFile", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package passpolicy
+
+import (
+	"bufio"
+	"crypto/sha1"
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/config"
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+)
+
+// Правила политики — коды в PasswordViolation.Rule.
+const (
+	RuleMinLength  = "min_length"
+	RuleMaxLength  = "max_length"
+	RuleLower      = "lowercase"
+	RuleUpper      = "uppercase"
+	RuleDigit      = "digit"
+	RuleSymbol     = "symbol"
+	RuleMinClasses = "min_classes"
+	RuleCommon     = "common"
+	RuleEmail      = "email"
+	RuleBreached   = "breached"
+)
+
+const (
+	defaultMinLength = 8
+	defaultMaxLength = 128
+
+	// короче — слишком много ложных срабатываний ("al" в любом пароле)
+	minEmailLocalLen = 3
+)
+
+//go:embed common_passwords.txt
+var commonPasswords string
+
+// Policy проверяет новые пароли (регистрация, смена). Логин политикой не ограничен:
+// старые пароли могли быть заданы до её ужесточения.
+type Policy struct {
+	minLength int
+	maxLength int
+
+	requireLower  bool
+	requireUpper  bool
+	requireDigit  bool
+	requireSymbol bool
+	minClasses    int
+
+	denylist map[string]bool // в нижнем регистре
+	breached *bloom          // nil — проверка выключена
+}
+
+func New(cfg config.PasswordPolicy) (*Policy, error) {
+	p := &Policy{
+		minLength:     cfg.MinLength,
+		maxLength:     cfg.MaxLength,
+		requireLower:  cfg.RequireLower,
+		requireUpper:  cfg.RequireUpper,
+		requireDigit:  cfg.RequireDigit,
+		requireSymbol: cfg.RequireSymbol,
+		minClasses:    cfg.MinClasses,
+		denylist:      map[string]bool{},
+	}
+	if p.minLength <= 0 {
+		p.minLength = defaultMinLength
+	}
+	if p.maxLength <= 0 {
+		p.maxLength = defaultMaxLength
+	}
+	if p.maxLength < p.minLength {
+		return nil, fmt.Errorf("password policy: max_length %d < min_length %d", p.maxLength, p.minLength)
+	}
+	if p.minClasses < 0 || p.minClasses > 4 {
+		return nil, fmt.Errorf("password policy: min_classes must be 0..4")
+	}
+
+	addDenylist(p.denylist, commonPasswords)
+	if cfg.DenylistFile != "" {
+		raw, err := os.ReadFile(cfg.DenylistFile)
+		if err != nil {
+			return nil, fmt.Errorf("password policy: denylist: %w", err)
+		}
+		addDenylist(p.denylist, string(raw))
+	}
+
+	if cfg.BreachedBloomFile != "" {
+		b, err := loadBloom(cfg.BreachedBloomFile)
+		if err != nil {
+			return nil, fmt.Errorf("password policy: breached bloom %s: %w", cfg.BreachedBloomFile, err)
+		}
+		p.breached = b
+	}
+	return p, nil
+}
+
+func addDenylist(dst map[string]bool, list string) {
+	sc := bufio.NewScanner(strings.NewReader(list))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		dst[strings.ToLower(line)] = true
+	}
+}
+
+// Check возвращает *modelerrors.PasswordPolicyError со всеми нарушенными правилами или nil.
+// email — адрес владельца, его local part в пароле запрещён.
+func (p *Policy) Check(password, email string) error {
+	var violations []modelerrors.PasswordViolation
+	violate := func(rule string, limit int, format string, args ...any) {
+		violations = append(violations, modelerrors.PasswordViolation{
+			Rule:        rule,
+			Description: fmt.Sprintf(format, args...),
+			Limit:       limit,
+		})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < p.minLength {
+		violate(RuleMinLength, p.minLength, "must be at least %d characters", p.minLength)
+	}
+	if length > p.maxLength {
+		violate(RuleMaxLength, p.maxLength, "must be at most %d characters", p.maxLength)
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if p.requireLower && !lower {
+		violate(RuleLower, 0, "must contain a lowercase letter")
+	}
+	if p.requireUpper && !upper {
+		violate(RuleUpper, 0, "must contain an uppercase letter")
+	}
+	if p.requireDigit && !digit {
+		violate(RuleDigit, 0, "must contain a digit")
+	}
+	if p.requireSymbol && !symbol {
+		violate(RuleSymbol, 0, "must contain a symbol")
+	}
+	if classes := countTrue(lower, upper, digit, symbol); classes < p.minClasses {
+		violate(RuleMinClasses, p.minClasses, "must contain at least %d of: lowercase, uppercase, digits, symbols", p.minClasses)
+	}
+
+	folded := strings.ToLower(password)
+	if p.denylist[folded] {
+		violate(RuleCommon, 0, "is too common")
+	}
+	if local, _, ok := strings.Cut(strings.ToLower(email), "@"); ok && utf8.RuneCountInString(local) >= minEmailLocalLen &&
+		strings.Contains(folded, local) {
+		violate(RuleEmail, 0, "must not contain the email address")
+	}
+	if p.breached != nil && p.breached.has(sha1.Sum([]byte(password))) {
+		violate(RuleBreached, 0, "appeared in a known data breach")
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return &modelerrors.PasswordPolicyError{Violations: violations}
+}
+
+func countTrue(flags ...bool) int {
+	n := 0
+	for _, f := range flags {
+		if f {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,91 @@
+package passpolicy
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/config"
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+)
+
+func TestCheck(t *testing.T) {
+	strict := config.PasswordPolicy{
+		MinLength:     10,
+		MaxLength:     20,
+		RequireLower:  true,
+		RequireUpper:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+
+	tests := []struct {
+		name     string
+		cfg      config.PasswordPolicy
+		password string
+		email    string
+		want     []string
+	}{
+		{name: "default ok", password: "violet-harbor-42", email: "ivan@example.com"},
+		{name: "default too short", password: "a1b2c3", want: []string{RuleMinLength}},
+		{name: "default too long", password: string(make([]byte, 129)), want: []string{RuleMaxLength}},
+		{name: "length in runes", cfg: config.PasswordPolicy{MinLength: 4}, password: "пароль"},
+		{name: "common any case", password: "PassWord", want: []string{RuleCommon}},
+		{name: "contains email local part", password: "my-ivanov-secret", email: "Ivanov@example.com", want: []string{RuleEmail}},
+		{name: "short local part ignored", password: "al-secret-words", email: "al@example.com"},
+		{name: "strict ok", cfg: strict, password: "Violet-Harbor-42"},
+		{
+			name:     "strict all violations at once",
+			cfg:      strict,
+			password: "short",
+			want:     []string{RuleMinLength, RuleUpper, RuleDigit, RuleSymbol},
+		},
+		{
+			name:     "min classes",
+			cfg:      config.PasswordPolicy{MinClasses: 3},
+			password: "violetharbor42",
+			want:     []string{RuleMinClasses},
+		},
+		{name: "space counts as symbol", cfg: config.PasswordPolicy{MinClasses: 3}, password: "violet harbor 42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(tt.cfg)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if got := violatedRules(t, p.Check(tt.password, tt.email)); !slices.Equal(got, tt.want) {
+				t.Fatalf("violations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRejectsBadConfig(t *testing.T) {
+	for _, cfg := range []config.PasswordPolicy{
+		{MinLength: 20, MaxLength: 10},
+		{MinClasses: 5},
+		{DenylistFile: "/nonexistent/denylist.txt"},
+		{BreachedBloomFile: "/nonexistent/breached.bloom"},
+	} {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("New(%+v) = nil error", cfg)
+		}
+	}
+}
+
+func violatedRules(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var perr *modelerrors.PasswordPolicyError
+	if !errors.As(err, &perr) {
+		t.Fatalf("error %v is not *PasswordPolicyError", err)
+	}
+	rules := make([]string, 0, len(perr.Violations))
+	for _, v := range perr.Violations {
+		rules = append(rules, v.Rule)
+	}
+	return rules
+}
@@ -0,0 +1,154 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Job — периодическая задача. Interval должен быть > 0.
+// Run возвращает число обработанных строк (для лога).
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) (int64, error)
+}
+
+// Locker — выбор лидера: job выполняет только реплика, взявшая lock с именем job-а.
+// Lock исключает лишь одновременные запуски, поэтому под ним реплика ещё отмечает запуск
+// через ClaimRun: claimed = false — job уже запускали меньше minGap назад.
+type Locker interface {
+	TryLock(ctx context.Context, name string) (unlock func(), acquired bool, err error)
+	ClaimRun(ctx context.Context, name string, minGap time.Duration) (claimed bool, err error)
+}
+
+var (
+	jobRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "bottrade_scheduler_job_runs_total",
+		Help: "Background job runs by result: ok, error, skipped (lock held or job already run by another replica).",
+	}, []string{"job", "result"})
+	jobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "bottrade_scheduler_job_duration_seconds",
+		Help:    "Background job run duration.",
+		Buckets: prometheus.ExponentialBuckets(0.01, 4, 8),
+	}, []string{"job"})
+)
+
+// Scheduler запускает job-ы по интервалам, каждый в своей горутине.
+type Scheduler struct {
+	locker Locker
+	jobs   []Job
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type SchedulerDeps struct {
+	Locker Locker
+	Jobs   []Job
+}
+
+func New(deps SchedulerDeps) *Scheduler {
+	return &Scheduler{
+		locker: deps.Locker,
+		jobs:   deps.Jobs,
+	}
+}
+
+// Start запускает job-ы в фоне; Stop останавливает их и дожидается текущих запусков.
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.loop(ctx, job)
+		}()
+	}
+}
+
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	// случайный сдвиг первого запуска, чтобы реплики и job-ы не стартовали разом
+	first := time.NewTimer(rand.N(job.Interval/10 + time.Second))
+	defer first.Stop()
+	select {
+	case <-ctx.Done():
+		return
+	case <-first.C:
+	}
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		s.runOnce(ctx, job)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	unlock, acquired, err := s.locker.TryLock(ctx, job.Name)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			logger.Log.Errorf("scheduler: job %s: lock: %s", job.Name, err)
+			jobRuns.WithLabelValues(job.Name, "error").Inc()
+		}
+		return
+	}
+	if !acquired {
+		jobRuns.WithLabelValues(job.Name, "skipped").Inc()
+		return
+	}
+	defer unlock()
+
+	// тикеры реплик и сама отметка сдвинуты на доли секунды: без допуска свой следующий
+	// тик попадал бы чуть раньше интервала и пропускался
+	claimed, err := s.locker.ClaimRun(ctx, job.Name, job.Interval-job.Interval/10)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			logger.Log.Errorf("scheduler: job %s: claim run: %s", job.Name, err)
+			jobRuns.WithLabelValues(job.Name, "error").Inc()
+		}
+		return
+	}
+	if !claimed {
+		jobRuns.WithLabelValues(job.Name, "skipped").Inc()
+		return
+	}
+
+	// запуск не должен наползать на следующий тик
+	runCtx, cancel := context.WithTimeout(ctx, job.Interval)
+	defer cancel()
+
+	start := time.Now()
+	n, err := job.Run(runCtx)
+	jobDuration.WithLabelValues(job.Name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		logger.Log.Errorf("scheduler: job %s: %s", job.Name, err)
+		jobRuns.WithLabelValues(job.Name, "error").Inc()
+		return
+	}
+	jobRuns.WithLabelValues(job.Name, "ok").Inc()
+	if n > 0 {
+		logger.Log.Infof("scheduler: job %s: %d rows", job.Name, n)
+	}
+}
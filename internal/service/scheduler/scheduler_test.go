@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// fakeLocker — общие для реплик lock-и и отметки запусков, со своими часами.
+type fakeLocker struct {
+	mu    sync.Mutex
+	now   time.Time
+	locks map[string]bool
+	runs  map[string]time.Time
+}
+
+func newFakeLocker() *fakeLocker {
+	return &fakeLocker{
+		now:   time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		locks: map[string]bool{},
+		runs:  map[string]time.Time{},
+	}
+}
+
+func (l *fakeLocker) TryLock(_ context.Context, name string) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.locks[name] {
+		return nil, false, nil
+	}
+	l.locks[name] = true
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.locks, name)
+	}, true, nil
+}
+
+func (l *fakeLocker) ClaimRun(_ context.Context, name string, minGap time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if last, ok := l.runs[name]; ok && l.now.Sub(last) < minGap {
+		return false, nil
+	}
+	l.runs[name] = l.now
+	return true, nil
+}
+
+func (l *fakeLocker) advance(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.now = l.now.Add(d)
+}
+
+func TestJobRunsOncePerIntervalAcrossReplicas(t *testing.T) {
+	var runs atomic.Int64
+	job := Job{
+		Name:     "test_once_per_interval",
+		Interval: time.Minute,
+		Run: func(context.Context) (int64, error) {
+			runs.Add(1)
+			return 0, nil
+		},
+	}
+	locker := newFakeLocker()
+	replicas := []*Scheduler{
+		New(SchedulerDeps{Locker: locker, Jobs: []Job{job}}),
+		New(SchedulerDeps{Locker: locker, Jobs: []Job{job}}),
+	}
+	skipped := testutil.ToFloat64(jobRuns.WithLabelValues(job.Name, "skipped"))
+
+	// реплика B тикает на полинтервала позже A; тик A приходит чуть раньше интервала
+	const intervals = 5
+	for i := range intervals {
+		replicas[0].runOnce(context.Background(), job)
+		locker.advance(job.Interval / 2)
+		replicas[1].runOnce(context.Background(), job)
+		locker.advance(job.Interval/2 - 500*time.Millisecond)
+
+		if got := runs.Load(); got != int64(i+1) {
+			t.Fatalf("interval %d: job ran %d times, want %d", i+1, got, i+1)
+		}
+	}
+
+	if got := testutil.ToFloat64(jobRuns.WithLabelValues(job.Name, "skipped")) - skipped; got != intervals {
+		t.Errorf("skipped runs = %v, want %d", got, intervals)
+	}
+}
+
+func TestJobSkippedWhileLocked(t *testing.T) {
+	var runs atomic.Int64
+	job := Job{
+		Name:     "test_locked",
+		Interval: time.Minute,
+		Run: func(context.Context) (int64, error) {
+			runs.Add(1)
+			return 0, nil
+		},
+	}
+	locker := newFakeLocker()
+	unlock, _, _ := locker.TryLock(context.Background(), job.Name)
+
+	s := New(SchedulerDeps{Locker: locker, Jobs: []Job{job}})
+	s.runOnce(context.Background(), job)
+	if runs.Load() != 0 {
+		t.Fatal("job ran while another replica held the lock")
+	}
+	if _, ok := locker.runs[job.Name]; ok {
+		t.Fatal("run claimed without the lock")
+	}
+
+	unlock()
+	s.runOnce(context.Background(), job)
+	if runs.Load() != 1 {
+		t.Fatalf("job ran %d times after unlock, want 1", runs.Load())
+	}
+}
@@ -0,0 +1,171 @@
+package svcadmin
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+// sessionsInDetails — сколько последних сессий отдаём в GetUser.
+const sessionsInDetails = 50
+
+type AdminRepo interface {
+	GetUserByID(ctx context.Context, userID int32) (models.User, error)
+	ListUsers(ctx context.Context, filter models.UserFilter) ([]models.User, error)
+	BlockUser(ctx context.Context, userID int32) error
+	UnblockUser(ctx context.Context, userID int32) error
+	DeleteUser(ctx context.Context, userID int32) error
+	ResetUserMfa(ctx context.Context, userID int32) error
+
+	ListIdentitiesByUser(ctx context.Context, userID int32) ([]models.Identity, error)
+	ListSessionsByUser(ctx context.Context, userID int32, limit int32) ([]models.Session, error)
+	RevokeUserSessions(ctx context.Context, userID int32) (int64, error)
+
+	ListAuditEvents(ctx context.Context, filter models.AuditFilter) ([]models.AuditEvent, error)
+
+	AddOutboxEvent(ctx context.Context, eventType string, event models.UserEvent) error
+}
+
+// TxRunner выполняет fn в транзакции хранилища: repo внутри fn работает в ней.
+type TxRunner func(ctx context.Context, fn func(repo AdminRepo) error) error
+
+// AuditSink — журнал событий безопасности.
+type AuditSink interface {
+	Record(ctx context.Context, event models.AuditEvent)
+}
+
+type AdminUsecase struct {
+	repo   AdminRepo
+	withTx TxRunner
+	audit  AuditSink
+}
+
+type AdminUsecaseDeps struct {
+	Repo   AdminRepo
+	WithTx TxRunner
+	Audit  AuditSink
+}
+
+func New(deps AdminUsecaseDeps) *AdminUsecase {
+	return &AdminUsecase{
+		repo:   deps.Repo,
+		withTx: deps.WithTx,
+		audit:  deps.Audit,
+	}
+}
+
+func (a *AdminUsecase) ListUsers(ctx context.Context, filter models.UserFilter) ([]models.User, error) {
+	return a.repo.ListUsers(ctx, filter)
+}
+
+func (a *AdminUsecase) GetUser(ctx context.Context, userID int32) (models.UserDetails, error) {
+	u, err := a.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return models.UserDetails{}, notFound(err)
+	}
+
+	identities, err := a.repo.ListIdentitiesByUser(ctx, userID)
+	if err != nil {
+		return models.UserDetails{}, err
+	}
+	sessions, err := a.repo.ListSessionsByUser(ctx, userID, sessionsInDetails)
+	if err != nil {
+		return models.UserDetails{}, err
+	}
+
+	return models.UserDetails{
+		User:       u,
+		Identities: identities,
+		Sessions:   sessions,
+	}, nil
+}
+
+// BlockUser блокирует пользователя и отзывает все его сессии.
+func (a *AdminUsecase) BlockUser(ctx context.Context, userID int32) error {
+	var revoked int64
+	err := a.withTx(ctx, func(repo AdminRepo) error {
+		if err := repo.BlockUser(ctx, userID); err != nil {
+			return err
+		}
+		if err := repo.AddOutboxEvent(ctx, models.EventUserBlocked, models.UserEvent{UserID: userID}); err != nil {
+			return err
+		}
+		var err error
+		revoked, err = repo.RevokeUserSessions(ctx, userID)
+		return err
+	})
+	if err != nil {
+		return notFound(err)
+	}
+	a.recordAdminAction(ctx, models.AuditAdminUserBlocked, userID, nil)
+	a.recordSessionsRevoked(ctx, userID, "blocked", revoked)
+	return nil
+}
+
+func (a *AdminUsecase) UnblockUser(ctx context.Context, userID int32) error {
+	if err := a.repo.UnblockUser(ctx, userID); err != nil {
+		return notFound(err)
+	}
+	a.recordAdminAction(ctx, models.AuditAdminUserUnblocked, userID, nil)
+	return nil
+}
+
+// ForceLogout отзывает все активные сессии пользователя.
+func (a *AdminUsecase) ForceLogout(ctx context.Context, userID int32) (revoked int64, err error) {
+	if _, err := a.repo.GetUserByID(ctx, userID); err != nil {
+		return 0, notFound(err)
+	}
+	revoked, err = a.repo.RevokeUserSessions(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	a.recordAdminAction(ctx, models.AuditAdminForceLogout, userID, nil)
+	a.recordSessionsRevoked(ctx, userID, "force_logout", revoked)
+	return revoked, nil
+}
+
+func (a *AdminUsecase) DeleteUser(ctx context.Context, userID int32) error {
+	if err := a.repo.DeleteUser(ctx, userID); err != nil {
+		return notFound(err)
+	}
+	a.recordAdminAction(ctx, models.AuditAdminUserDeleted, userID, nil)
+	return nil
+}
+
+func (a *AdminUsecase) ResetMfa(ctx context.Context, userID int32) error {
+	if err := a.repo.ResetUserMfa(ctx, userID); err != nil {
+		return notFound(err)
+	}
+	a.recordAdminAction(ctx, models.AuditAdminMfaReset, userID, nil)
+	return nil
+}
+
+func (a *AdminUsecase) ListAuditEvents(ctx context.Context, filter models.AuditFilter) ([]models.AuditEvent, error) {
+	return a.repo.ListAuditEvents(ctx, filter)
+}
+
+// actor (админ) и его IP/user agent audit sink берёт из контекста запроса
+func (a *AdminUsecase) recordAdminAction(ctx context.Context, eventType string, userID int32, details map[string]string) {
+	a.audit.Record(ctx, models.AuditEvent{
+		Type:         eventType,
+		TargetUserID: userID,
+		Details:      details,
+	})
+}
+
+func (a *AdminUsecase) recordSessionsRevoked(ctx context.Context, userID int32, reason string, revoked int64) {
+	a.recordAdminAction(ctx, models.AuditSessionsRevoked, userID, map[string]string{
+		"reason":  reason,
+		"revoked": strconv.FormatInt(revoked, 10),
+	})
+}
+
+func notFound(err error) error {
+	if errors.Is(err, modelerrors.ErrNoRows) {
+		return modelerrors.ErrUserNotFound
+	}
+	return err
+}
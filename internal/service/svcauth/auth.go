@@ -3,6 +3,8 @@ package svcauth
 import (
 	"context"
 	"errors"
+	"strconv"
+	"time"
 
 	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
 	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
@@ -14,46 +16,161 @@ type Hasher interface {
 }
 
 type Tokener interface {
-	Token(userID int32) (accessToken string, exp int64, err error)
+	Token(userID int32, sessionID string) (accessToken string, exp int64, err error)
+	Parse(accessToken string) (userID int32, sessionID string, err error)
+}
+
+// PasswordPolicy проверяет новый пароль; ошибка — *modelerrors.PasswordPolicyError.
+type PasswordPolicy interface {
+	Check(password, email string) error
+}
+
+// EmailNormalizer даёт ключ, по которому email уникален (регистр, IDNA, правила провайдеров).
+type EmailNormalizer interface {
+	Normalize(email string) (string, error)
 }
 
 type AuthRepo interface {
 	CreateUser(ctx context.Context, user models.User) (int32, error)
-	GetByEmail(ctx context.Context, email string) (models.User, error)
+	GetByEmail(ctx context.Context, normalizedEmail string) (models.User, error)
+	GetUserByID(ctx context.Context, userID int32) (models.User, error)
+	UpdatePassword(ctx context.Context, userID int32, hashPassword string) error
+	DeleteUser(ctx context.Context, userID int32) error
+
+	GetIdentity(ctx context.Context, provider, providerUserID string) (models.Identity, error)
+	CreateIdentity(ctx context.Context, identity models.Identity) (int64, error)
+	ListIdentitiesByUser(ctx context.Context, userID int32) ([]models.Identity, error)
+	MoveIdentities(ctx context.Context, fromUserID, toUserID int32) (int64, error)
+	UpdateIdentityProfile(ctx context.Context, identity models.Identity) error
+
+	CreateLinkCode(ctx context.Context, code models.LinkCode) error
+	GetLinkCode(ctx context.Context, code string) (models.LinkCode, error)
+	UseLinkCode(ctx context.Context, code string) (userID int32, err error)
+
+	CreateSession(ctx context.Context, session models.Session) error
+	GetSessionState(ctx context.Context, sessionID string) (models.SessionState, error)
+	RevokeUserSessions(ctx context.Context, userID int32) (int64, error)
+	RevokeSession(ctx context.Context, sessionID string) (bool, error)
+	GetSessionByRefreshHash(ctx context.Context, hash []byte) (models.RefreshSessionState, error)
+	RotateSessionRefresh(ctx context.Context, sessionID string, oldHash, newHash []byte) (bool, error)
+	MoveSessions(ctx context.Context, fromUserID, toUserID int32) (int64, error)
+
+	RecordLogin(ctx context.Context, record models.LoginRecord) error
+	GetLoginSeen(ctx context.Context, userID int32, deviceFingerprint, ip string) (models.LoginSeen, error)
+	ListLoginHistory(ctx context.Context, userID int32, beforeID int64, limit int32) ([]models.LoginRecord, error)
+	MoveLoginHistory(ctx context.Context, fromUserID, toUserID int32) (int64, error)
+
+	ClaimBotMessages(ctx context.Context, limit int32) ([]models.BotMessage, error)
+	UseBotNonce(ctx context.Context, botID, nonce string, expiresAt time.Time) error
+
+	AddOutboxEvent(ctx context.Context, eventType string, event models.UserEvent) error
+}
+
+// TxRunner выполняет fn в транзакции хранилища: repo внутри fn работает в ней.
+// fn может быть вызвана повторно (serialization failure), поэтому побочные эффекты — после.
+type TxRunner func(ctx context.Context, fn func(repo AuthRepo) error) error
+
+// AuditSink — журнал событий безопасности.
+type AuditSink interface {
+	Record(ctx context.Context, event models.AuditEvent)
 }
 
 type AuthUsecase struct {
-	hasher  Hasher
-	tokener Tokener
-	repo    AuthRepo
+	hasher   Hasher
+	tokener  Tokener
+	emails   EmailNormalizer
+	policy   PasswordPolicy
+	repo     AuthRepo
+	withTx   TxRunner
+	audit    AuditSink
+	notifier LoginNotifier
+
+	refreshTTL time.Duration
+	botSecrets map[string][]byte
+	botMaxSkew time.Duration
+	now        func() time.Time
 }
 
 type AuthUsecaseDeps struct {
-	Hasher  Hasher
-	Tokener Tokener
-	Repo    AuthRepo
+	Hasher   Hasher
+	Tokener  Tokener
+	Emails   EmailNormalizer
+	Policy   PasswordPolicy
+	Repo     AuthRepo
+	WithTx   TxRunner
+	Audit    AuditSink
+	Notifier LoginNotifier
+
+	RefreshTTL time.Duration // срок web-сессии (refresh token в cookie)
+	// BotSecrets — HMAC-секреты ботов по x-bot-id; пусто — подписанные запросы не принимаются
+	BotSecrets map[string][]byte
+	BotMaxSkew time.Duration // допустимое расхождение x-ts с часами сервера
 }
 
+const (
+	defaultRefreshTTL = 30 * 24 * time.Hour
+	defaultBotMaxSkew = 5 * time.Minute
+)
+
 func New(deps AuthUsecaseDeps) *AuthUsecase {
+	if deps.RefreshTTL <= 0 {
+		deps.RefreshTTL = defaultRefreshTTL
+	}
+	if deps.BotMaxSkew <= 0 {
+		deps.BotMaxSkew = defaultBotMaxSkew
+	}
 	return &AuthUsecase{
-		hasher:  deps.Hasher,
-		tokener: deps.Tokener,
-		repo:    deps.Repo,
+		hasher:   deps.Hasher,
+		tokener:  deps.Tokener,
+		emails:   deps.Emails,
+		policy:   deps.Policy,
+		repo:     deps.Repo,
+		withTx:   deps.WithTx,
+		audit:    deps.Audit,
+		notifier: deps.Notifier,
+
+		refreshTTL: deps.RefreshTTL,
+		botSecrets: deps.BotSecrets,
+		botMaxSkew: deps.BotMaxSkew,
+		now:        time.Now,
 	}
 }
 
 func (a *AuthUsecase) Register(ctx context.Context, email, password string) (models.AuthTokens, error) {
-	hash, err := a.hasher.Hash(password)
+	ctx, span := startSpan(ctx, "Register")
+	defer span.End()
+
+	normalized, err := a.emails.Normalize(email)
+	if err != nil {
+		return models.AuthTokens{}, modelerrors.ErrEmailInvalid
+	}
+	if err := a.policy.Check(password, email); err != nil {
+		return models.AuthTokens{}, err
+	}
+
+	hash, err := a.hashPassword(ctx, password)
 	if err != nil {
 		return models.AuthTokens{}, err
 	}
 
 	user := models.User{
-		Email:        email,
-		HashPassword: hash,
+		Email:           email,
+		EmailNormalized: normalized,
+		HashPassword:    hash,
 	}
 
-	userID, err := a.repo.CreateUser(ctx, user)
+	var userID int32
+	err = a.withTx(ctx, func(repo AuthRepo) error {
+		var err error
+		userID, err = repo.CreateUser(ctx, user)
+		if err != nil {
+			return err
+		}
+		return repo.AddOutboxEvent(ctx, models.EventUserRegistered, models.UserEvent{
+			UserID: userID,
+			Email:  email,
+		})
+	})
 	if err != nil {
 		if errors.Is(err, modelerrors.ErrEmailTaken) {
 			return models.AuthTokens{}, modelerrors.ErrEmailTaken
@@ -61,39 +178,150 @@ func (a *AuthUsecase) Register(ctx context.Context, email, password string) (mod
 		return models.AuthTokens{}, err
 	}
 
-	accessToken, expInSec, err := a.tokener.Token(userID)
+	a.audit.Record(ctx, models.AuditEvent{
+		Type:         models.AuditUserRegistered,
+		ActorUserID:  userID,
+		TargetUserID: userID,
+		Details:      map[string]string{"email": email},
+	})
+
+	return a.issueTokens(ctx, userID)
+}
+
+func (a *AuthUsecase) Login(ctx context.Context, email, password string) (models.AuthTokens, error) {
+	ctx, span := startSpan(ctx, "Login")
+	defer span.End()
+
+	u, err := a.authenticate(ctx, email, password)
 	if err != nil {
 		return models.AuthTokens{}, err
 	}
+	return a.issueTokens(ctx, u.ID)
+}
+
+// authenticate проверяет email и пароль; успешный вход пишет в аудит и историю входов.
+func (a *AuthUsecase) authenticate(ctx context.Context, email, password string) (models.User, error) {
+	normalized, err := a.emails.Normalize(email)
+	if err != nil {
+		a.auditLoginFailed(ctx, 0, "unknown_email", email)
+		return models.User{}, modelerrors.ErrInvalidCredentials
+	}
 
-	return models.AuthTokens{
-		AccessToken:  accessToken,
-		ExpiresInSec: expInSec,
-	}, nil
+	u, err := a.repo.GetByEmail(ctx, normalized)
+	if err != nil {
+		if errors.Is(err, modelerrors.ErrNoRows) {
+			a.auditLoginFailed(ctx, 0, "unknown_email", email)
+			return models.User{}, modelerrors.ErrInvalidCredentials
+		}
+		return models.User{}, err
+	}
+	ok, err := a.compareHash(ctx, password, u.HashPassword)
+	if err != nil {
+		return models.User{}, err
+	}
+	if !ok {
+		a.auditLoginFailed(ctx, u.ID, "bad_password", email)
+		return models.User{}, modelerrors.ErrInvalidCredentials
+	}
+	// блокировку проверяем после пароля, чтобы не раскрывать статус аккаунта
+	if u.Blocked() {
+		a.auditLoginFailed(ctx, u.ID, "blocked", email)
+		return models.User{}, modelerrors.ErrUserBlocked
+	}
+
+	a.audit.Record(ctx, models.AuditEvent{
+		Type:         models.AuditLoginSucceeded,
+		ActorUserID:  u.ID,
+		TargetUserID: u.ID,
+		Details:      map[string]string{"method": models.LoginMethodPassword},
+	})
+	a.recordLogin(ctx, u, models.LoginMethodPassword)
+	loginSucceeded(models.LoginMethodPassword)
+
+	return u, nil
 }
 
-func (a *AuthUsecase) Login(ctx context.Context, email, password string) (models.AuthTokens, error) {
-	u, err := a.repo.GetByEmail(ctx, email)
+// ChangePassword меняет пароль, отзывает все сессии и выдаёт новый токен.
+func (a *AuthUsecase) ChangePassword(ctx context.Context, userID string, oldPassword, newPassword string) (models.AuthTokens, error) {
+	ctx, span := startSpan(ctx, "ChangePassword")
+	defer span.End()
+
+	uid, err := parseUserID(userID)
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+
+	u, err := a.repo.GetUserByID(ctx, uid)
 	if err != nil {
 		if errors.Is(err, modelerrors.ErrNoRows) {
-			return models.AuthTokens{}, modelerrors.ErrInvalidCredentials
+			return models.AuthTokens{}, modelerrors.ErrUnauthorized
 		}
 		return models.AuthTokens{}, err
 	}
-	ok, err := a.hasher.CompareHash(password, u.HashPassword)
+	ok, err := a.compareHash(ctx, oldPassword, u.HashPassword)
 	if err != nil {
 		return models.AuthTokens{}, err
 	}
 	if !ok {
 		return models.AuthTokens{}, modelerrors.ErrInvalidCredentials
 	}
+	if err := a.policy.Check(newPassword, u.Email); err != nil {
+		return models.AuthTokens{}, err
+	}
 
-	accessToken, expInSec, err := a.tokener.Token(u.ID)
+	hash, err := a.hashPassword(ctx, newPassword)
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+	// смена пароля и отзыв сессий атомарны: старые токены не переживут новый пароль
+	var revoked int64
+	err = a.withTx(ctx, func(repo AuthRepo) error {
+		if err := repo.UpdatePassword(ctx, uid, hash); err != nil {
+			return err
+		}
+		if err := repo.AddOutboxEvent(ctx, models.EventPasswordChanged, models.UserEvent{UserID: uid}); err != nil {
+			return err
+		}
+		var err error
+		revoked, err = repo.RevokeUserSessions(ctx, uid)
+		return err
+	})
 	if err != nil {
 		return models.AuthTokens{}, err
 	}
-	return models.AuthTokens{
-		AccessToken:  accessToken,
-		ExpiresInSec: expInSec,
-	}, nil
+	a.audit.Record(ctx, models.AuditEvent{
+		Type:         models.AuditPasswordChanged,
+		TargetUserID: uid,
+	})
+	a.audit.Record(ctx, models.AuditEvent{
+		Type:         models.AuditSessionsRevoked,
+		TargetUserID: uid,
+		Details: map[string]string{
+			"reason":  "password_changed",
+			"revoked": strconv.FormatInt(revoked, 10),
+		},
+	})
+
+	return a.issueTokens(ctx, uid)
+}
+
+func (a *AuthUsecase) auditLoginFailed(ctx context.Context, userID int32, reason, email string) {
+	loginFailed(models.LoginMethodPassword, reason)
+	a.audit.Record(ctx, models.AuditEvent{
+		Type:         models.AuditLoginFailed,
+		TargetUserID: userID,
+		Details: map[string]string{
+			"method": models.LoginMethodPassword,
+			"reason": reason,
+			"email":  email,
+		},
+	})
+}
+
+func parseUserID(userID string) (int32, error) {
+	uid, err := strconv.ParseInt(userID, 10, 32)
+	if err != nil || uid <= 0 {
+		return 0, modelerrors.ErrUnauthorized
+	}
+	return int32(uid), nil
 }
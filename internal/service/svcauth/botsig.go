@@ -0,0 +1,64 @@
+package svcauth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+const (
+	botSignatureVersion = "v1="
+	maxBotNonceLen      = 128
+)
+
+// SignBotRequest — подпись запроса бота к BOT-методу:
+//
+//	"v1=" + hex(HMAC-SHA256(secret, "<bot_id>.<ts>.<nonce>.<full_method>." + body))
+//
+// ts — unix-секунды (x-ts), full_method — /bottrade.auth.v1.AuthService/LinkTelegram,
+// body — детерминированный proto.Marshal запроса. Подписан и метод, поэтому подпись
+// одного вызова не подходит к другому с тем же телом.
+func SignBotRequest(secret []byte, botID string, ts int64, nonce, fullMethod string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	for _, part := range []string{botID, strconv.FormatInt(ts, 10), nonce, fullMethod} {
+		mac.Write([]byte(part))
+		mac.Write([]byte("."))
+	}
+	mac.Write(body)
+	return botSignatureVersion + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidateBotSignature проверяет подпись бота, окно x-ts и одноразовость nonce.
+// Неизвестный бот, старый timestamp и неверная подпись неотличимы для клиента (ErrBadBotSignature),
+// повтор nonce — ErrReplay.
+func (a *AuthUsecase) ValidateBotSignature(ctx context.Context, meta models.BotMeta, fullMethod string, reqBytes []byte) error {
+	ctx, span := startSpan(ctx, "ValidateBotSignature")
+	defer span.End()
+
+	secret, ok := a.botSecrets[meta.BotID]
+	if !ok {
+		return modelerrors.ErrBadBotSignature
+	}
+	if meta.Nonce == "" || len(meta.Nonce) > maxBotNonceLen {
+		return modelerrors.ErrBadBotSignature
+	}
+	ts := time.Unix(meta.Timestamp, 0)
+	if d := a.now().Sub(ts); d > a.botMaxSkew || d < -a.botMaxSkew {
+		return modelerrors.ErrBadBotSignature
+	}
+
+	expected := SignBotRequest(secret, meta.BotID, meta.Timestamp, meta.Nonce, fullMethod, reqBytes)
+	if !hmac.Equal([]byte(expected), []byte(meta.Signature)) {
+		return modelerrors.ErrBadBotSignature
+	}
+
+	// nonce запоминаем только после проверки подписи: чужие запросы не засоряют таблицу.
+	// Хранить его дольше, чем ts проходит окно, незачем — старый запрос отсечёт проверка ts.
+	return a.repo.UseBotNonce(ctx, meta.BotID, meta.Nonce, ts.Add(a.botMaxSkew))
+}
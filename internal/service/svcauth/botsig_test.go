@@ -0,0 +1,130 @@
+package svcauth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/inmemory"
+)
+
+const testMethod = "/bottrade.auth.v1.AuthService/PullBotMessages"
+
+var testBotSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func newBotVerifier(now time.Time) *AuthUsecase {
+	a := New(AuthUsecaseDeps{
+		Repo:       inmemory.New(),
+		BotSecrets: map[string][]byte{"tg-bot": testBotSecret},
+	})
+	a.now = func() time.Time { return now }
+	return a
+}
+
+func signedMeta(ts time.Time, nonce, method string, body []byte) models.BotMeta {
+	return models.BotMeta{
+		BotID:     "tg-bot",
+		Timestamp: ts.Unix(),
+		Nonce:     nonce,
+		Signature: SignBotRequest(testBotSecret, "tg-bot", ts.Unix(), nonce, method, body),
+	}
+}
+
+func TestValidateBotSignature(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte("request")
+
+	tests := []struct {
+		name   string
+		meta   func() models.BotMeta
+		method string
+		want   error
+	}{
+		{
+			name:   "valid",
+			meta:   func() models.BotMeta { return signedMeta(now, "n1", testMethod, body) },
+			method: testMethod,
+		},
+		{
+			name:   "clock skew within window",
+			meta:   func() models.BotMeta { return signedMeta(now.Add(-4*time.Minute), "n2", testMethod, body) },
+			method: testMethod,
+		},
+		{
+			name: "unknown bot",
+			meta: func() models.BotMeta {
+				m := signedMeta(now, "n3", testMethod, body)
+				m.BotID = "other-bot"
+				return m
+			},
+			method: testMethod,
+			want:   modelerrors.ErrBadBotSignature,
+		},
+		{
+			name: "tampered signature",
+			meta: func() models.BotMeta {
+				m := signedMeta(now, "n4", testMethod, body)
+				m.Signature = m.Signature[:len(m.Signature)-1] + "0"
+				return m
+			},
+			method: testMethod,
+			want:   modelerrors.ErrBadBotSignature,
+		},
+		{
+			name: "signature for another method",
+			meta: func() models.BotMeta {
+				return signedMeta(now, "n5", "/bottrade.auth.v1.AuthService/LinkTelegram", body)
+			},
+			method: testMethod,
+			want:   modelerrors.ErrBadBotSignature,
+		},
+		{
+			name:   "stale timestamp",
+			meta:   func() models.BotMeta { return signedMeta(now.Add(-6*time.Minute), "n6", testMethod, body) },
+			method: testMethod,
+			want:   modelerrors.ErrBadBotSignature,
+		},
+		{
+			name:   "timestamp from the future",
+			meta:   func() models.BotMeta { return signedMeta(now.Add(6*time.Minute), "n7", testMethod, body) },
+			method: testMethod,
+			want:   modelerrors.ErrBadBotSignature,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newBotVerifier(now)
+			err := a.ValidateBotSignature(context.Background(), tt.meta(), tt.method, body)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateBotSignatureRejectsReplay(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	a := newBotVerifier(now)
+	meta := signedMeta(now, "once", testMethod, nil)
+
+	if err := a.ValidateBotSignature(context.Background(), meta, testMethod, nil); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	if err := a.ValidateBotSignature(context.Background(), meta, testMethod, nil); !errors.Is(err, modelerrors.ErrReplay) {
+		t.Fatalf("replay: err = %v, want ErrReplay", err)
+	}
+}
+
+func TestValidateBotSignatureWithoutBotsRejectsEveryone(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	a := New(AuthUsecaseDeps{Repo: inmemory.New()})
+	a.now = func() time.Time { return now }
+
+	err := a.ValidateBotSignature(context.Background(), signedMeta(now, "n", testMethod, nil), testMethod, nil)
+	if !errors.Is(err, modelerrors.ErrBadBotSignature) {
+		t.Fatalf("err = %v, want ErrBadBotSignature", err)
+	}
+}
@@ -0,0 +1,59 @@
+package svcauth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Порядок важен: Edge и Opera содержат "Chrome/", Chrome содержит "Safari/".
+var uaClients = []struct{ marker, name string }{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"YaBrowser/", "Yandex Browser"},
+	{"Firefox/", "Firefox"},
+	{"Chrome/", "Chrome"},
+	{"Safari/", "Safari"},
+	{"grpc-", "gRPC client"},
+	{"okhttp/", "Android app"},
+	{"CFNetwork/", "iOS app"},
+}
+
+// Android содержит "Linux", iPhone — "Mac OS X", поэтому они раньше.
+var uaOSes = []struct{ marker, name string }{
+	{"Windows", "Windows"},
+	{"Android", "Android"},
+	{"iPhone", "iOS"},
+	{"iPad", "iOS"},
+	{"Mac OS X", "macOS"},
+	{"Macintosh", "macOS"},
+	{"CrOS", "ChromeOS"},
+	{"Linux", "Linux"},
+}
+
+// describeDevice даёт грубое описание устройства по user agent: клиент и ОС без версий.
+// Отпечаток стабилен между обновлениями браузера, но различает, например, Chrome и Firefox.
+func describeDevice(userAgent string) (name, fingerprint string) {
+	client := "Unknown client"
+	for _, c := range uaClients {
+		if strings.Contains(userAgent, c.marker) {
+			client = c.name
+			break
+		}
+	}
+	os := "unknown OS"
+	for _, o := range uaOSes {
+		if strings.Contains(userAgent, o.marker) {
+			os = o.name
+			break
+		}
+	}
+
+	name = client + ", " + os
+	return name, deviceFingerprint(name)
+}
+
+func deviceFingerprint(name string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(name)))
+	return hex.EncodeToString(sum[:8])
+}
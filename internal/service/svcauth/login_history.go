@@ -0,0 +1,97 @@
+package svcauth
+
+import (
+	"context"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/reqmeta"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/logger"
+)
+
+// LoginNotifier — уведомления пользователю о входе с нового устройства или IP.
+type LoginNotifier interface {
+	NotifyNewLogin(ctx context.Context, notice models.LoginNotice)
+}
+
+func (a *AuthUsecase) ListLoginHistory(ctx context.Context, userID string, beforeID int64, limit int32) ([]models.LoginRecord, error) {
+	ctx, span := startSpan(ctx, "ListLoginHistory")
+	defer span.End()
+
+	uid, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return a.repo.ListLoginHistory(ctx, uid, beforeID, limit)
+}
+
+// PullBotMessages отдаёт боту накопившиеся сообщения для пользователей (at-most-once).
+func (a *AuthUsecase) PullBotMessages(ctx context.Context, limit int32) ([]models.BotMessage, error) {
+	ctx, span := startSpan(ctx, "PullBotMessages")
+	defer span.End()
+
+	return a.repo.ClaimBotMessages(ctx, limit)
+}
+
+// recordLogin пишет вход в историю и уведомляет пользователя, если устройство или IP новые.
+// Ошибки не прерывают логин — только логируются.
+func (a *AuthUsecase) recordLogin(ctx context.Context, u models.User, method string) {
+	m := reqmeta.From(ctx)
+	record := models.LoginRecord{
+		UserID:    u.ID,
+		Method:    method,
+		IP:        m.IP,
+		UserAgent: m.UserAgent,
+	}
+	if method == models.LoginMethodTelegram {
+		// вход идёт через бота: IP и user agent принадлежат боту, а не пользователю
+		record.IP, record.UserAgent = "", ""
+		record.DeviceName = "Telegram"
+		record.DeviceFingerprint = deviceFingerprint(record.DeviceName)
+	} else {
+		record.DeviceName, record.DeviceFingerprint = describeDevice(m.UserAgent)
+	}
+
+	seen, err := a.repo.GetLoginSeen(ctx, u.ID, record.DeviceFingerprint, record.IP)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("svcauth.recordLogin: get login seen for user %d: %s", u.ID, err)
+		return
+	}
+	if err := a.repo.RecordLogin(ctx, record); err != nil {
+		logger.FromContext(ctx).Errorf("svcauth.recordLogin: record login for user %d: %s", u.ID, err)
+		return
+	}
+
+	// самый первый вход не с чем сравнивать — не шумим
+	if !seen.HasHistory {
+		return
+	}
+	newDevice := !seen.DeviceSeen
+	newIP := record.IP != "" && !seen.IPSeen
+	if !newDevice && !newIP {
+		return
+	}
+
+	identities, err := a.repo.ListIdentitiesByUser(ctx, u.ID)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("svcauth.recordLogin: list identities for user %d: %s", u.ID, err)
+	}
+	var chatIDs []int64
+	for _, identity := range identities {
+		if identity.Provider == models.ProviderTelegram && identity.ChatID != 0 {
+			chatIDs = append(chatIDs, identity.ChatID)
+		}
+	}
+
+	a.notifier.NotifyNewLogin(ctx, models.LoginNotice{
+		UserID:     u.ID,
+		Email:      u.Email,
+		ChatIDs:    chatIDs,
+		Method:     method,
+		IP:         record.IP,
+		DeviceName: record.DeviceName,
+		NewDevice:  newDevice,
+		NewIP:      newIP,
+		At:         time.Now(),
+	})
+}
@@ -0,0 +1,137 @@
+package svcauth
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+// MergeAccounts сливает второй аккаунт пользователя в текущий: identities, сессии и история
+// входов переезжают, второй аккаунт удаляется. Аккаунты создаются только через Register
+// (TelegramAuth пользователей не заводит), так что второй — это повторная регистрация
+// на другой email, возможно со своим привязанным Telegram.
+// Email, пароль и роль остаются от текущего. Ссылки на удалённый аккаунт в других сервисах
+// платформы перепривязываются по событию user.merged.
+func (a *AuthUsecase) MergeAccounts(ctx context.Context, userID string, proof models.MergeProof) (models.MergeResult, error) {
+	ctx, span := startSpan(ctx, "MergeAccounts")
+	defer span.End()
+
+	primaryID, err := parseUserID(userID)
+	if err != nil {
+		return models.MergeResult{}, err
+	}
+
+	method := "access_token"
+	var secondaryID int32
+	code := strings.ToUpper(proof.LinkCode)
+	if proof.AccessToken != "" {
+		sid, err := a.ValidateAccessToken(ctx, proof.AccessToken)
+		if err != nil {
+			return models.MergeResult{}, err
+		}
+		if secondaryID, err = parseUserID(sid); err != nil {
+			return models.MergeResult{}, err
+		}
+	} else {
+		method = "link_code"
+	}
+
+	var (
+		result    models.MergeResult
+		primary   models.User
+		secondary models.User
+		revoked   int64
+	)
+	err = a.withTx(ctx, func(repo AuthRepo) error {
+		var err error
+		sid := secondaryID
+		if proof.AccessToken == "" {
+			// ErrNoRows отсюда — единственный, который не переведён ниже: разбираем как ошибку кода
+			if sid, err = repo.UseLinkCode(ctx, code); err != nil {
+				return err
+			}
+		}
+		if sid == primaryID {
+			return modelerrors.ErrMergeNotAllowed
+		}
+
+		if primary, err = repo.GetUserByID(ctx, primaryID); err != nil {
+			return userErr(err, modelerrors.ErrUnauthorized)
+		}
+		if secondary, err = repo.GetUserByID(ctx, sid); err != nil {
+			return userErr(err, modelerrors.ErrUserNotFound)
+		}
+		// слияние не должно обходить блокировку или поднимать права
+		if primary.Blocked() || secondary.Blocked() {
+			return modelerrors.ErrUserBlocked
+		}
+		if secondary.Role == models.RoleAdmin && primary.Role != models.RoleAdmin {
+			return modelerrors.ErrMergeNotAllowed
+		}
+
+		result.MergedUserID = sid
+		// токены второго аккаунта несут его id и после переноса сессий работать не должны
+		if revoked, err = repo.RevokeUserSessions(ctx, sid); err != nil {
+			return err
+		}
+		if result.Identities, err = repo.MoveIdentities(ctx, sid, primaryID); err != nil {
+			return err
+		}
+		if result.Sessions, err = repo.MoveSessions(ctx, sid, primaryID); err != nil {
+			return err
+		}
+		if result.Logins, err = repo.MoveLoginHistory(ctx, sid, primaryID); err != nil {
+			return err
+		}
+		if err := repo.DeleteUser(ctx, sid); err != nil {
+			return userErr(err, modelerrors.ErrUserNotFound)
+		}
+		return repo.AddOutboxEvent(ctx, models.EventUserMerged, models.UserEvent{
+			UserID:       primaryID,
+			Email:        primary.Email,
+			MergedUserID: sid,
+		})
+	})
+	if err != nil {
+		if errors.Is(err, modelerrors.ErrNoRows) {
+			return models.MergeResult{}, a.linkCodeError(ctx, code)
+		}
+		return models.MergeResult{}, err
+	}
+
+	a.audit.Record(ctx, models.AuditEvent{
+		Type:         models.AuditAccountsMerged,
+		ActorUserID:  primaryID,
+		TargetUserID: primaryID,
+		Details: map[string]string{
+			"method":         method,
+			"merged_user_id": strconv.FormatInt(int64(result.MergedUserID), 10),
+			"merged_email":   secondary.Email,
+			"identities":     strconv.FormatInt(result.Identities, 10),
+			"sessions":       strconv.FormatInt(result.Sessions, 10),
+			"logins":         strconv.FormatInt(result.Logins, 10),
+		},
+	})
+	a.audit.Record(ctx, models.AuditEvent{
+		Type:         models.AuditSessionsRevoked,
+		ActorUserID:  primaryID,
+		TargetUserID: result.MergedUserID,
+		Details: map[string]string{
+			"reason":  "merged",
+			"revoked": strconv.FormatInt(revoked, 10),
+		},
+	})
+
+	return result, nil
+}
+
+func userErr(err, notFound error) error {
+	if errors.Is(err, modelerrors.ErrNoRows) {
+		return notFound
+	}
+	return err
+}
@@ -0,0 +1,192 @@
+package svcauth
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+func TestMergeAccountsByProof(t *testing.T) {
+	tests := []struct {
+		name   string
+		proof  func(u testUsecase, secondaryID, secondaryToken string) models.MergeProof
+		method string
+	}{
+		{
+			name: "secondary access token",
+			proof: func(_ testUsecase, _, secondaryToken string) models.MergeProof {
+				return models.MergeProof{AccessToken: secondaryToken}
+			},
+			method: "access_token",
+		},
+		{
+			name: "link code issued by secondary",
+			proof: func(u testUsecase, secondaryID, _ string) models.MergeProof {
+				code, _, err := u.CreateTelegramLinkCode(context.Background(), secondaryID, 10*time.Minute)
+				if err != nil {
+					t.Fatalf("CreateTelegramLinkCode: %v", err)
+				}
+				return models.MergeProof{LinkCode: code}
+			},
+			method: "link_code",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			u := newTestUsecase()
+			primaryID, _ := u.register(t, "primary@example.com")
+			secondaryID, secondaryToken := u.register(t, "secondary@example.com")
+			u.linkTelegram(t, secondaryID, models.TelegramProfile{TelegramUserID: 7, ChatID: 7})
+
+			res, err := u.MergeAccounts(ctx, primaryID, tt.proof(u, secondaryID, secondaryToken))
+			if err != nil {
+				t.Fatalf("MergeAccounts: %v", err)
+			}
+			if strconv.Itoa(int(res.MergedUserID)) != secondaryID || res.Identities != 1 {
+				t.Fatalf("result = %+v, want merged %s with 1 identity", res, secondaryID)
+			}
+
+			// Telegram второго аккаунта теперь входит в основной
+			toks, err := u.TelegramAuth(ctx, models.TelegramProfile{TelegramUserID: 7, ChatID: 7})
+			if err != nil {
+				t.Fatalf("TelegramAuth after merge: %v", err)
+			}
+			if got, _ := u.ValidateAccessToken(ctx, toks.AccessToken); got != primaryID {
+				t.Fatalf("telegram login user = %s, want %s", got, primaryID)
+			}
+			if _, err := u.repo.GetUserByID(ctx, res.MergedUserID); !errors.Is(err, modelerrors.ErrNoRows) {
+				t.Fatalf("secondary still exists: err = %v", err)
+			}
+			if got := mergeMethod(u.audit); got != tt.method {
+				t.Fatalf("audit method = %q, want %q", got, tt.method)
+			}
+		})
+	}
+}
+
+func TestMergeAccountsRevokesSecondarySessions(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUsecase()
+	primaryID, _ := u.register(t, "primary@example.com")
+	_, secondaryToken := u.register(t, "secondary@example.com")
+
+	if _, err := u.MergeAccounts(ctx, primaryID, models.MergeProof{AccessToken: secondaryToken}); err != nil {
+		t.Fatalf("MergeAccounts: %v", err)
+	}
+	if _, err := u.ValidateAccessToken(ctx, secondaryToken); err == nil {
+		t.Fatal("secondary access token still valid after merge")
+	}
+	// сессия переехала на основной аккаунт, но отозвана
+	state, err := u.repo.GetSessionState(ctx, sessionOf(t, secondaryToken))
+	if err != nil || !state.Revoked || strconv.Itoa(int(state.UserID)) != primaryID {
+		t.Fatalf("secondary session after merge: %+v, %v", state, err)
+	}
+	if _, err := u.MergeAccounts(ctx, primaryID, models.MergeProof{AccessToken: secondaryToken}); err == nil {
+		t.Fatal("merge with revoked token succeeded")
+	}
+}
+
+func TestMergeAccountsRejected(t *testing.T) {
+	tests := []struct {
+		name           string
+		secondaryRole  string
+		blockPrimary   bool
+		blockSecondary bool
+		want           error
+	}{
+		{name: "blocked secondary", blockSecondary: true, want: modelerrors.ErrUserBlocked},
+		{name: "blocked primary", blockPrimary: true, want: modelerrors.ErrUserBlocked},
+		{name: "admin secondary into user primary", secondaryRole: models.RoleAdmin, want: modelerrors.ErrMergeNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			u := newTestUsecase()
+			primaryID, _ := u.register(t, "primary@example.com")
+			pid, _ := parseUserID(primaryID)
+			sid, secondaryToken := u.createUser(t, "secondary@example.com", tt.secondaryRole)
+			if tt.blockPrimary {
+				if err := u.repo.BlockUser(ctx, pid); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.blockSecondary {
+				if err := u.repo.BlockUser(ctx, sid); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err := u.MergeAccounts(ctx, primaryID, models.MergeProof{AccessToken: secondaryToken})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("MergeAccounts err = %v, want %v", err, tt.want)
+			}
+
+			// отказ не должен ничего менять
+			if _, err := u.repo.GetUserByID(ctx, sid); err != nil {
+				t.Fatalf("secondary removed on rejected merge: %v", err)
+			}
+			primary, err := u.repo.GetUserByID(ctx, pid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if primary.Role != models.RoleUser {
+				t.Fatalf("primary role = %q after rejected merge", primary.Role)
+			}
+			state, err := u.repo.GetSessionState(ctx, sessionOf(t, secondaryToken))
+			if err != nil || state.Revoked || state.UserID != sid {
+				t.Fatalf("secondary session changed on rejected merge: %+v, %v", state, err)
+			}
+		})
+	}
+}
+
+func TestMergeAccountsSelf(t *testing.T) {
+	u := newTestUsecase()
+	primaryID, token := u.register(t, "primary@example.com")
+
+	_, err := u.MergeAccounts(context.Background(), primaryID, models.MergeProof{AccessToken: token})
+	if !errors.Is(err, modelerrors.ErrMergeNotAllowed) {
+		t.Fatalf("MergeAccounts err = %v, want ErrMergeNotAllowed", err)
+	}
+}
+
+// createUser заводит пользователя в обход Register, чтобы задать роль.
+func (u testUsecase) createUser(t *testing.T, email, role string) (userID int32, accessToken string) {
+	t.Helper()
+	ctx := context.Background()
+	userID, err := u.repo.CreateUser(ctx, models.User{Email: email, EmailNormalized: email, Role: role})
+	if err != nil {
+		t.Fatal(err)
+	}
+	toks, err := u.issueTokens(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return userID, toks.AccessToken
+}
+
+func sessionOf(t *testing.T, accessToken string) string {
+	t.Helper()
+	_, sid, err := plainTokener{}.Parse(accessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sid
+}
+
+func mergeMethod(l *auditLog) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, e := range l.events {
+		if e.Type == models.AuditAccountsMerged {
+			return e.Details["method"]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,21 @@
+package svcauth
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// loginAttempts — входы по способу (password, telegram) и результату; reason — причина отказа
+// в тех же терминах, что и в аудите (unknown_email, bad_password, blocked, not_linked).
+var loginAttempts = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "bottrade_auth_login_attempts_total",
+	Help: "Login attempts by method and result (success, failure) with failure reason.",
+}, []string{"method", "result", "reason"})
+
+func loginSucceeded(method string) {
+	loginAttempts.WithLabelValues(method, "success", "").Inc()
+}
+
+func loginFailed(method, reason string) {
+	loginAttempts.WithLabelValues(method, "failure", reason).Inc()
+}
@@ -0,0 +1,46 @@
+package svcauth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+// issueTokens заводит сессию и выпускает на неё access token (jti = id сессии).
+func (a *AuthUsecase) issueTokens(ctx context.Context, userID int32) (models.AuthTokens, error) {
+	sessionID, err := newSessionID()
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+
+	accessToken, expInSec, err := a.signToken(ctx, userID, sessionID)
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+
+	session := models.Session{
+		ID:        sessionID,
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(time.Duration(expInSec) * time.Second),
+	}
+	if err := a.repo.CreateSession(ctx, session); err != nil {
+		return models.AuthTokens{}, err
+	}
+
+	return models.AuthTokens{
+		AccessToken:  accessToken,
+		ExpiresInSec: expInSec,
+	}, nil
+}
+
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("read session id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
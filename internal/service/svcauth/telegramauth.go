@@ -2,19 +2,197 @@ package svcauth
 
 import (
 	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
 	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
 )
 
+// linkCodeAlphabet — без похожих символов (0/O, 1/I/L), код вводят руками в боте.
+const (
+	linkCodeAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+	linkCodeLen      = 8
+)
+
 func (a *AuthUsecase) CreateTelegramLinkCode(ctx context.Context, userID string, ttl time.Duration) (code string, expiresInSec int64, err error) {
-	return "", 0, nil
+	ctx, span := startSpan(ctx, "CreateTelegramLinkCode")
+	defer span.End()
+
+	uid, err := parseUserID(userID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	code, err = newLinkCode()
+	if err != nil {
+		return "", 0, err
+	}
+
+	linkCode := models.LinkCode{
+		Code:      code,
+		UserID:    uid,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := a.repo.CreateLinkCode(ctx, linkCode); err != nil {
+		return "", 0, err
+	}
+
+	a.audit.Record(ctx, models.AuditEvent{
+		Type:         models.AuditLinkCodeCreated,
+		TargetUserID: uid,
+		Details:      map[string]string{"ttl_sec": strconv.FormatInt(int64(ttl.Seconds()), 10)},
+	})
+
+	return code, int64(ttl.Seconds()), nil
 }
 
 func (a *AuthUsecase) LinkTelegram(ctx context.Context, code string, tg models.TelegramProfile) error {
+	ctx, span := startSpan(ctx, "LinkTelegram")
+	defer span.End()
+
+	code = strings.ToUpper(code)
+
+	identity := models.Identity{
+		Provider:       models.ProviderTelegram,
+		ProviderUserID: strconv.FormatInt(tg.TelegramUserID, 10),
+		Username:       tg.Username,
+		FirstName:      tg.FirstName,
+		LastName:       tg.LastName,
+		ChatID:         tg.ChatID,
+	}
+
+	// код гасится только вместе с созданием identity: при ErrTelegramAlreadyLinked он остаётся рабочим
+	var userID int32
+	err := a.withTx(ctx, func(repo AuthRepo) error {
+		var err error
+		userID, err = repo.UseLinkCode(ctx, code)
+		if err != nil {
+			return err
+		}
+		identity.UserID = userID
+		if _, err := repo.CreateIdentity(ctx, identity); err != nil {
+			return err
+		}
+		return repo.AddOutboxEvent(ctx, models.EventTelegramLinked, models.UserEvent{
+			UserID:         userID,
+			TelegramUserID: identity.ProviderUserID,
+		})
+	})
+	if err != nil {
+		if errors.Is(err, modelerrors.ErrNoRows) {
+			return a.linkCodeError(ctx, code)
+		}
+		return err
+	}
+
+	a.audit.Record(ctx, models.AuditEvent{
+		Type:         models.AuditTelegramLinked,
+		TargetUserID: userID,
+		Details: map[string]string{
+			"telegram_user_id": identity.ProviderUserID,
+			"username":         tg.Username,
+		},
+	})
 	return nil
 }
 
 func (a *AuthUsecase) TelegramAuth(ctx context.Context, tg models.TelegramProfile) (models.AuthTokens, error) {
-	return models.AuthTokens{}, nil
+	ctx, span := startSpan(ctx, "TelegramAuth")
+	defer span.End()
+
+	identity, err := a.repo.GetIdentity(ctx, models.ProviderTelegram, strconv.FormatInt(tg.TelegramUserID, 10))
+	if err != nil {
+		if errors.Is(err, modelerrors.ErrNoRows) {
+			a.auditTelegramLoginFailed(ctx, 0, "not_linked", tg)
+			return models.AuthTokens{}, modelerrors.ErrTelegramNotLinked
+		}
+		return models.AuthTokens{}, err
+	}
+
+	u, err := a.repo.GetUserByID(ctx, identity.UserID)
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+	if u.Blocked() {
+		a.auditTelegramLoginFailed(ctx, u.ID, "blocked", tg)
+		return models.AuthTokens{}, modelerrors.ErrUserBlocked
+	}
+
+	// chat_id и username в Telegram меняются; бот шлёт сообщения по сохранённому chat_id
+	if updated, changed := identity.WithTelegramProfile(tg); changed {
+		if err := a.repo.UpdateIdentityProfile(ctx, updated); err != nil {
+			return models.AuthTokens{}, err
+		}
+	}
+
+	a.audit.Record(ctx, models.AuditEvent{
+		Type:         models.AuditLoginSucceeded,
+		ActorUserID:  u.ID,
+		TargetUserID: u.ID,
+		Details: map[string]string{
+			"method":           models.LoginMethodTelegram,
+			"telegram_user_id": identity.ProviderUserID,
+		},
+	})
+	a.recordLogin(ctx, u, models.LoginMethodTelegram)
+	loginSucceeded(models.LoginMethodTelegram)
+
+	return a.issueTokens(ctx, u.ID)
+}
+
+// linkCodeError объясняет, почему код не удалось использовать.
+func (a *AuthUsecase) linkCodeError(ctx context.Context, code string) error {
+	lc, err := a.repo.GetLinkCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, modelerrors.ErrNoRows) {
+			return modelerrors.ErrLinkCodeInvalid
+		}
+		return err
+	}
+	switch {
+	case !lc.UsedAt.IsZero():
+		return modelerrors.ErrLinkCodeUsed
+	case !lc.ExpiresAt.After(time.Now()):
+		return modelerrors.ErrLinkCodeExpired
+	default:
+		return modelerrors.ErrLinkCodeInvalid
+	}
+}
+
+func (a *AuthUsecase) auditTelegramLoginFailed(ctx context.Context, userID int32, reason string, tg models.TelegramProfile) {
+	loginFailed(models.LoginMethodTelegram, reason)
+	a.audit.Record(ctx, models.AuditEvent{
+		Type:         models.AuditLoginFailed,
+		TargetUserID: userID,
+		Details: map[string]string{
+			"method":           models.LoginMethodTelegram,
+			"reason":           reason,
+			"telegram_user_id": strconv.FormatInt(tg.TelegramUserID, 10),
+		},
+	})
+}
+
+func newLinkCode() (string, error) {
+	// байты >= maxByte отбрасываем, чтобы не было смещения при взятии по модулю
+	const maxByte = 256 - 256%len(linkCodeAlphabet)
+
+	code := make([]byte, 0, linkCodeLen)
+	buf := make([]byte, linkCodeLen*2)
+	for len(code) < linkCodeLen {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("read link code: %w", err)
+		}
+		for _, b := range buf {
+			if int(b) >= maxByte || len(code) == linkCodeLen {
+				continue
+			}
+			code = append(code, linkCodeAlphabet[int(b)%len(linkCodeAlphabet)])
+		}
+	}
+	return string(code), nil
 }
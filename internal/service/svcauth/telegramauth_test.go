@@ -0,0 +1,47 @@
+package svcauth
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+func TestTelegramAuthNotLinked(t *testing.T) {
+	u := newTestUsecase()
+
+	_, err := u.TelegramAuth(context.Background(), models.TelegramProfile{TelegramUserID: 42, ChatID: 42})
+	if !errors.Is(err, modelerrors.ErrTelegramNotLinked) {
+		t.Fatalf("TelegramAuth err = %v, want ErrTelegramNotLinked", err)
+	}
+	if _, err := u.repo.GetIdentity(context.Background(), models.ProviderTelegram, "42"); !errors.Is(err, modelerrors.ErrNoRows) {
+		t.Fatalf("identity created on failed login: err = %v", err)
+	}
+}
+
+func TestTelegramAuthRefreshesIdentity(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUsecase()
+	userID, _ := u.register(t, "tg@example.com")
+	u.linkTelegram(t, userID, models.TelegramProfile{TelegramUserID: 42, ChatID: 100, Username: "old"})
+
+	fresh := models.TelegramProfile{TelegramUserID: 42, ChatID: 200, Username: "new", FirstName: "Ivan"}
+	toks, err := u.TelegramAuth(ctx, fresh)
+	if err != nil {
+		t.Fatalf("TelegramAuth: %v", err)
+	}
+	if got, err := u.ValidateAccessToken(ctx, toks.AccessToken); err != nil || got != userID {
+		t.Fatalf("token user = %q, %v; want %q", got, err, userID)
+	}
+
+	identity, err := u.repo.GetIdentity(ctx, models.ProviderTelegram, strconv.FormatInt(fresh.TelegramUserID, 10))
+	if err != nil {
+		t.Fatalf("GetIdentity: %v", err)
+	}
+	if identity.ChatID != 200 || identity.Username != "new" || identity.FirstName != "Ivan" {
+		t.Fatalf("identity not refreshed: %+v", identity)
+	}
+}
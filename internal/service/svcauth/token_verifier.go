@@ -2,14 +2,58 @@ package svcauth
 
 import (
 	"context"
+	"errors"
+	"strconv"
 
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
 	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
 )
 
 func (a *AuthUsecase) ValidateAccessToken(ctx context.Context, accessToken string) (userID string, err error) {
-	return "", nil
+	ctx, span := startSpan(ctx, "ValidateAccessToken")
+	defer span.End()
+
+	uid, sessionID, err := a.tokener.Parse(accessToken)
+	if err != nil {
+		return "", modelerrors.ErrUnauthorized
+	}
+
+	st, err := a.repo.GetSessionState(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, modelerrors.ErrNoRows) {
+			return "", modelerrors.ErrUnauthorized
+		}
+		return "", err
+	}
+	if st.UserID != uid || st.Revoked {
+		return "", modelerrors.ErrUnauthorized
+	}
+	if st.UserBlocked {
+		return "", modelerrors.ErrUserBlocked
+	}
+
+	return strconv.FormatInt(int64(uid), 10), nil
 }
 
-func (a *AuthUsecase) ValidateBotSignature(ctx context.Context, meta models.BotMeta, fullMethod string, reqBytes []byte) error {
+// RequireAdmin проверяет, что пользователь из токена — администратор.
+func (a *AuthUsecase) RequireAdmin(ctx context.Context, userID string) error {
+	ctx, span := startSpan(ctx, "RequireAdmin")
+	defer span.End()
+
+	uid, err := parseUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	u, err := a.repo.GetUserByID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, modelerrors.ErrNoRows) {
+			return modelerrors.ErrUnauthorized
+		}
+		return err
+	}
+	if u.Role != models.RoleAdmin {
+		return modelerrors.ErrForbidden
+	}
 	return nil
 }
@@ -0,0 +1,45 @@
+package svcauth
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer — span-ы методов сервиса и тяжёлых шагов внутри них (argon2, подпись токена),
+// чтобы в trace медленного запроса было видно, куда ушло время. Запросы к Postgres
+// трассирует pgx tracer репозитория.
+var tracer = otel.Tracer("github.com/IvanOplesnin/BotTradeService.git/internal/service/svcauth")
+
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "svcauth."+name)
+}
+
+// endSpan закрывает span с ошибкой; используется через defer с именованным err.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (a *AuthUsecase) hashPassword(ctx context.Context, password string) (hash string, err error) {
+	_, span := tracer.Start(ctx, "argon2.Hash")
+	defer func() { endSpan(span, err) }()
+	return a.hasher.Hash(password)
+}
+
+func (a *AuthUsecase) compareHash(ctx context.Context, password, hash string) (match bool, err error) {
+	_, span := tracer.Start(ctx, "argon2.CompareHash")
+	defer func() { endSpan(span, err) }()
+	return a.hasher.CompareHash(password, hash)
+}
+
+func (a *AuthUsecase) signToken(ctx context.Context, userID int32, sessionID string) (token string, expInSec int64, err error) {
+	_, span := tracer.Start(ctx, "token.Sign")
+	defer func() { endSpan(span, err) }()
+	return a.tokener.Token(userID, sessionID)
+}
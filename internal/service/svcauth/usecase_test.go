@@ -0,0 +1,117 @@
+package svcauth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/repository/inmemory"
+)
+
+// тестовые зависимости: реальный inmemory репозиторий, остальное — простые фейки
+
+type plainHasher struct{}
+
+func (plainHasher) Hash(password string) (string, error) { return "plain:" + password, nil }
+
+func (plainHasher) CompareHash(password, hash string) (bool, error) {
+	return hash == "plain:"+password, nil
+}
+
+type plainTokener struct{}
+
+func (plainTokener) Token(userID int32, sessionID string) (string, int64, error) {
+	return fmt.Sprintf("%d.%s", userID, sessionID), 900, nil
+}
+
+func (plainTokener) Parse(accessToken string) (int32, string, error) {
+	uid, sid, ok := strings.Cut(accessToken, ".")
+	if !ok {
+		return 0, "", fmt.Errorf("bad token %q", accessToken)
+	}
+	id, err := strconv.ParseInt(uid, 10, 32)
+	if err != nil {
+		return 0, "", err
+	}
+	return int32(id), sid, nil
+}
+
+type lowerEmails struct{}
+
+func (lowerEmails) Normalize(email string) (string, error) { return strings.ToLower(email), nil }
+
+type anyPassword struct{}
+
+func (anyPassword) Check(string, string) error { return nil }
+
+type auditLog struct {
+	mu     sync.Mutex
+	events []models.AuditEvent
+}
+
+func (l *auditLog) Record(_ context.Context, event models.AuditEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+type nopNotifier struct{}
+
+func (nopNotifier) NotifyNewLogin(context.Context, models.LoginNotice) {}
+
+type testUsecase struct {
+	*AuthUsecase
+	repo  *inmemory.Repo
+	audit *auditLog
+}
+
+func newTestUsecase() testUsecase {
+	repo := inmemory.New()
+	audit := &auditLog{}
+	a := New(AuthUsecaseDeps{
+		Hasher:  plainHasher{},
+		Tokener: plainTokener{},
+		Emails:  lowerEmails{},
+		Policy:  anyPassword{},
+		Repo:    repo,
+		WithTx: func(ctx context.Context, fn func(repo AuthRepo) error) error {
+			return repo.WithTx(ctx, func(tx *inmemory.Repo) error { return fn(tx) })
+		},
+		Audit:    audit,
+		Notifier: nopNotifier{},
+	})
+	return testUsecase{AuthUsecase: a, repo: repo, audit: audit}
+}
+
+// register создаёт пользователя и возвращает его id и access token.
+func (u testUsecase) register(t *testing.T, email string) (userID string, accessToken string) {
+	t.Helper()
+	ctx := context.Background()
+	toks, err := u.Register(ctx, email, "correct horse battery staple")
+	if err != nil {
+		t.Fatalf("Register(%s): %v", email, err)
+	}
+	userID, err = u.ValidateAccessToken(ctx, toks.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken: %v", err)
+	}
+	return userID, toks.AccessToken
+}
+
+// linkTelegram привязывает Telegram-профиль к пользователю через link-код.
+func (u testUsecase) linkTelegram(t *testing.T, userID string, tg models.TelegramProfile) {
+	t.Helper()
+	ctx := context.Background()
+	code, _, err := u.CreateTelegramLinkCode(ctx, userID, 10*time.Minute)
+	if err != nil {
+		t.Fatalf("CreateTelegramLinkCode: %v", err)
+	}
+	if err := u.LinkTelegram(ctx, code, tg); err != nil {
+		t.Fatalf("LinkTelegram: %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package svcauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+// WebLogin — вход веб-приложения: кроме короткого access token выдаёт refresh token,
+// который HTTP edge кладёт в HttpOnly cookie. Сессия живёт refreshTTL.
+func (a *AuthUsecase) WebLogin(ctx context.Context, email, password string) (models.AuthTokens, error) {
+	ctx, span := startSpan(ctx, "WebLogin")
+	defer span.End()
+
+	u, err := a.authenticate(ctx, email, password)
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+
+	sessionID, err := newSessionID()
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+	refreshToken, refreshHash, err := newRefreshToken()
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+
+	expiresAt := time.Now().Add(a.refreshTTL)
+	session := models.Session{
+		ID:               sessionID,
+		UserID:           u.ID,
+		ExpiresAt:        expiresAt,
+		RefreshTokenHash: refreshHash,
+	}
+	if err := a.repo.CreateSession(ctx, session); err != nil {
+		return models.AuthTokens{}, err
+	}
+
+	return a.webTokens(ctx, u.ID, sessionID, refreshToken, expiresAt)
+}
+
+// RefreshWebSession ротирует refresh token и выпускает новый access token на ту же сессию.
+// Повторное предъявление уже ротированного токена — признак кражи: сессия отзывается.
+func (a *AuthUsecase) RefreshWebSession(ctx context.Context, refreshToken string) (models.AuthTokens, error) {
+	ctx, span := startSpan(ctx, "RefreshWebSession")
+	defer span.End()
+
+	oldHash := hashRefreshToken(refreshToken)
+	newToken, newHash, err := newRefreshToken()
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+
+	var (
+		st     models.RefreshSessionState
+		reused bool
+	)
+	err = a.withTx(ctx, func(repo AuthRepo) error {
+		var err error
+		reused = false
+		st, err = repo.GetSessionByRefreshHash(ctx, oldHash)
+		if err != nil {
+			if errors.Is(err, modelerrors.ErrNoRows) {
+				return modelerrors.ErrUnauthorized
+			}
+			return err
+		}
+		if !st.Current {
+			// отзыв должен закоммититься, поэтому ошибку отдаём уже после транзакции
+			if _, err := repo.RevokeSession(ctx, st.SessionID); err != nil {
+				return err
+			}
+			reused = !st.Revoked
+			return nil
+		}
+		if st.Revoked || !time.Now().Before(st.ExpiresAt) {
+			return modelerrors.ErrUnauthorized
+		}
+		if st.UserBlocked {
+			return modelerrors.ErrUserBlocked
+		}
+
+		ok, err := repo.RotateSessionRefresh(ctx, st.SessionID, oldHash, newHash)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return modelerrors.ErrUnauthorized
+		}
+		return nil
+	})
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+	if !st.Current {
+		if reused {
+			a.audit.Record(ctx, models.AuditEvent{
+				Type:         models.AuditSessionsRevoked,
+				TargetUserID: st.UserID,
+				Details: map[string]string{
+					"reason":  "refresh_token_reused",
+					"session": st.SessionID,
+				},
+			})
+		}
+		return models.AuthTokens{}, modelerrors.ErrUnauthorized
+	}
+
+	return a.webTokens(ctx, st.UserID, st.SessionID, newToken, st.ExpiresAt)
+}
+
+// EndWebSession — выход из веб-приложения: отзывает сессию refresh token'а.
+// Неизвестный или уже отозванный токен — не ошибка, cookie всё равно будут стёрты.
+func (a *AuthUsecase) EndWebSession(ctx context.Context, refreshToken string) error {
+	ctx, span := startSpan(ctx, "EndWebSession")
+	defer span.End()
+
+	st, err := a.repo.GetSessionByRefreshHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, modelerrors.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if !st.Current {
+		return nil
+	}
+
+	revoked, err := a.repo.RevokeSession(ctx, st.SessionID)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		a.audit.Record(ctx, models.AuditEvent{
+			Type:         models.AuditSessionsRevoked,
+			ActorUserID:  st.UserID,
+			TargetUserID: st.UserID,
+			Details: map[string]string{
+				"reason":  "logout",
+				"session": st.SessionID,
+			},
+		})
+	}
+	return nil
+}
+
+func (a *AuthUsecase) webTokens(ctx context.Context, userID int32, sessionID, refreshToken string, expiresAt time.Time) (models.AuthTokens, error) {
+	accessToken, expInSec, err := a.signToken(ctx, userID, sessionID)
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+	return models.AuthTokens{
+		AccessToken:         accessToken,
+		ExpiresInSec:        expInSec,
+		RefreshToken:        refreshToken,
+		RefreshExpiresInSec: int64(time.Until(expiresAt).Seconds()),
+	}, nil
+}
+
+// newRefreshToken — случайный токен для cookie и его sha256 для хранения.
+func newRefreshToken() (token string, hash []byte, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, fmt.Errorf("read refresh token: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, hashRefreshToken(token), nil
+}
+
+func hashRefreshToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
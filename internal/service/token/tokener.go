@@ -49,8 +49,8 @@ type JwtClaims struct {
 	jwt.RegisteredClaims
 }
 
-// CreateToken — соответствует твоему интерфейсу Tokener.CreateToken(...)
-func (t *Tokener) Token(userID int32) (accessToken string, expInSec int64, err error) {
+// Token выпускает access token для сессии sessionID (кладётся в jti).
+func (t *Tokener) Token(userID int32, sessionID string) (accessToken string, expInSec int64, err error) {
 	now := time.Now()
 
 	claims := JwtClaims{
@@ -61,6 +61,7 @@ func (t *Tokener) Token(userID int32) (accessToken string, expInSec int64, err e
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now.Add(-t.clockSkew)),
 			ExpiresAt: jwt.NewNumericDate(now.Add(t.ttl)),
+			ID:        sessionID,
 		},
 	}
 
@@ -72,3 +73,25 @@ func (t *Tokener) Token(userID int32) (accessToken string, expInSec int64, err e
 
 	return s, int64(t.ttl.Seconds()), nil
 }
+
+// Parse проверяет подпись, issuer и сроки токена и возвращает userID и sessionID (jti).
+func (t *Tokener) Parse(accessToken string) (userID int32, sessionID string, err error) {
+	var claims JwtClaims
+	_, err = jwt.ParseWithClaims(accessToken, &claims,
+		func(*jwt.Token) (any, error) { return t.secret, nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(t.issuer),
+		jwt.WithLeeway(t.clockSkew),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return 0, "", ErrExpiredToken
+		}
+		return 0, "", fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+	if claims.UserID <= 0 || claims.ID == "" {
+		return 0, "", ErrInvalidToken
+	}
+	return claims.UserID, claims.ID, nil
+}
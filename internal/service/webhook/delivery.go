@@ -0,0 +1,223 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/logger"
+)
+
+const (
+	defaultPollInterval = 2 * time.Second
+	defaultBatchSize    = 50
+	defaultMaxAttempts  = 8
+	defaultTimeout      = 10 * time.Second
+
+	baseBackoff = 10 * time.Second
+	maxBackoff  = time.Hour
+
+	// тело ответа получателя не нужно, но дочитываем немного ради keep-alive
+	maxResponseDrain = 64 << 10
+)
+
+type WorkerStore interface {
+	ClaimWebhookDeliveries(ctx context.Context, limit int32, lease time.Duration) ([]models.WebhookJob, error)
+	MarkWebhookDelivered(ctx context.Context, id int64, statusCode int32) error
+	MarkWebhookDeliveryFailed(ctx context.Context, id int64, statusCode int32, lastErr string, nextAttemptAt time.Time) error
+	MarkWebhookDeliveryDead(ctx context.Context, id int64, statusCode int32, lastErr string) error
+}
+
+// payload — тело POST. event_id одинаков во всех повторах: получатель дедуплицирует по нему.
+type payload struct {
+	EventID int64           `json:"event_id"`
+	Type    string          `json:"type"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Worker отправляет доставки из журнала: 2xx — доставлено, иначе повтор
+// с экспоненциальным backoff, после maxAttempts — статус failed.
+type Worker struct {
+	store  WorkerStore
+	client *http.Client
+
+	pollInterval time.Duration
+	batchSize    int32
+	maxAttempts  int32
+	now          func() time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type WorkerDeps struct {
+	Store WorkerStore
+
+	PollInterval time.Duration
+	MaxAttempts  int32
+	Timeout      time.Duration // на один запрос
+}
+
+func NewWorker(deps WorkerDeps) *Worker {
+	w := &Worker{
+		store:        deps.Store,
+		pollInterval: deps.PollInterval,
+		batchSize:    defaultBatchSize,
+		maxAttempts:  deps.MaxAttempts,
+		now:          time.Now,
+	}
+	if w.pollInterval <= 0 {
+		w.pollInterval = defaultPollInterval
+	}
+	if w.maxAttempts <= 0 {
+		w.maxAttempts = defaultMaxAttempts
+	}
+	timeout := deps.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	w.client = &http.Client{
+		Timeout: timeout,
+		// редирект мог бы увести подписанный запрос на чужой адрес
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return w
+}
+
+func (w *Worker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		w.run(ctx)
+	}()
+}
+
+func (w *Worker) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+}
+
+func (w *Worker) run(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := w.deliverBatch(ctx)
+			if err != nil {
+				logger.Log.Errorf("webhook: deliver batch: %s", err)
+			}
+			if err != nil || n < int(w.batchSize) {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) deliverBatch(ctx context.Context) (int, error) {
+	if ctx.Err() != nil {
+		return 0, nil
+	}
+
+	lease := w.client.Timeout * time.Duration(w.batchSize)
+	jobs, err := w.store.ClaimWebhookDeliveries(ctx, w.batchSize, lease)
+	if err != nil {
+		return 0, fmt.Errorf("claim: %w", err)
+	}
+
+	// ошибка записи одной доставки не должна оставлять остальные под арендой до её истечения
+	storeCtx := context.WithoutCancel(ctx)
+	var errs []error
+	for _, job := range jobs {
+		statusCode, sendErr := w.send(ctx, job)
+		if err := w.settle(storeCtx, job.Delivery, statusCode, sendErr); err != nil {
+			errs = append(errs, fmt.Errorf("settle delivery %d: %w", job.Delivery.ID, err))
+		}
+	}
+	return len(jobs), errors.Join(errs...)
+}
+
+// send возвращает код ответа (0 — ответа не было) и ошибку, если доставка не удалась.
+func (w *Worker) send(ctx context.Context, job models.WebhookJob) (int32, error) {
+	d := job.Delivery
+	body, err := json.Marshal(payload{
+		EventID: d.EventID,
+		Type:    d.EventType,
+		Data:    json.RawMessage(d.Payload),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	ts := w.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "BotTrade-Webhooks/1")
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderSignature, Sign(job.Secret, ts, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseDrain))
+
+	code := int32(resp.StatusCode)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return code, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return code, nil
+}
+
+func (w *Worker) settle(ctx context.Context, d models.WebhookDelivery, statusCode int32, sendErr error) error {
+	if sendErr == nil {
+		return w.store.MarkWebhookDelivered(ctx, d.ID, statusCode)
+	}
+
+	if d.Attempts >= w.maxAttempts {
+		logger.Log.Errorf("webhook: delivery %d (webhook %d, %s) failed after %d attempts: %s",
+			d.ID, d.WebhookID, d.EventType, d.Attempts, sendErr)
+		return w.store.MarkWebhookDeliveryDead(ctx, d.ID, statusCode, sendErr.Error())
+	}
+
+	delay := backoff(d.Attempts)
+	logger.Log.Warnf("webhook: delivery %d (webhook %d), attempt %d, retry in %s: %s",
+		d.ID, d.WebhookID, d.Attempts, delay, sendErr)
+	return w.store.MarkWebhookDeliveryFailed(ctx, d.ID, statusCode, sendErr.Error(), w.now().Add(delay))
+}
+
+// backoff: 10s, 20s, 40s, ... но не больше maxBackoff.
+func backoff(attempt int32) time.Duration {
+	delay := baseBackoff
+	for i := int32(1); i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
@@ -0,0 +1,278 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+const testSecret = "whsec_test"
+
+type settled struct {
+	statusCode    int32
+	lastErr       string
+	nextAttemptAt time.Time
+	dead          bool
+	delivered     bool
+}
+
+// fakeStore отдаёт заданные доставки один раз и запоминает, чем они закончились.
+type fakeStore struct {
+	mu      sync.Mutex
+	jobs    []models.WebhookJob
+	results map[int64]settled
+	failIDs map[int64]bool // Mark* по этим доставкам возвращают ошибку
+}
+
+func newFakeStore(jobs ...models.WebhookJob) *fakeStore {
+	return &fakeStore{jobs: jobs, results: map[int64]settled{}, failIDs: map[int64]bool{}}
+}
+
+var errStoreDown = errors.New("store down")
+
+func (s *fakeStore) ClaimWebhookDeliveries(_ context.Context, limit int32, _ time.Duration) ([]models.WebhookJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := min(int(limit), len(s.jobs))
+	jobs := s.jobs[:n]
+	s.jobs = s.jobs[n:]
+	return jobs, nil
+}
+
+func (s *fakeStore) MarkWebhookDelivered(_ context.Context, id int64, statusCode int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failIDs[id] {
+		return errStoreDown
+	}
+	s.results[id] = settled{statusCode: statusCode, delivered: true}
+	return nil
+}
+
+func (s *fakeStore) MarkWebhookDeliveryFailed(_ context.Context, id int64, statusCode int32, lastErr string, next time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failIDs[id] {
+		return errStoreDown
+	}
+	s.results[id] = settled{statusCode: statusCode, lastErr: lastErr, nextAttemptAt: next}
+	return nil
+}
+
+func (s *fakeStore) MarkWebhookDeliveryDead(_ context.Context, id int64, statusCode int32, lastErr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failIDs[id] {
+		return errStoreDown
+	}
+	s.results[id] = settled{statusCode: statusCode, lastErr: lastErr, dead: true}
+	return nil
+}
+
+func testJob(id int64, url string, attempts int32) models.WebhookJob {
+	return models.WebhookJob{
+		Delivery: models.WebhookDelivery{
+			ID:        id,
+			WebhookID: 1,
+			EventID:   100 + id,
+			EventType: models.EventUserRegistered,
+			Payload:   []byte(`{"user_id":7,"email":"a@b.c"}`),
+			Attempts:  attempts,
+		},
+		URL:    url,
+		Secret: testSecret,
+	}
+}
+
+func TestWorkerDeliversSignedPayload(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	got := make(chan received, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- received{header: r.Header.Clone(), body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	store := newFakeStore(testJob(1, srv.URL, 1))
+	w := NewWorker(WorkerDeps{Store: store})
+
+	if _, err := w.deliverBatch(context.Background()); err != nil {
+		t.Fatalf("deliverBatch: %v", err)
+	}
+
+	r := <-got
+	err := Verify(testSecret, r.header.Get(HeaderTimestamp), r.header.Get(HeaderSignature), r.body, time.Now(), DefaultTolerance)
+	if err != nil {
+		t.Fatalf("receiver rejected signature: %v", err)
+	}
+	if h := r.header.Get(HeaderEvent); h != models.EventUserRegistered {
+		t.Errorf("%s = %q", HeaderEvent, h)
+	}
+	if h := r.header.Get(HeaderDelivery); h != "1" {
+		t.Errorf("%s = %q, want 1", HeaderDelivery, h)
+	}
+
+	var p payload
+	if err := json.Unmarshal(r.body, &p); err != nil {
+		t.Fatalf("unmarshal body: %v", err)
+	}
+	if p.EventID != 101 || p.Type != models.EventUserRegistered || string(p.Data) != `{"user_id":7,"email":"a@b.c"}` {
+		t.Errorf("unexpected payload: %+v", p)
+	}
+
+	if res := store.results[1]; !res.delivered || res.statusCode != http.StatusNoContent {
+		t.Errorf("delivery not marked delivered: %+v", res)
+	}
+}
+
+func TestWorkerRetriesWithBackoff(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := newFakeStore(testJob(1, srv.URL, 1), testJob(2, srv.URL, 3))
+	w := NewWorker(WorkerDeps{Store: store})
+	w.now = func() time.Time { return now }
+
+	if _, err := w.deliverBatch(context.Background()); err != nil {
+		t.Fatalf("deliverBatch: %v", err)
+	}
+
+	for id, wantDelay := range map[int64]time.Duration{1: 10 * time.Second, 2: 40 * time.Second} {
+		res := store.results[id]
+		if res.delivered || res.dead {
+			t.Fatalf("delivery %d: want retry, got %+v", id, res)
+		}
+		if res.statusCode != http.StatusInternalServerError {
+			t.Errorf("delivery %d: status code %d", id, res.statusCode)
+		}
+		if got := res.nextAttemptAt.Sub(now); got != wantDelay {
+			t.Errorf("delivery %d: retry in %s, want %s", id, got, wantDelay)
+		}
+	}
+}
+
+func TestWorkerGivesUpAfterMaxAttempts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	store := newFakeStore(testJob(1, srv.URL, 3))
+	w := NewWorker(WorkerDeps{Store: store, MaxAttempts: 3})
+
+	if _, err := w.deliverBatch(context.Background()); err != nil {
+		t.Fatalf("deliverBatch: %v", err)
+	}
+	if res := store.results[1]; !res.dead || res.statusCode != http.StatusBadGateway {
+		t.Errorf("delivery not marked failed: %+v", res)
+	}
+}
+
+func TestWorkerSettlesWholeBatchOnStoreError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	store := newFakeStore(testJob(1, srv.URL, 1), testJob(2, srv.URL, 1), testJob(3, srv.URL, 1))
+	store.failIDs[1] = true
+	w := NewWorker(WorkerDeps{Store: store})
+
+	n, err := w.deliverBatch(context.Background())
+	if !errors.Is(err, errStoreDown) {
+		t.Fatalf("deliverBatch error = %v, want %v", err, errStoreDown)
+	}
+	if n != 3 {
+		t.Errorf("deliverBatch n = %d, want 3", n)
+	}
+	for _, id := range []int64{2, 3} {
+		if res := store.results[id]; !res.delivered {
+			t.Errorf("delivery %d not settled after earlier store error: %+v", id, res)
+		}
+	}
+}
+
+func TestWorkerDoesNotFollowRedirects(t *testing.T) {
+	var hits int
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits++
+	}))
+	defer target.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer srv.Close()
+
+	store := newFakeStore(testJob(1, srv.URL, 1))
+	w := NewWorker(WorkerDeps{Store: store})
+
+	if _, err := w.deliverBatch(context.Background()); err != nil {
+		t.Fatalf("deliverBatch: %v", err)
+	}
+	if hits != 0 {
+		t.Errorf("redirect target was called %d times", hits)
+	}
+	if res := store.results[1]; res.delivered || res.statusCode != http.StatusTemporaryRedirect {
+		t.Errorf("redirect must be a failed attempt: %+v", res)
+	}
+}
+
+func TestVerifyRejectsReplayAndTampering(t *testing.T) {
+	body := []byte(`{"event_id":1}`)
+	now := time.Now()
+	ts := now.Add(-10 * time.Minute).Unix()
+	sig := Sign(testSecret, ts, body)
+
+	if err := Verify(testSecret, strconv.FormatInt(ts, 10), sig, body, now, DefaultTolerance); err != ErrStaleTimestamp {
+		t.Errorf("old delivery: got %v, want %v", err, ErrStaleTimestamp)
+	}
+
+	ts = now.Unix()
+	sig = Sign(testSecret, ts, body)
+	if err := Verify(testSecret, strconv.FormatInt(ts, 10), sig, []byte(`{"event_id":2}`), now, DefaultTolerance); err != ErrBadSignature {
+		t.Errorf("tampered body: got %v, want %v", err, ErrBadSignature)
+	}
+	if err := Verify("other", strconv.FormatInt(ts, 10), sig, body, now, DefaultTolerance); err != ErrBadSignature {
+		t.Errorf("wrong secret: got %v, want %v", err, ErrBadSignature)
+	}
+	if err := Verify(testSecret, strconv.FormatInt(ts, 10), sig, body, now, DefaultTolerance); err != nil {
+		t.Errorf("valid delivery: %v", err)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	cases := []struct {
+		filter []string
+		event  string
+		want   bool
+	}{
+		{nil, models.EventUserBlocked, true},
+		{[]string{"*"}, models.EventTelegramLinked, true},
+		{[]string{"user.*"}, models.EventUserRegistered, true},
+		{[]string{"user.*"}, models.EventTelegramLinked, false},
+		{[]string{models.EventPasswordChanged}, models.EventPasswordChanged, true},
+		{[]string{models.EventPasswordChanged}, models.EventUserBlocked, false},
+		{[]string{"user*"}, models.EventUserBlocked, false},
+	}
+	for _, c := range cases {
+		if got := Matches(c.filter, c.event); got != c.want {
+			t.Errorf("Matches(%v, %q) = %v, want %v", c.filter, c.event, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+type FanoutStore interface {
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	EnqueueWebhookDelivery(ctx context.Context, webhookID int64, event models.OutboxEvent) error
+}
+
+// Fanout — outbox.Publisher: раскладывает событие по подходящим подпискам в журнал доставок.
+// Постановка идемпотентна, поэтому повтор события relay-ем безопасен.
+type Fanout struct {
+	store FanoutStore
+}
+
+func NewFanout(store FanoutStore) *Fanout {
+	return &Fanout{store: store}
+}
+
+func (f *Fanout) Publish(ctx context.Context, event models.OutboxEvent) error {
+	webhooks, err := f.store.ListWebhooks(ctx)
+	if err != nil {
+		return fmt.Errorf("list webhooks: %w", err)
+	}
+	for _, w := range webhooks {
+		if !Matches(w.EventTypes, event.Type) {
+			continue
+		}
+		if err := f.store.EnqueueWebhookDelivery(ctx, w.ID, event); err != nil {
+			return fmt.Errorf("enqueue delivery for webhook %d: %w", w.ID, err)
+		}
+	}
+	return nil
+}
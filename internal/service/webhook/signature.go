@@ -0,0 +1,56 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Заголовки доставки. Подпись: "v1=" + hex(HMAC-SHA256(secret, "<timestamp>.<body>")),
+// timestamp — unix-секунды отправки. Получатель сверяет подпись и отбрасывает
+// доставки со старым timestamp, чтобы перехваченный запрос нельзя было повторить.
+const (
+	HeaderEvent     = "X-BotTrade-Event"
+	HeaderDelivery  = "X-BotTrade-Delivery"
+	HeaderTimestamp = "X-BotTrade-Timestamp"
+	HeaderSignature = "X-BotTrade-Signature"
+
+	signatureVersion = "v1="
+
+	// DefaultTolerance — рекомендуемое окно проверки timestamp у получателя.
+	DefaultTolerance = 5 * time.Minute
+)
+
+var (
+	ErrBadSignature    = errors.New("webhook signature mismatch")
+	ErrStaleTimestamp  = errors.New("webhook timestamp outside tolerance")
+	ErrBadTimestampHdr = errors.New("webhook timestamp header is invalid")
+)
+
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signatureVersion + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify — проверка на стороне получателя (используется и в тестах).
+func Verify(secret, timestampHeader, signatureHeader string, body []byte, now time.Time, tolerance time.Duration) error {
+	ts, err := strconv.ParseInt(strings.TrimSpace(timestampHeader), 10, 64)
+	if err != nil {
+		return ErrBadTimestampHdr
+	}
+	if d := now.Sub(time.Unix(ts, 0)); d > tolerance || d < -tolerance {
+		return ErrStaleTimestamp
+	}
+	expected := Sign(secret, ts, body)
+	if !hmac.Equal([]byte(expected), []byte(strings.TrimSpace(signatureHeader))) {
+		return ErrBadSignature
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+
+	modelerrors "github.com/IvanOplesnin/BotTradeService.git/internal/domain/errors"
+	"github.com/IvanOplesnin/BotTradeService.git/internal/domain/models"
+)
+
+type Store interface {
+	CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	ListWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error)
+}
+
+// AuditSink — журнал событий безопасности.
+type AuditSink interface {
+	Record(ctx context.Context, event models.AuditEvent)
+}
+
+// Service — управление подписками из AdminService.
+type Service struct {
+	store Store
+	audit AuditSink
+}
+
+type ServiceDeps struct {
+	Store Store
+	Audit AuditSink
+}
+
+func New(deps ServiceDeps) *Service {
+	return &Service{
+		store: deps.Store,
+		audit: deps.Audit,
+	}
+}
+
+// CreateWebhook сохраняет подписку. Пустой секрет генерируется — вызывающий показывает его один раз.
+func (s *Service) CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	if webhook.Secret == "" {
+		secret, err := newSecret()
+		if err != nil {
+			return models.Webhook{}, err
+		}
+		webhook.Secret = secret
+	}
+
+	created, err := s.store.CreateWebhook(ctx, webhook)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	s.audit.Record(ctx, models.AuditEvent{
+		Type: models.AuditAdminWebhookCreated,
+		Details: map[string]string{
+			"webhook_id":  strconv.FormatInt(created.ID, 10),
+			"url":         created.URL,
+			"event_types": strings.Join(created.EventTypes, ","),
+		},
+	})
+	return created, nil
+}
+
+func (s *Service) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	return s.store.ListWebhooks(ctx)
+}
+
+func (s *Service) DeleteWebhook(ctx context.Context, id int64) error {
+	if err := s.store.DeleteWebhook(ctx, id); err != nil {
+		if errors.Is(err, modelerrors.ErrNoRows) {
+			return modelerrors.ErrWebhookNotFound
+		}
+		return err
+	}
+	s.audit.Record(ctx, models.AuditEvent{
+		Type:    models.AuditAdminWebhookDeleted,
+		Details: map[string]string{"webhook_id": strconv.FormatInt(id, 10)},
+	})
+	return nil
+}
+
+func (s *Service) ListWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	return s.store.ListWebhookDeliveries(ctx, filter)
+}
+
+// Matches: пустой фильтр — все события, "*" — тоже все, "user.*" — по префиксу.
+func Matches(eventTypes []string, eventType string) bool {
+	if len(eventTypes) == 0 {
+		return true
+	}
+	for _, t := range eventTypes {
+		if t == eventType || t == "*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(t, "*"); ok && strings.HasSuffix(prefix, ".") &&
+			strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,10 @@
+// Package migrations встраивает SQL-миграции goose в бинарник.
+package migrations
+
+import "embed"
+
+//go:embed schema/*.sql
+var FS embed.FS
+
+// Dir — каталог миграций внутри FS.
+const Dir = "schema"
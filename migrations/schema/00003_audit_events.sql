@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Журнал событий безопасности. Только INSERT: UPDATE/DELETE запрещены триггером.
+-- user_id без FK — записи должны переживать удаление пользователя.
+CREATE TABLE IF NOT EXISTS audit_events (
+    id              BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    event_type      TEXT    NOT NULL,           -- 'user.login_succeeded', 'admin.user_blocked', ...
+
+    actor_user_id   INTEGER,                    -- кто сделал (NULL — аноним или бот)
+    actor_bot_id    TEXT,
+    target_user_id  INTEGER,                    -- над кем
+
+    ip              TEXT,
+    user_agent      TEXT,
+    request_id      TEXT,
+    details         JSONB   NOT NULL DEFAULT '{}'::jsonb,
+
+    occurred_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_target_user_id
+    ON audit_events(target_user_id, occurred_at);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor_user_id
+    ON audit_events(actor_user_id, occurred_at);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_occurred_at
+    ON audit_events(occurred_at);
+
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS audit_events;
+DROP FUNCTION IF EXISTS audit_events_append_only();
+-- +goose StatementEnd
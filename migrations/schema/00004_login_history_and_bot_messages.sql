@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- История успешных входов пользователя.
+CREATE TABLE IF NOT EXISTS login_history (
+    id                  BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id             INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    "method"            TEXT    NOT NULL,   -- 'password' | 'telegram'
+    ip                  TEXT,
+    user_agent          TEXT,
+    device_name         TEXT    NOT NULL,   -- 'Chrome, Windows'
+    device_fingerprint  TEXT    NOT NULL,   -- хэш клиента и ОС без версий
+
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_history_user_id
+    ON login_history(user_id, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_login_history_user_device
+    ON login_history(user_id, device_fingerprint);
+
+CREATE INDEX IF NOT EXISTS idx_login_history_user_ip
+    ON login_history(user_id, ip);
+
+-- Очередь сообщений для Telegram-бота (бот забирает через PullBotMessages).
+CREATE TABLE IF NOT EXISTS bot_messages (
+    id            BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    chat_id       BIGINT  NOT NULL,
+    text          TEXT    NOT NULL,
+
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_bot_messages_pending
+    ON bot_messages(id)
+    WHERE delivered_at IS NULL;
+
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS bot_messages;
+DROP TABLE IF EXISTS login_history;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Transactional outbox: доменные события пишутся в одной транзакции с изменением состояния,
+-- relay публикует их в шину (at-least-once). Получатель дедуплицирует по id.
+CREATE TABLE IF NOT EXISTS outbox (
+    id               BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    event_type       TEXT  NOT NULL,   -- 'user.registered', 'telegram.linked', ...
+    aggregate_id     TEXT  NOT NULL,   -- id пользователя
+    payload          JSONB NOT NULL,
+
+    attempts         INTEGER     NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    locked_until     TIMESTAMPTZ,      -- аренда relay-воркером на время публикации
+    last_error       TEXT,
+
+    published_at     TIMESTAMPTZ,
+    dead_at          TIMESTAMPTZ,      -- исчерпаны попытки (dead letter), нужен разбор вручную
+
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending
+    ON outbox(next_attempt_at, id)
+    WHERE published_at IS NULL AND dead_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_dead
+    ON outbox(id)
+    WHERE dead_at IS NOT NULL;
+
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbox;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Подписки на доменные события для интеграций без NATS.
+CREATE TABLE IF NOT EXISTS webhooks (
+    id           BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    url          TEXT   NOT NULL,
+    event_types  TEXT[] NOT NULL DEFAULT '{}',  -- пусто — все события; 'user.*' — по префиксу
+    secret       TEXT   NOT NULL,               -- ключ HMAC-подписи доставок
+
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Журнал доставок: одна строка на пару (подписка, событие outbox).
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id                BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    webhook_id        BIGINT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_id          BIGINT NOT NULL,   -- outbox.id
+    event_type        TEXT   NOT NULL,
+    payload           JSONB  NOT NULL,
+
+    status            TEXT        NOT NULL DEFAULT 'pending',  -- 'pending' | 'delivered' | 'failed'
+    attempts          INTEGER     NOT NULL DEFAULT 0,
+    next_attempt_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    locked_until      TIMESTAMPTZ,
+    last_status_code  INTEGER,
+    last_error        TEXT,
+    delivered_at      TIMESTAMPTZ,
+
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT uq_webhook_deliveries_event UNIQUE (webhook_id, event_id),
+    CONSTRAINT chk_webhook_deliveries_status CHECK (status IN ('pending', 'delivered', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending
+    ON webhook_deliveries(next_attempt_at, id)
+    WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id
+    ON webhook_deliveries(webhook_id, id DESC);
+
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+-- +goose StatementEnd
@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Email как идентификатор: регистр не важен. email остаётся в том виде, как его ввели
+-- (для писем и отображения), уникальность — по email_normalized.
+-- Здесь только lower(): IDNA и правила провайдеров досчитывает `migrate normalize-emails`.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_normalized TEXT;
+
+UPDATE users
+SET email_normalized = lower(btrim(email))
+WHERE email_normalized IS NULL;
+
+-- Дубли не сливаем молча: миграция падает со списком, аккаунты разбирают вручную.
+DO $$
+DECLARE
+    collisions TEXT;
+BEGIN
+    SELECT string_agg(format('%s: user_ids %s', email_normalized, ids), E'\n')
+    INTO collisions
+    FROM (
+        SELECT email_normalized, array_agg(id ORDER BY id)::TEXT AS ids
+        FROM users
+        GROUP BY email_normalized
+        HAVING count(*) > 1
+    ) c;
+
+    IF collisions IS NOT NULL THEN
+        RAISE EXCEPTION 'users.email collisions after normalization, resolve them before migrating'
+            USING DETAIL = collisions,
+                  HINT = 'merge or rename the listed accounts, then run migrate up again';
+    END IF;
+END
+$$;
+
+ALTER TABLE users ALTER COLUMN email_normalized SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_users_email_normalized
+    ON users(email_normalized);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS uq_users_email_normalized;
+ALTER TABLE users DROP COLUMN IF EXISTS email_normalized;
+
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Web-сессии (HttpOnly cookie): refresh token живёт у браузера, в базе — только sha256.
+-- expires_at у такой сессии — срок refresh token'а, access token'ы на неё выпускаются короткими.
+-- Предыдущий hash хранится, чтобы распознать повторное использование уже ротированного токена.
+ALTER TABLE user_sessions
+    ADD COLUMN IF NOT EXISTS refresh_token_hash      BYTEA,
+    ADD COLUMN IF NOT EXISTS prev_refresh_token_hash BYTEA;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_user_sessions_refresh_token_hash
+    ON user_sessions(refresh_token_hash)
+    WHERE refresh_token_hash IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_prev_refresh_token_hash
+    ON user_sessions(prev_refresh_token_hash)
+    WHERE prev_refresh_token_hash IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_user_sessions_prev_refresh_token_hash;
+DROP INDEX IF EXISTS uq_user_sessions_refresh_token_hash;
+ALTER TABLE user_sessions
+    DROP COLUMN IF EXISTS prev_refresh_token_hash,
+    DROP COLUMN IF EXISTS refresh_token_hash;
+
+-- +goose StatementEnd
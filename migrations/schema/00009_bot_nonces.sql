@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Использованные nonce подписанных запросов ботов. Строка живёт, пока timestamp запроса
+-- ещё проходит проверку окна (expires_at); повтор той же пары (bot_id, nonce) — replay.
+CREATE TABLE IF NOT EXISTS bot_nonces (
+    bot_id     TEXT        NOT NULL,
+    nonce      TEXT        NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (bot_id, nonce)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bot_nonces_expires_at ON bot_nonces(expires_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS bot_nonces;
+
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Последний запуск фонового job-а. Общая для всех реплик: advisory lock исключает только
+-- одновременные запуски, а по last_run_at реплика видит, что job за этот интервал уже отработал.
+CREATE TABLE IF NOT EXISTS scheduler_job_runs (
+    name        TEXT        PRIMARY KEY,
+    last_run_at TIMESTAMPTZ NOT NULL
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS scheduler_job_runs;
+
+-- +goose StatementEnd
@@ -6,4 +6,4 @@ set -a
 set +a
 
 
-go run cmd/grpc_server/main.go
\ No newline at end of file
+go run cmd/grpc_server/main.go "$@"
\ No newline at end of file